@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/cli"
@@ -19,7 +18,6 @@ func main() {
 	cli.SetVersion(version, commit, date)
 
 	if err := cli.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ReportError(err))
 	}
 }
@@ -2,21 +2,59 @@ package app
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
 )
 
+// LicenseLookup finds the licenses of an artifact, such as LicenseService.
+type LicenseLookup interface {
+	// Licenses returns the licenses of groupId:artifactId:version and the
+	// coordinates of the pom declaring them, see LicenseService.Licenses.
+	Licenses(groupID, artifactID, version string) ([]*domain.License, string, error)
+}
+
+// LicenseCheck is the outcome of checking an artifact against the license policy.
+type LicenseCheck struct {
+	Licenses []*domain.License
+	Verdict  domain.LicenseVerdict
+
+	// Problem explains why the licenses could not be read; empty when they were
+	Problem string
+
+	// Exception is the policy exception accepting the artifact, if any
+	Exception *domain.LicenseException
+
+	// Recorded tells whether Exception was added to the policy by this check
+	Recorded bool
+}
+
 // AddDependencyService handles adding dependencies to a project.
 type AddDependencyService struct {
-	resolver      domain.Resolver
-	pomRepository domain.PomRepository
+	resolver domain.Resolver
+	projectLock
+
+	// licenses and policies enforce the license policy of the project; nil
+	// leaves licenses unchecked
+	licenses LicenseLookup
+	policies domain.LicensePolicyStore
 }
 
-// NewAddDependencyService creates a new AddDependencyService.
-func NewAddDependencyService(resolver domain.Resolver, pomRepository domain.PomRepository) *AddDependencyService {
+// NewAddDependencyService creates a new AddDependencyService. Unless licenses or
+// policies is nil, Add enforces the license policy of the project.
+func NewAddDependencyService(
+	resolver domain.Resolver,
+	pomRepository domain.PomRepository,
+	locker domain.Locker,
+	licenses LicenseLookup,
+	policies domain.LicensePolicyStore,
+) *AddDependencyService {
 	return &AddDependencyService{
-		resolver:      resolver,
-		pomRepository: pomRepository,
+		resolver:    resolver,
+		projectLock: newProjectLock(pomRepository, locker),
+		licenses:    licenses,
+		policies:    policies,
 	}
 }
 
@@ -27,18 +65,41 @@ type SearchResult struct {
 
 	// NeedsSelection indicates if user needs to select from multiple results
 	NeedsSelection bool
+
+	// Requested holds the coordinates given in the query, including type, classifier
+	// and version when present; nil when the query was a search term
+	Requested *domain.Dependency
 }
 
 // Search searches for dependencies matching the query.
+// The query is a search term, or coordinates in the syntax of domain.ParseCoordinates.
+// Coordinates with a version are used as given, without querying Maven Central.
 // Returns a SearchResult that may require user selection if multiple artifacts are found.
 func (s *AddDependencyService) Search(query string) (*SearchResult, error) {
+	var requested *domain.Dependency
+	if strings.Contains(query, ":") {
+		dep, err := domain.ParseCoordinates(query)
+		if err != nil {
+			return nil, err
+		}
+		requested = dep
+
+		if dep.Version != "" {
+			return &SearchResult{
+				Results:   []*domain.ArtifactSearchResult{domain.NewArtifactSearchResult(dep.GroupID, dep.ArtifactID, dep.Version, 100.0)},
+				Requested: requested,
+			}, nil
+		}
+		query = dep.Coordinates()
+	}
+
 	results, err := s.resolver.Resolve(query)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("no artifacts found for query: %s", query)
+		return nil, &domain.NotFoundError{Kind: "artifact", Name: query}
 	}
 
 	// If there's only one result or it was an exact match, no selection needed
@@ -47,21 +108,31 @@ func (s *AddDependencyService) Search(query string) (*SearchResult, error) {
 	return &SearchResult{
 		Results:        results,
 		NeedsSelection: needsSelection,
+		Requested:      requested,
 	}, nil
 }
 
-// Add adds a dependency to the pom.xml.
-// The artifact parameter should be an ArtifactSearchResult (from Search).
-// The scope parameter specifies the dependency scope (compile, test, provided, runtime).
-func (s *AddDependencyService) Add(artifact *domain.ArtifactSearchResult, scope string) error {
-	// Convert artifact to dependency
-	dep, err := artifact.ToDependency(scope)
-	if err != nil {
+// Add adds a dependency to the pom.xml, typically built from an ArtifactSearchResult
+// returned by Search. A dependency with the same groupId, artifactId, type and
+// classifier is updated instead.
+// The dependency is validated first, see domain.Dependency.Validate.
+//
+// check is the result of CheckLicense for dep, or nil. An exception it records is
+// added to the license policy before the pom.xml is changed, so that a dependency
+// is never added without the exception accepting it.
+func (s *AddDependencyService) Add(dep *domain.Dependency, check *LicenseCheck) error {
+	if err := dep.Validate(false); err != nil {
 		return err
 	}
 
+	if check != nil && check.Recorded {
+		if err := s.policies.AddException(check.Exception); err != nil {
+			return fmt.Errorf("failed to record the license exception: %w", err)
+		}
+	}
+
 	// Check if dependency already exists
-	if s.pomRepository.HasDependency(dep.GroupID, dep.ArtifactID) {
+	if s.pomRepository.HasDependency(dep) {
 		// Update existing dependency (silent update)
 		if err := s.pomRepository.AddDependency(dep); err != nil {
 			return fmt.Errorf("failed to update dependency: %w", err)
@@ -81,7 +152,74 @@ func (s *AddDependencyService) Add(artifact *domain.ArtifactSearchResult, scope
 	return nil
 }
 
-// LoadPom loads the pom.xml from the specified path.
-func (s *AddDependencyService) LoadPom(path string) error {
-	return s.pomRepository.Load(path)
+// CheckLicense checks the licenses of dep against the license policy of the
+// project, if it has one; the returned LicenseCheck is nil otherwise. Looking up
+// the licenses may download poms, so call it before LoadPom.
+//
+// An artifact whose license is denied, or unknown when the policy denies unknown
+// licenses, is refused with a *LicensePolicyError unless a justification is
+// given. The justification then becomes an exception for Add to record in the
+// policy, as it does for artifacts the policy only warns about.
+func (s *AddDependencyService) CheckLicense(dep *domain.Dependency, justification string) (*LicenseCheck, error) {
+	if s.licenses == nil || s.policies == nil {
+		return nil, nil
+	}
+	policy, err := s.policies.Load()
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	check := &LicenseCheck{}
+	check.Licenses, _, err = s.licenses.Licenses(dep.GroupID, dep.ArtifactID, dep.Version)
+	if err != nil {
+		check.Problem = err.Error()
+	}
+	check.Verdict = policy.Evaluate(check.Licenses)
+	if check.Verdict == domain.LicenseAllowed {
+		return check, nil
+	}
+
+	if check.Exception = policy.Exception(dep.GroupID, dep.ArtifactID); check.Exception != nil {
+		return check, nil
+	}
+
+	licenses := make([]string, len(check.Licenses))
+	for i, license := range check.Licenses {
+		licenses[i] = license.String()
+	}
+	if justification == "" {
+		if check.Verdict == domain.LicenseDenied || policy.DenyUnknown {
+			return check, &domain.LicensePolicyError{Artifact: dep.GroupID + ":" + dep.ArtifactID + ":" + dep.Version, Verdict: check.Verdict, Licenses: licenses}
+		}
+		return check, nil
+	}
+
+	check.Exception = &domain.LicenseException{
+		Artifact:      dep.GroupID + ":" + dep.ArtifactID,
+		Licenses:      licenses,
+		Justification: justification,
+		Added:         time.Now().UTC(),
+	}
+	check.Recorded = true
+	return check, nil
+}
+
+// EnsureProfile adds the profile to the pom.xml unless a profile with the same id
+// exists, and reports whether it was created. The activation of an existing
+// profile is left as is. The pom.xml is saved by Add.
+func (s *AddDependencyService) EnsureProfile(profile *domain.Profile) (bool, error) {
+	profiles, err := s.pomRepository.GetProfiles()
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range profiles {
+		if existing.ID == profile.ID {
+			return false, nil
+		}
+	}
+
+	if err := s.pomRepository.AddProfile(profile); err != nil {
+		return false, fmt.Errorf("failed to add profile: %w", err)
+	}
+	return true, nil
 }
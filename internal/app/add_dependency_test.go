@@ -0,0 +1,121 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// stubLicenseLookup is a LicenseLookup backed by a map of groupId:artifactId:version to licenses.
+type stubLicenseLookup map[string][]*domain.License
+
+func (l stubLicenseLookup) Licenses(groupID, artifactID, version string) ([]*domain.License, string, error) {
+	coordinates := groupID + ":" + artifactID + ":" + version
+	licenses, ok := l[coordinates]
+	if !ok {
+		return nil, "", &domain.NotFoundError{Kind: "pom", Name: coordinates}
+	}
+	return licenses, coordinates, nil
+}
+
+// stubLicensePolicyStore is a LicensePolicyStore kept in memory. AddException
+// fails with err when it is set.
+type stubLicensePolicyStore struct {
+	policy *domain.LicensePolicy
+	err    error
+}
+
+func (s *stubLicensePolicyStore) Load() (*domain.LicensePolicy, error) {
+	return s.policy, nil
+}
+
+func (s *stubLicensePolicyStore) AddException(exception *domain.LicenseException) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.policy.Exceptions = append(s.policy.Exceptions, exception)
+	return nil
+}
+
+func TestAddDependencyService_LicensePolicy(t *testing.T) {
+	licenses := stubLicenseLookup{
+		"org.example:apache:1.0": {{Name: "Apache-2.0", SPDX: "Apache-2.0"}},
+		"org.example:gpl:1.0":    {{Name: "GPL v3", SPDX: "GPL-3.0-only"}},
+		"org.example:custom:1.0": {{Name: "Custom"}},
+	}
+
+	tests := []struct {
+		name          string
+		artifactID    string
+		denyUnknown   bool
+		justification string
+		// storeErr makes recording the exception fail
+		storeErr error
+		verdict  domain.LicenseVerdict
+		refused  bool
+		recorded bool
+	}{
+		{name: "allowed", artifactID: "apache", verdict: domain.LicenseAllowed},
+		{name: "denied", artifactID: "gpl", verdict: domain.LicenseDenied, refused: true},
+		{name: "denied with justification", artifactID: "gpl", justification: "internal tool", verdict: domain.LicenseDenied, recorded: true},
+		{name: "unknown warns", artifactID: "custom", verdict: domain.LicenseUnknown},
+		{name: "unknown denied", artifactID: "custom", denyUnknown: true, verdict: domain.LicenseUnknown, refused: true},
+		{name: "unavailable", artifactID: "missing", denyUnknown: true, justification: "vendored", verdict: domain.LicenseUnknown, recorded: true},
+		{name: "exception not recorded", artifactID: "gpl", justification: "internal tool", storeErr: os.ErrPermission, verdict: domain.LicenseDenied, recorded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubLicensePolicyStore{policy: &domain.LicensePolicy{
+				Allowed:     []string{"Apache-2.0"},
+				Denied:      []string{"GPL-3.0-only"},
+				DenyUnknown: tt.denyUnknown,
+			}, err: tt.storeErr}
+			service := NewAddDependencyService(nil, xml.NewPomRepository(), fs.NewFileLocker(), licenses, store)
+
+			// The license is checked before the project is locked
+			dep := &domain.Dependency{GroupID: "org.example", ArtifactID: tt.artifactID, Version: "1.0", Scope: "compile"}
+			check, err := service.CheckLicense(dep, tt.justification)
+			require.NotNil(t, check)
+			assert.Equal(t, tt.verdict, check.Verdict)
+			assert.Equal(t, tt.recorded, check.Recorded)
+			if tt.refused {
+				var policyErr *domain.LicensePolicyError
+				require.ErrorAs(t, err, &policyErr)
+				return
+			}
+			require.NoError(t, err)
+
+			pomPath := loadTestPom(t, service, "app.xml")
+			err = service.Add(dep, check)
+			content, readErr := os.ReadFile(pomPath)
+			require.NoError(t, readErr)
+			if tt.storeErr != nil {
+				// Without its exception, the dependency is not added
+				assert.ErrorIs(t, err, tt.storeErr)
+				assert.NotContains(t, string(content), tt.artifactID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, string(content), "<artifactId>"+tt.artifactID+"</artifactId>")
+
+			if tt.recorded {
+				require.Len(t, store.policy.Exceptions, 1)
+				assert.Equal(t, "org.example:"+tt.artifactID, store.policy.Exceptions[0].Artifact)
+				assert.Equal(t, tt.justification, store.policy.Exceptions[0].Justification)
+
+				// The exception accepts the artifact from then on
+				check, err = service.CheckLicense(dep, "")
+				require.NoError(t, err)
+				assert.False(t, check.Recorded)
+				assert.Same(t, store.policy.Exceptions[0], check.Exception)
+			}
+		})
+	}
+}
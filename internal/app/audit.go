@@ -0,0 +1,219 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// AuditFinding is an artifact of the project affected by security advisories.
+type AuditFinding struct {
+	// Dependency is the artifact with the version the project uses
+	Dependency *domain.Dependency
+
+	// Path lists the artifacts leading to a transitive dependency, see GraphRequest;
+	// empty for direct dependencies
+	Path []string
+
+	// Advisories affect the version, ordered from the most severe
+	Advisories []*domain.Advisory
+
+	// SafeVersion is the lowest higher version affected by no known advisory;
+	// empty when no fix is known
+	SafeVersion string
+}
+
+// AuditReport is the outcome of AuditService.Audit.
+type AuditReport struct {
+	Findings []*AuditFinding
+
+	// Artifacts counts the artifacts checked
+	Artifacts int
+
+	// Unknown lists the dependencies whose version cannot be determined, and
+	// Unresolved the artifacts whose pom is not available locally, so their
+	// dependencies could not be checked
+	Unknown    []string
+	Unresolved []string
+}
+
+// AuditFix is the outcome of upgrading an AuditFinding.
+type AuditFix struct {
+	Finding *AuditFinding
+
+	// Fixed tells whether the version was upgraded to the safe version; if not,
+	// Reason explains why
+	Fixed  bool
+	Reason string
+}
+
+// AuditService checks the dependencies of a project against an advisory database
+// and upgrades the vulnerable ones.
+type AuditService struct {
+	projectLock
+
+	database domain.AdvisoryDatabase
+	graph    *ListDependenciesService
+}
+
+// NewAuditService creates a new AuditService. Like ListDependenciesService, it
+// reads the poms of parents, BOMs and dependencies from disk only.
+func NewAuditService(
+	pomRepository domain.PomRepository,
+	locker domain.Locker,
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+	database domain.AdvisoryDatabase,
+) *AuditService {
+	return &AuditService{
+		projectLock: newProjectLock(pomRepository, locker),
+		database:    database,
+		graph:       NewListDependenciesService(pomRepository, locator, load),
+	}
+}
+
+// Audit returns the dependencies affected by an advisory, in the order of the
+// dependency graph. Each artifact is checked at the version Maven picks, the
+// nearest to the project. Unless transitive is set, only the dependencies
+// declared by the project and its parents are checked.
+func (s *AuditService) Audit(transitive bool) (*AuditReport, error) {
+	graph, err := s.graph.Graph(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dependency graph: %w", err)
+	}
+
+	report := &AuditReport{}
+	if transitive {
+		report.Unresolved = graph.Unresolved
+	}
+	seen := make(map[string]bool)
+	for _, request := range graph.Requests {
+		dep := request.Dependency
+		if seen[dep.Key()] || (!transitive && len(request.Path) > 0) {
+			continue
+		}
+		seen[dep.Key()] = true
+		report.Artifacts++
+
+		if dep.Version == "" {
+			report.Unknown = append(report.Unknown, dep.ID())
+			continue
+		}
+
+		advisories, err := s.database.Advisories(dep.GroupID, dep.ArtifactID)
+		if err != nil {
+			return nil, err
+		}
+		finding := &AuditFinding{Dependency: dep, Path: request.Path}
+		for _, advisory := range advisories {
+			if advisory.Affects(dep.Version) {
+				finding.Advisories = append(finding.Advisories, advisory)
+			}
+		}
+		if len(finding.Advisories) == 0 {
+			continue
+		}
+
+		slices.SortStableFunc(finding.Advisories, func(a, b *domain.Advisory) int {
+			return slices.Index(domain.AdvisorySeverities, b.Severity) - slices.Index(domain.AdvisorySeverities, a.Severity)
+		})
+		finding.SafeVersion = safeVersion(dep.Version, advisories)
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report, nil
+}
+
+// safeVersion returns the lowest fixed version higher than version that none of
+// the advisories affects, or "" if there is none.
+func safeVersion(version string, advisories []*domain.Advisory) string {
+	var candidates []string
+	for _, advisory := range advisories {
+		for _, events := range advisory.Ranges {
+			for _, event := range events {
+				if event.Fixed != "" && domain.CompareVersions(event.Fixed, version) > 0 {
+					candidates = append(candidates, event.Fixed)
+				}
+			}
+		}
+	}
+	slices.SortFunc(candidates, domain.CompareVersions)
+
+	for _, candidate := range candidates {
+		if !slices.ContainsFunc(advisories, func(a *domain.Advisory) bool { return a.Affects(candidate) }) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Fix upgrades each finding to its safe version and saves the pom.xml once.
+// A direct dependency declaring its version is upgraded in place, or through
+// its property when nothing else uses it; other dependencies are pinned in
+// <dependencyManagement>.
+func (s *AuditService) Fix(findings []*AuditFinding) ([]*AuditFix, error) {
+	dependencies, err := s.pomRepository.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+	managed, err := s.pomRepository.GetManagedDependencies()
+	if err != nil {
+		return nil, err
+	}
+	declarations, err := s.pomRepository.GetPropertyDeclarations()
+	if err != nil {
+		return nil, err
+	}
+
+	var fixes []*AuditFix
+	changed := false
+	for _, finding := range findings {
+		fix := &AuditFix{Finding: finding}
+		fixes = append(fixes, fix)
+		if finding.SafeVersion == "" {
+			fix.Reason = "no fixed version is known"
+			continue
+		}
+
+		var declared *domain.Dependency
+		if len(finding.Path) == 0 {
+			if i := slices.IndexFunc(dependencies, finding.Dependency.SameArtifact); i >= 0 && dependencies[i].Version != "" {
+				declared = dependencies[i]
+			}
+		}
+		if declared != nil {
+			err = setVersion(s.pomRepository, declared, finding.SafeVersion, declarations)
+		} else {
+			err = pinVersion(s.pomRepository, finding.Dependency, finding.SafeVersion, managed, declarations)
+		}
+		if err != nil {
+			return nil, err
+		}
+		fix.Fixed = true
+		changed = true
+	}
+
+	if changed {
+		if err := s.pomRepository.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+		}
+	}
+	return fixes, nil
+}
+
+// ReadPom loads the pom.xml without locking the project, for Audit.
+func (s *AuditService) ReadPom(path string) error {
+	return s.graph.LoadPom(path)
+}
+
+// LoadPom locks the project and loads the pom.xml from the specified path, also
+// for the dependency graph. The lock is held until Close.
+func (s *AuditService) LoadPom(path string) error {
+	if err := s.projectLock.LoadPom(path); err != nil {
+		return err
+	}
+
+	s.graph.pomPath = path
+	return nil
+}
@@ -0,0 +1,215 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// stubAdvisoryDatabase is an AdvisoryDatabase backed by a map of groupId:artifactId to advisories.
+type stubAdvisoryDatabase map[string][]*domain.Advisory
+
+func (d stubAdvisoryDatabase) Advisories(groupID, artifactID string) ([]*domain.Advisory, error) {
+	return d[groupID+":"+artifactID], nil
+}
+
+var auditDatabase = stubAdvisoryDatabase{
+	"org.example:lib": {
+		{ID: "GHSA-1", Severity: domain.AdvisoryModerate, Ranges: [][]domain.AdvisoryEvent{{{Introduced: "0"}, {Fixed: "1.2"}}}},
+		// 1.2 fixes the first advisory only
+		{ID: "GHSA-2", Severity: domain.AdvisoryCritical, Ranges: [][]domain.AdvisoryEvent{{{Introduced: "1.0"}, {Fixed: "1.3"}}}},
+	},
+	"org.example:c": {
+		{ID: "GHSA-3", Severity: domain.AdvisoryHigh, Ranges: [][]domain.AdvisoryEvent{{{Introduced: "0"}, {Fixed: "1.0.1"}}}},
+	},
+	"org.example:a": {
+		{ID: "GHSA-4", Severity: domain.AdvisoryLow, Ranges: [][]domain.AdvisoryEvent{{{Introduced: "2.0"}}}},
+	},
+}
+
+// auditLocator finds the artifacts of the audit testdata projects.
+var auditLocator = repositoryLocator(filepath.Join("testdata", "audit", "repository"))
+
+func TestAuditService_Audit(t *testing.T) {
+	type finding struct {
+		coordinates string
+		version     string
+		path        []string
+		advisories  []string
+		safeVersion string
+	}
+	tests := []struct {
+		name       string
+		file       string
+		transitive bool
+		artifacts  int
+		want       []finding
+	}{
+		{
+			name:       "transitive",
+			file:       "pom.xml",
+			transitive: true,
+			artifacts:  3,
+			want: []finding{
+				{"org.example:lib", "1.0", nil, []string{"GHSA-2", "GHSA-1"}, "1.3"},
+				{"org.example:c", "1.0", []string{"org.example:a:1.0"}, []string{"GHSA-3"}, "1.0.1"},
+			},
+		},
+		{
+			name:      "direct",
+			file:      "pom.xml",
+			artifacts: 2,
+			want: []finding{
+				{"org.example:lib", "1.0", nil, []string{"GHSA-2", "GHSA-1"}, "1.3"},
+			},
+		},
+		{
+			// right requests d:2.0, which loses to the d:1.0 of left, so the
+			// build never gets the lib d:2.0 depends on
+			name:       "losing version",
+			file:       "losing-version.xml",
+			transitive: true,
+			artifacts:  3,
+		},
+		{
+			name:       "no fixed version",
+			file:       "no-fix.xml",
+			transitive: true,
+			artifacts:  1,
+			want: []finding{
+				{"org.example:a", "2.0", nil, []string{"GHSA-4"}, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAuditService(xml.NewPomRepository(), nil, auditLocator, loadTestRepository, auditDatabase)
+			require.NoError(t, service.ReadPom(filepath.Join("testdata", "audit", tt.file)))
+
+			report, err := service.Audit(tt.transitive)
+			require.NoError(t, err)
+			assert.Equal(t, tt.artifacts, report.Artifacts)
+
+			var got []finding
+			for _, f := range report.Findings {
+				var advisories []string
+				for _, advisory := range f.Advisories {
+					advisories = append(advisories, advisory.ID)
+				}
+				got = append(got, finding{f.Dependency.Coordinates(), f.Dependency.Version, f.Path, advisories, f.SafeVersion})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAuditService_Fix(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		fixed []bool
+
+		// edit changes the pom.xml on disk after it is loaded for the fix
+		edit func(string) string
+
+		contains  []string
+		remaining int
+		conflict  bool
+	}{
+		{
+			name:  "property and pin",
+			file:  "pom.xml",
+			fixed: []bool{true, true},
+			// The direct dependency is upgraded through its property, the transitive one pinned
+			contains: []string{
+				"<lib.version>1.3</lib.version>",
+				"<artifactId>c</artifactId>\n        <version>1.0.1</version>",
+			},
+		},
+		{
+			name:  "shared property",
+			file:  "shared-property.xml",
+			fixed: []bool{true},
+			// lib-extras keeps the version of the property
+			contains: []string{
+				"<lib.version>1.0</lib.version>",
+				"<artifactId>lib</artifactId>\n      <version>1.3</version>",
+				"<artifactId>lib-extras</artifactId>\n      <version>${lib.version}</version>",
+			},
+		},
+		{
+			name:      "no fixed version",
+			file:      "no-fix.xml",
+			fixed:     []bool{false},
+			contains:  []string{"<artifactId>a</artifactId>\n      <version>2.0</version>"},
+			remaining: 1,
+		},
+		{
+			name: "pom.xml changed",
+			file: "pom.xml",
+			edit: func(pom string) string {
+				return strings.Replace(pom, "<artifactId>app</artifactId>", "<artifactId>shop</artifactId>", 1)
+			},
+			// The other change is kept
+			contains: []string{"<artifactId>shop</artifactId>", "<lib.version>1.0</lib.version>"},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAuditService(xml.NewPomRepository(), fs.NewFileLocker(), auditLocator, loadTestRepository, auditDatabase)
+			pomPath := copyTestPom(t, filepath.Join("audit", tt.file))
+			require.NoError(t, service.ReadPom(pomPath))
+			report, err := service.Audit(true)
+			require.NoError(t, err)
+
+			require.NoError(t, service.LoadPom(pomPath))
+			defer func() { assert.NoError(t, service.Close()) }()
+			if tt.edit != nil {
+				data, err := os.ReadFile(pomPath)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(pomPath, []byte(tt.edit(string(data))), 0644))
+			}
+
+			fixes, err := service.Fix(report.Findings)
+			if tt.conflict {
+				var conflictErr *domain.ConflictError
+				assert.ErrorAs(t, err, &conflictErr)
+			} else {
+				require.NoError(t, err)
+				var fixed []bool
+				for _, fix := range fixes {
+					fixed = append(fixed, fix.Fixed)
+					if !fix.Fixed {
+						assert.Equal(t, "no fixed version is known", fix.Reason)
+					}
+				}
+				assert.Equal(t, tt.fixed, fixed)
+			}
+
+			data, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, string(data), s)
+			}
+			if tt.conflict {
+				return
+			}
+
+			require.NoError(t, service.ReadPom(pomPath))
+			report, err = service.Audit(true)
+			require.NoError(t, err)
+			assert.Len(t, report.Findings, tt.remaining)
+		})
+	}
+}
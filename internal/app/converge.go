@@ -0,0 +1,238 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// VersionRequest is a version of an artifact requested in the dependency graph.
+type VersionRequest struct {
+	Version string
+
+	// Paths lists the artifacts requesting the version, each as the path leading
+	// to it from a direct dependency; an empty path is the project itself
+	Paths [][]string
+}
+
+// Divergence is an artifact requested at more than one version in the
+// dependency graph, which the enforcer's dependencyConvergence rule rejects.
+type Divergence struct {
+	// Dependency identifies the artifact, as first requested
+	Dependency *domain.Dependency
+
+	// Versions are in the order they are first requested
+	Versions []*VersionRequest
+
+	// Winner is the version Maven picks: the one nearest to the project,
+	// the first declared on ties
+	Winner string
+}
+
+// ConvergenceReport is the outcome of ConvergeService.Check.
+type ConvergenceReport struct {
+	Divergences []*Divergence
+
+	// Artifacts counts the distinct artifacts in the dependency graph
+	Artifacts int
+
+	// Unresolved lists the artifacts whose pom is not available locally; their
+	// dependencies could not be checked
+	Unresolved []string
+}
+
+// ConvergeService checks that the transitive dependency graph of a project
+// requests a single version of each artifact, and pins the versions Maven picks
+// in <dependencyManagement> when it does not.
+type ConvergeService struct {
+	projectLock
+
+	graph *ListDependenciesService
+}
+
+// NewConvergeService creates a new ConvergeService. Like ListDependenciesService,
+// it reads the poms of parents, BOMs and dependencies from disk only.
+func NewConvergeService(
+	pomRepository domain.PomRepository,
+	locker domain.Locker,
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+) *ConvergeService {
+	return &ConvergeService{
+		projectLock: newProjectLock(pomRepository, locker),
+		graph:       NewListDependenciesService(pomRepository, locator, load),
+	}
+}
+
+// Check walks the dependency graph and returns the artifacts requested at more
+// than one version, in the order they are first requested. Requests whose
+// version is unknown are ignored.
+func (s *ConvergeService) Check() (*ConvergenceReport, error) {
+	graph, err := s.graph.Graph(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dependency graph: %w", err)
+	}
+
+	report := &ConvergenceReport{Unresolved: graph.Unresolved}
+	var divergences []*Divergence
+	byKey := make(map[string]*Divergence)
+	for _, request := range graph.Requests {
+		dep := request.Dependency
+		if dep.Version == "" {
+			continue
+		}
+
+		divergence, ok := byKey[dep.Key()]
+		if !ok {
+			// Requests are breadth-first, so the first one is the nearest
+			divergence = &Divergence{Dependency: dep, Winner: dep.Version}
+			byKey[dep.Key()] = divergence
+			divergences = append(divergences, divergence)
+		}
+
+		var version *VersionRequest
+		for _, v := range divergence.Versions {
+			if v.Version == dep.Version {
+				version = v
+			}
+		}
+		if version == nil {
+			version = &VersionRequest{Version: dep.Version}
+			divergence.Versions = append(divergence.Versions, version)
+		}
+		version.Paths = append(version.Paths, request.Path)
+	}
+
+	report.Artifacts = len(divergences)
+	for _, divergence := range divergences {
+		if len(divergence.Versions) > 1 {
+			report.Divergences = append(report.Divergences, divergence)
+		}
+	}
+	return report, nil
+}
+
+// Pin sets the winning version of each divergence in the <dependencyManagement>
+// of the project and saves the pom.xml once. An existing managed entry keeps its
+// scope and exclusions; if its version is a property of the pom.xml that nothing
+// else uses, the property is updated instead.
+func (s *ConvergeService) Pin(divergences []*Divergence) error {
+	if len(divergences) == 0 {
+		return nil
+	}
+
+	managed, err := s.pomRepository.GetManagedDependencies()
+	if err != nil {
+		return err
+	}
+	declarations, err := s.pomRepository.GetPropertyDeclarations()
+	if err != nil {
+		return err
+	}
+
+	for _, divergence := range divergences {
+		if err := pinVersion(s.pomRepository, divergence.Dependency, divergence.Winner, managed, declarations); err != nil {
+			return err
+		}
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+	return nil
+}
+
+// pinVersion sets the version of the artifact of dep in <dependencyManagement>,
+// updating its entry among managed if there is one.
+func pinVersion(pom domain.PomRepository, dep *domain.Dependency, version string, managed []*domain.Dependency, declarations []*domain.Property) error {
+	pinned := &domain.Dependency{
+		GroupID:    dep.GroupID,
+		ArtifactID: dep.ArtifactID,
+		Type:       dep.Type,
+		Classifier: dep.Classifier,
+		Managed:    true,
+	}
+	for _, entry := range managed {
+		if entry.SameArtifact(pinned) && !entry.IsImport() {
+			pinned = entry
+			break
+		}
+	}
+	return setVersion(pom, pinned, version, declarations)
+}
+
+// setVersion updates the version of a declaration, keeping the rest of it.
+// If the version is a property of the pom.xml used nowhere else, the property is
+// updated instead; a property shared with other versions is left alone and the
+// declaration gets the version written out.
+func setVersion(pom domain.PomRepository, declaration *domain.Dependency, version string, declarations []*domain.Property) error {
+	if name, ok := propertyName(declaration.Version); ok && declaresProperty(declarations, name) {
+		shared, err := sharedProperty(pom, name)
+		if err != nil {
+			return err
+		}
+		if !shared {
+			if err := pom.SetProperty(name, version); err != nil {
+				return fmt.Errorf("failed to set %s: %w", name, err)
+			}
+			return nil
+		}
+	}
+
+	updated := *declaration
+	updated.Version = version
+	if updated.Scope == "" {
+		updated.Scope = "compile"
+	}
+	if err := pom.AddDependency(&updated); err != nil {
+		return fmt.Errorf("failed to set the version of %s: %w", updated.ID(), err)
+	}
+	return nil
+}
+
+// sharedProperty reports whether the pom.xml references ${name} more than once,
+// so that changing the property would change more than one version. It counts
+// every reference in the file, including those in profiles, plugins and comments,
+// so it errs on the side of writing the version out instead of changing the property.
+func sharedProperty(pom domain.PomRepository, name string) (bool, error) {
+	content, err := pom.Render()
+	if err != nil {
+		return false, err
+	}
+	return strings.Count(string(content), "${"+name+"}") > 1, nil
+}
+
+// propertyName returns the name of the property when value is exactly a ${name} reference.
+func propertyName(value string) (string, bool) {
+	if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") || strings.Count(value, "${") != 1 {
+		return "", false
+	}
+	return value[2 : len(value)-1], true
+}
+
+// declaresProperty reports whether the property is declared in the <properties> of the pom.xml.
+func declaresProperty(declarations []*domain.Property, name string) bool {
+	for _, property := range declarations {
+		if property.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPom loads the pom.xml without locking the project, for Check.
+func (s *ConvergeService) ReadPom(path string) error {
+	return s.graph.LoadPom(path)
+}
+
+// LoadPom locks the project and loads the pom.xml from the specified path, also
+// for the dependency graph. The lock is held until Close.
+func (s *ConvergeService) LoadPom(path string) error {
+	if err := s.projectLock.LoadPom(path); err != nil {
+		return err
+	}
+
+	s.graph.pomPath = path
+	return nil
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// convergeLocator finds the artifacts of the converge testdata projects.
+var convergeLocator = repositoryLocator(filepath.Join("testdata", "converge", "repository"))
+
+func TestConvergeService_Check(t *testing.T) {
+	type divergence struct {
+		coordinates string
+		winner      string
+		versions    []*VersionRequest
+	}
+	tests := []struct {
+		file        string
+		artifacts   int
+		unresolved  []string
+		divergences []divergence
+	}{
+		{
+			// a, b, missing, c and excluded; the test and optional d are not
+			// transitive, and b excludes its own excluded:2.0
+			file:       "pom.xml",
+			artifacts:  5,
+			unresolved: []string{"org.example:missing:1.0"},
+			divergences: []divergence{{
+				coordinates: "org.example:c",
+				winner:      "1.0",
+				versions: []*VersionRequest{
+					{Version: "1.0", Paths: [][]string{{"org.example:a:1.0"}}},
+					{Version: "2.0", Paths: [][]string{{"org.example:b:1.0"}}},
+				},
+			}},
+		},
+		{
+			// The managed version applies to every transitive request
+			file:       "pinned.xml",
+			artifacts:  5,
+			unresolved: []string{"org.example:missing:1.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			service := NewConvergeService(xml.NewPomRepository(), nil, convergeLocator, loadTestRepository)
+			require.NoError(t, service.ReadPom(filepath.Join("testdata", "converge", tt.file)))
+
+			report, err := service.Check()
+			require.NoError(t, err)
+			assert.Equal(t, tt.artifacts, report.Artifacts)
+			assert.Equal(t, tt.unresolved, report.Unresolved)
+			var got []divergence
+			for _, d := range report.Divergences {
+				got = append(got, divergence{d.Dependency.Coordinates(), d.Winner, d.Versions})
+			}
+			assert.Equal(t, tt.divergences, got)
+		})
+	}
+}
+
+func TestConvergeService_Pin(t *testing.T) {
+	pinned, err := os.ReadFile(filepath.Join("testdata", "converge", "pinned.xml"))
+	require.NoError(t, err)
+
+	service := NewConvergeService(xml.NewPomRepository(), fs.NewFileLocker(), convergeLocator, loadTestRepository)
+	pomPath := copyTestPom(t, filepath.Join("converge", "pom.xml"))
+	require.NoError(t, service.ReadPom(pomPath))
+	report, err := service.Check()
+	require.NoError(t, err)
+
+	require.NoError(t, service.LoadPom(pomPath))
+	defer func() { assert.NoError(t, service.Close()) }()
+	require.NoError(t, service.Pin(report.Divergences))
+
+	data, err := os.ReadFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(pinned), string(data))
+}
+
+func TestPinVersion_SharedProperty(t *testing.T) {
+	tests := []struct {
+		name       string
+		groupID    string
+		artifactID string
+		version    string
+		file       string
+		properties map[string]string
+		versions   map[string]string
+	}{
+		{
+			name:       "property used by one artifact",
+			file:       "shared-property.xml",
+			groupID:    "com.google.guava",
+			artifactID: "guava",
+			version:    "33.2.1-jre",
+			properties: map[string]string{"netty.version": "4.1.0", "guava.version": "33.2.1-jre"},
+			versions:   map[string]string{"netty-codec": "${netty.version}", "netty-handler": "${netty.version}", "guava": "${guava.version}"},
+		},
+		{
+			name:       "property shared by two artifacts",
+			file:       "shared-property.xml",
+			groupID:    "io.netty",
+			artifactID: "netty-codec",
+			version:    "4.1.2",
+			properties: map[string]string{"netty.version": "4.1.0", "guava.version": "32.0.0-jre"},
+			versions:   map[string]string{"netty-codec": "4.1.2", "netty-handler": "${netty.version}", "guava": "${guava.version}"},
+		},
+		{
+			// Any other reference counts, even in a comment
+			name:       "property also referenced in a comment",
+			groupID:    "com.google.guava",
+			artifactID: "guava",
+			version:    "33.2.1-jre",
+			file:       "shared-property-comment.xml",
+			properties: map[string]string{"netty.version": "4.1.0", "guava.version": "32.0.0-jre"},
+			versions:   map[string]string{"netty-codec": "${netty.version}", "netty-handler": "${netty.version}", "guava": "33.2.1-jre"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := xml.NewPomRepository()
+			require.NoError(t, repository.Load(copyTestPom(t, tt.file)))
+			managed, err := repository.GetManagedDependencies()
+			require.NoError(t, err)
+			declarations, err := repository.GetPropertyDeclarations()
+			require.NoError(t, err)
+
+			dep := &domain.Dependency{GroupID: tt.groupID, ArtifactID: tt.artifactID}
+			require.NoError(t, pinVersion(repository, dep, tt.version, managed, declarations))
+
+			declarations, err = repository.GetPropertyDeclarations()
+			require.NoError(t, err)
+			properties := make(map[string]string)
+			for _, property := range declarations {
+				properties[property.Name] = property.Value
+			}
+			assert.Equal(t, tt.properties, properties)
+
+			managed, err = repository.GetManagedDependencies()
+			require.NoError(t, err)
+			versions := make(map[string]string)
+			for _, entry := range managed {
+				versions[entry.ArtifactID] = entry.Version
+			}
+			assert.Equal(t, tt.versions, versions)
+		})
+	}
+}
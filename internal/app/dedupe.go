@@ -0,0 +1,285 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Duplicate is an artifact declared more than once: twice in the same section,
+// or in <dependencies> with another version than its <dependencyManagement> entry.
+type Duplicate struct {
+	// Section names where the artifact is declared, e.g. "<dependencies>"
+	Section string
+
+	// Declarations are in pom.xml order; for an override, the managed entry comes first
+	Declarations []*domain.Dependency
+
+	// Override is set for a <dependencies> declaration overriding the version of its
+	// <dependencyManagement> entry. Both declarations are legitimate, so overrides
+	// are reported but never merged.
+	Override bool
+}
+
+// Versions returns the distinct versions declared, as written and in order.
+// Declarations without a version are left out.
+func (d *Duplicate) Versions() []string {
+	var versions []string
+	for _, dep := range d.Declarations {
+		if dep.Version != "" && !slices.Contains(versions, dep.Version) {
+			versions = append(versions, dep.Version)
+		}
+	}
+	return versions
+}
+
+// Scopes returns the distinct scopes declared, in order; an omitted scope is compile.
+// Overrides have no scope conflicts, since a managed scope only applies to
+// declarations without one.
+func (d *Duplicate) Scopes() []string {
+	if d.Override {
+		return nil
+	}
+
+	var scopes []string
+	for _, dep := range d.Declarations {
+		scope := dep.Scope
+		if scope == "" {
+			scope = "compile"
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// Conflicting reports whether the declarations disagree on the version or scope.
+func (d *Duplicate) Conflicting() bool {
+	return len(d.Versions()) > 1 || len(d.Scopes()) > 1
+}
+
+// DedupeMerge is the outcome of merging the declarations of a Duplicate.
+type DedupeMerge struct {
+	Duplicate *Duplicate
+
+	// Merged is the declaration kept; nil when the duplicate was skipped
+	Merged *domain.Dependency
+
+	// Removed counts the declarations removed
+	Removed int
+
+	// Reason explains why the duplicate was skipped
+	Reason string
+}
+
+// DedupeService finds duplicate dependency declarations and merges them.
+type DedupeService struct {
+	projectLock
+}
+
+// NewDedupeService creates a new DedupeService.
+func NewDedupeService(pomRepository domain.PomRepository, locker domain.Locker) *DedupeService {
+	return &DedupeService{
+		projectLock: newProjectLock(pomRepository, locker),
+	}
+}
+
+// Find returns the duplicate declarations of the pom.xml, in pom.xml order.
+func (s *DedupeService) Find() ([]*Duplicate, error) {
+	return findDuplicates(s.pomRepository)
+}
+
+// Merge merges the declarations of each duplicate into its first one, choosing
+// the version and scope with the given strategies, and saves the pom.xml once.
+// Overrides and declarations that cannot be merged are skipped with a reason.
+func (s *DedupeService) Merge(duplicates []*Duplicate, versions domain.VersionStrategy, scopes domain.ScopeStrategy) ([]*DedupeMerge, error) {
+	properties, err := s.pomRepository.GetProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	var merges []*DedupeMerge
+	changed := false
+	for _, duplicate := range duplicates {
+		merge := &DedupeMerge{Duplicate: duplicate}
+		merges = append(merges, merge)
+
+		if duplicate.Override {
+			merge.Reason = "overrides the managed version; remove one of the versions by hand"
+			continue
+		}
+
+		merged, err := mergeDeclarations(duplicate.Declarations, properties, versions, scopes)
+		if err != nil {
+			merge.Reason = err.Error()
+			continue
+		}
+
+		merge.Removed, err = s.pomRepository.MergeDependencies(merged)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", merged.ID(), err)
+		}
+		merge.Merged = merged
+		changed = true
+	}
+
+	if changed {
+		if err := s.pomRepository.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+		}
+	}
+	return merges, nil
+}
+
+// findDuplicates finds the artifacts declared twice in a section of the pom.xml,
+// and the <dependencies> declarations overriding a managed version.
+func findDuplicates(pom domain.PomRepository) ([]*Duplicate, error) {
+	sections, err := dependencySections(pom)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []*Duplicate
+	for _, section := range sections {
+		groups := make(map[string]*Duplicate)
+		for _, dep := range section.dependencies {
+			if duplicate, ok := groups[dep.Key()]; ok {
+				duplicate.Declarations = append(duplicate.Declarations, dep)
+				continue
+			}
+			duplicate := &Duplicate{Section: section.name, Declarations: []*domain.Dependency{dep}}
+			groups[dep.Key()] = duplicate
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	duplicates = slices.DeleteFunc(duplicates, func(d *Duplicate) bool { return len(d.Declarations) < 2 })
+
+	properties, err := pom.GetProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	// The first two sections are the <dependencies> and <dependencyManagement> of the project
+	managed := make(map[string]*domain.Dependency)
+	for _, dep := range sections[1].dependencies {
+		if _, ok := managed[dep.Key()]; !ok {
+			managed[dep.Key()] = dep
+		}
+	}
+	seen := make(map[string]bool)
+	for _, dep := range sections[0].dependencies {
+		entry, ok := managed[dep.Key()]
+		if !ok || seen[dep.Key()] || dep.Version == "" || entry.Version == "" {
+			continue
+		}
+		seen[dep.Key()] = true
+		if !sameVersion(dep.Version, entry.Version, properties) {
+			duplicates = append(duplicates, &Duplicate{
+				Section:      "<dependencies> and <dependencyManagement>",
+				Declarations: []*domain.Dependency{entry, dep},
+				Override:     true,
+			})
+		}
+	}
+
+	return duplicates, nil
+}
+
+// mergeDeclarations merges duplicate declarations of an artifact into one, with
+// the identity of the first. The merged declaration is optional only if all are,
+// and excludes what any of them excludes.
+func mergeDeclarations(declarations []*domain.Dependency, properties map[string]string, versions domain.VersionStrategy, scopes domain.ScopeStrategy) (*domain.Dependency, error) {
+	first, last := declarations[0], declarations[len(declarations)-1]
+	merged := &domain.Dependency{
+		GroupID:    first.GroupID,
+		ArtifactID: first.ArtifactID,
+		Type:       first.Type,
+		Classifier: first.Classifier,
+		Profile:    first.Profile,
+		Managed:    first.Managed,
+		Optional:   true,
+	}
+
+	switch versions {
+	case domain.VersionFirst:
+		merged.Version = first.Version
+	case domain.VersionLast:
+		merged.Version = last.Version
+	default:
+		version, err := highestVersion(declarations, properties)
+		if err != nil {
+			return nil, err
+		}
+		merged.Version = version
+	}
+
+	switch scopes {
+	case domain.ScopeFirst:
+		merged.Scope = first.Scope
+	case domain.ScopeLast:
+		merged.Scope = last.Scope
+	default:
+		all := make([]string, len(declarations))
+		for i, dep := range declarations {
+			all[i] = dep.Scope
+		}
+		scope, err := domain.WidestScope(all...)
+		if err != nil {
+			return nil, err
+		}
+		merged.Scope = scope
+	}
+	if merged.Scope == "" {
+		merged.Scope = "compile"
+	}
+
+	for _, dep := range declarations {
+		merged.Optional = merged.Optional && dep.Optional
+		if merged.Scope == "system" && merged.SystemPath == "" {
+			merged.SystemPath = dep.SystemPath
+		}
+		for _, exclusion := range dep.Exclusions {
+			if !merged.HasExclusion(exclusion) {
+				merged.Exclusions = append(merged.Exclusions, exclusion)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// highestVersion returns the highest of the versions declared, as written.
+// Versions are compared once their properties are resolved.
+func highestVersion(declarations []*domain.Dependency, properties map[string]string) (string, error) {
+	var highest, highestResolved string
+	for _, dep := range declarations {
+		if dep.Version == "" {
+			continue
+		}
+		resolved := interpolate(dep.Version, properties)
+		if resolved == "" {
+			return "", fmt.Errorf("cannot compare version %s: property not defined in the pom.xml", dep.Version)
+		}
+		if highest == "" || domain.CompareVersions(resolved, highestResolved) > 0 {
+			highest, highestResolved = dep.Version, resolved
+		}
+	}
+	return highest, nil
+}
+
+// sameVersion reports whether two versions are equal once their properties are
+// resolved, or as written when a property is not defined.
+func sameVersion(a, b string, properties map[string]string) bool {
+	resolvedA, resolvedB := interpolate(a, properties), interpolate(b, properties)
+	if resolvedA == "" || resolvedB == "" {
+		return a == b
+	}
+	return resolvedA == resolvedB
+}
+
+// ReadPom loads the pom.xml without locking the project, for Find.
+func (s *DedupeService) ReadPom(path string) error {
+	return s.pomRepository.Load(path)
+}
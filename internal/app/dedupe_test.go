@@ -0,0 +1,114 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestDedupeService_Find(t *testing.T) {
+	type row struct {
+		id           string
+		section      string
+		declarations int
+		versions     []string
+		scopes       []string
+		conflicting  bool
+		override     bool
+	}
+	tests := []struct {
+		file string
+		want []row
+	}{
+		{
+			file: "dedupe.xml",
+			want: []row{
+				// The classifier makes the sources jar another artifact
+				{"org.postgresql:postgresql", "<dependencies>", 2, []string{"42.7.3", "42.6.0"}, []string{"runtime", "provided"}, true, false},
+				{"org.slf4j:slf4j-api", "<dependencyManagement>", 2, []string{"2.0.9", "${slf4j.version}"}, []string{"compile"}, true, false},
+				{"com.google.guava:guava", "<dependencies> and <dependencyManagement>", 2, []string{"33.2.1-jre", "32.1.3-jre"}, nil, true, true},
+			},
+		},
+		{file: "formatted.xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			service := NewDedupeService(xml.NewPomRepository(), fs.NewFileLocker())
+			loadTestPom(t, service, tt.file)
+
+			duplicates, err := service.Find()
+			require.NoError(t, err)
+
+			var got []row
+			for _, d := range duplicates {
+				got = append(got, row{d.Declarations[0].ID(), d.Section, len(d.Declarations), d.Versions(), d.Scopes(), d.Conflicting(), d.Override})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDedupeService_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions domain.VersionStrategy
+		scopes   domain.ScopeStrategy
+		want     []string
+	}{
+		{
+			name:     "highest and widest",
+			versions: domain.VersionHighest,
+			scopes:   domain.ScopeWidest,
+			want:     []string{"<version>42.7.3</version>\n      <exclusions>", "<version>${slf4j.version}</version>"},
+		},
+		{
+			name:     "last",
+			versions: domain.VersionLast,
+			scopes:   domain.ScopeLast,
+			want:     []string{"<version>42.6.0</version>\n      <scope>provided</scope>", "<version>${slf4j.version}</version>"},
+		},
+		{
+			name:     "first",
+			versions: domain.VersionFirst,
+			scopes:   domain.ScopeFirst,
+			want:     []string{"<version>42.7.3</version>\n      <scope>runtime</scope>", "<version>2.0.9</version>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDedupeService(xml.NewPomRepository(), fs.NewFileLocker())
+			pomPath := loadTestPom(t, service, "dedupe.xml")
+
+			duplicates, err := service.Find()
+			require.NoError(t, err)
+			merges, err := service.Merge(duplicates, tt.versions, tt.scopes)
+			require.NoError(t, err)
+			require.Len(t, merges, 3)
+			assert.Equal(t, 1, merges[0].Removed)
+			assert.Equal(t, 1, merges[1].Removed)
+			assert.Nil(t, merges[2].Merged)
+			assert.NotEmpty(t, merges[2].Reason)
+
+			data, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+			pom := string(data)
+			for _, want := range tt.want {
+				assert.Contains(t, pom, want)
+			}
+			assert.Contains(t, pom, "<artifactId>checker-qual</artifactId>")
+			assert.Contains(t, pom, "<classifier>sources</classifier>")
+
+			duplicates, err = service.Find()
+			require.NoError(t, err)
+			assert.Len(t, duplicates, 1, "only the override is left")
+		})
+	}
+}
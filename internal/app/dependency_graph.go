@@ -0,0 +1,234 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// maxGraphDepth bounds how deep the transitive dependency graph is walked
+const maxGraphDepth = 32
+
+// GraphRequest is a dependency requested somewhere in the transitive dependency graph.
+type GraphRequest struct {
+	// Dependency is the declaration with its effective Version, empty when unknown.
+	// The project's <dependencyManagement> overrides the versions of transitive
+	// dependencies, as in Maven.
+	Dependency *domain.Dependency
+
+	// Scope is the effective scope of the request: its declared scope for direct
+	// dependencies, else the scope Maven derives from the path, e.g. runtime for
+	// a compile dependency of a runtime dependency
+	Scope string
+
+	// Path lists the artifacts leading to the request as groupId:artifactId:version,
+	// starting with a direct dependency; empty for direct dependencies
+	Path []string
+}
+
+// DependencyGraph is the transitive dependency graph of a project, before Maven
+// picks one version of each artifact.
+type DependencyGraph struct {
+	// Requests are in breadth-first order, so nearer requests come first
+	Requests []*GraphRequest
+
+	// Unresolved lists the artifacts whose pom is not available locally, so their
+	// own dependencies are missing from the graph
+	Unresolved []string
+}
+
+// graphNode is a request waiting to be expanded, with the exclusions inherited
+// along its path.
+type graphNode struct {
+	request    *GraphRequest
+	exclusions []domain.Exclusion
+}
+
+// Graph walks the transitive dependencies of the project, reading the pom of
+// each artifact from the local repository. As in Maven, the direct dependencies
+// of every scope are included, but only the compile and runtime dependencies of
+// other artifacts, without optional ones and honoring exclusions. Each version of
+// an artifact is expanded once, through the first path reaching it.
+//
+// Unless all is set, only the version Maven picks for each artifact, the first one
+// reached, is expanded: the other versions are still requested, but the
+// dependencies only they bring are left out, as they are of the build. All
+// versions are expanded to find where versions diverge.
+func (s *ListDependenciesService) Graph(all bool) (*DependencyGraph, error) {
+	root, err := s.resolveModel(s.pomRepository, s.pomPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	direct, err := s.effectiveDependencies(s.pomRepository, s.pomPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := s.pomRepository.GetProperties()
+	if err != nil {
+		return nil, err
+	}
+	project := properties["project.groupId"] + ":" + properties["project.artifactId"]
+
+	graph := &DependencyGraph{}
+	var queue []graphNode
+	for _, dep := range direct {
+		resolved := *dep
+		resolved.Version = root.declare(dep, false).ResolvedVersion
+		request := &GraphRequest{Dependency: &resolved, Scope: propagateScope("compile", dep.Scope)}
+		queue = append(queue, graphNode{request: request, exclusions: dep.Exclusions})
+	}
+
+	expanded := make(map[string]bool)
+	// picked is the version of each artifact requested first, by key
+	picked := make(map[string]string)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		graph.Requests = append(graph.Requests, node.request)
+
+		dep := node.request.Dependency
+		version, ok := picked[dep.Key()]
+		if !ok {
+			version = dep.Version
+			picked[dep.Key()] = version
+		}
+		if !all && version != dep.Version {
+			continue
+		}
+
+		coordinates := dep.Coordinates() + ":" + dep.Version
+		if dep.Version == "" || dep.IsImport() || dep.Scope == "system" || expanded[coordinates] || len(node.request.Path) >= maxGraphDepth {
+			continue
+		}
+		expanded[coordinates] = true
+
+		children, model, err := s.openArtifact(dep)
+		if err != nil {
+			return nil, err
+		}
+		if model == nil {
+			graph.Unresolved = append(graph.Unresolved, coordinates)
+			continue
+		}
+
+		path := append(slices.Clone(node.request.Path), coordinates)
+		for _, child := range children {
+			if child.Optional || (child.Scope != "" && child.Scope != "compile" && child.Scope != "runtime") {
+				continue
+			}
+			if excluded(node.exclusions, child) || child.Coordinates() == project || onPath(path, child) {
+				continue
+			}
+
+			resolved := *child
+			if version := root.managed(child.Key()); version != "" {
+				resolved.Version = version
+			} else {
+				resolved.Version = model.declare(child, false).ResolvedVersion
+			}
+			queue = append(queue, graphNode{
+				request:    &GraphRequest{Dependency: &resolved, Scope: propagateScope(node.request.Scope, child.Scope), Path: path},
+				exclusions: append(slices.Clone(node.exclusions), child.Exclusions...),
+			})
+		}
+	}
+
+	return graph, nil
+}
+
+// propagateScope returns the scope of a compile or runtime dependency of an
+// artifact in the given scope: a compile dependency inherits the scope of the
+// artifact, other scopes win over compile.
+func propagateScope(scope, dependencyScope string) string {
+	if dependencyScope == "" {
+		dependencyScope = "compile"
+	}
+	if scope == "compile" {
+		return dependencyScope
+	}
+	return scope
+}
+
+// openArtifact reads the pom of a dependency from the local repository and returns
+// its dependencies with its effective model, or a nil model if it is not available.
+func (s *ListDependenciesService) openArtifact(dep *domain.Dependency) ([]*domain.Dependency, *effectiveModel, error) {
+	path, err := s.locator.Locate(dep.GroupID, dep.ArtifactID, dep.Version)
+	if err != nil {
+		return nil, nil, nil
+	}
+	repository, err := s.load(path)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	model, err := s.resolveModel(repository, path, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	dependencies, err := s.effectiveDependencies(repository, path, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dependencies, model, nil
+}
+
+// effectiveDependencies returns the <dependencies> of a pom followed by those it
+// inherits from its parents that it does not redeclare.
+func (s *ListDependenciesService) effectiveDependencies(repository domain.PomRepository, path string, depth int) ([]*domain.Dependency, error) {
+	dependencies, err := repository.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := repository.GetParent()
+	if err != nil || parent == nil || depth >= maxModelDepth {
+		return dependencies, err
+	}
+	parentRepository, parentPath := openParent(s.locator, s.load, parent, path)
+	if parentRepository == nil {
+		return dependencies, nil
+	}
+
+	inherited, err := s.effectiveDependencies(parentRepository, parentPath, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range inherited {
+		if !slices.ContainsFunc(dependencies, dep.SameArtifact) {
+			dependencies = append(dependencies, dep)
+		}
+	}
+	return dependencies, nil
+}
+
+// managed returns the version the model manages for the artifact with the given
+// key, or "" when it is not managed or cannot be resolved.
+func (m *effectiveModel) managed(key string) string {
+	if mv, ok := m.direct[key]; ok {
+		return interpolate(mv.version, m.properties)
+	}
+	return m.imported[key].version
+}
+
+// excluded reports whether one of the exclusions matches the dependency.
+func excluded(exclusions []domain.Exclusion, dep *domain.Dependency) bool {
+	for _, e := range exclusions {
+		if (e.GroupID == "*" || e.GroupID == dep.GroupID) && (e.ArtifactID == "*" || e.ArtifactID == dep.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+// onPath reports whether the artifact of dep is already on the path, which
+// would make the graph cycle.
+func onPath(path []string, dep *domain.Dependency) bool {
+	prefix := dep.Coordinates() + ":"
+	for _, coordinates := range path {
+		if strings.HasPrefix(coordinates, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Severity ranks the problems reported by DoctorService.
+type Severity string
+
+const (
+	// SeverityError is a problem that breaks or endangers the build or a release
+	SeverityError Severity = "error"
+
+	// SeverityWarning is a problem Maven warns about, or a likely mistake
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo is a suggestion
+	SeverityInfo Severity = "info"
+)
+
+// Severities lists the severities from the most to the least severe.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+// ParseSeverity parses one of Severities.
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range Severities {
+		if string(severity) == s {
+			return severity, nil
+		}
+	}
+	return "", &domain.ValidationError{
+		Field:   "severity",
+		Message: fmt.Sprintf("invalid severity: %s (valid: error, warning, info)", s),
+	}
+}
+
+// AtLeast reports whether s is as severe as min, or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return slices.Index(Severities, s) <= slices.Index(Severities, min)
+}
+
+// Diagnostic is a problem found in a pom.xml by a DoctorCheck.
+type Diagnostic struct {
+	// Check is the name of the check that found the problem
+	Check string
+
+	Severity Severity
+	Message  string
+
+	// Position is where the problem is in the pom.xml; zero for problems of the
+	// file as a whole, such as a missing property
+	Position domain.Position
+
+	// Fix describes the automatic fix, e.g. "set the scope to test"; empty when
+	// the problem must be fixed by hand
+	Fix string
+
+	// apply performs Fix on the pom.xml
+	apply func(repository domain.PomRepository) error
+}
+
+// DoctorProject is what a DoctorCheck inspects: the pom.xml and what it inherits.
+type DoctorProject struct {
+	Pom domain.PomRepository
+
+	// Parents holds the parent poms that could be read, nearest first
+	Parents []domain.PomRepository
+
+	// ParentsComplete is false when a parent is not available locally, so what
+	// the project inherits is not fully known
+	ParentsComplete bool
+
+	// Properties are the effective properties: those of the pom.xml over those of its parents
+	Properties map[string]string
+
+	// Text is the content of the pom.xml
+	Text string
+}
+
+// DoctorCheck is one of the checks run by DoctorService. Checks are independent
+// of each other; DefaultChecks lists those mvnx doctor runs.
+type DoctorCheck interface {
+	// Name identifies the check in reports and on the command line, e.g. "duplicates"
+	Name() string
+
+	// Description says what the check looks for, in a short sentence
+	Description() string
+
+	// Run returns the problems found in the project.
+	Run(project *DoctorProject) ([]*Diagnostic, error)
+}
+
+// DoctorService checks a pom.xml for common problems and fixes those it can.
+type DoctorService struct {
+	projectLock
+
+	locator domain.PomLocator
+	checks  []DoctorCheck
+
+	// load reads a parent pom.xml
+	load func(path string) (domain.PomRepository, error)
+}
+
+// NewDoctorService creates a new DoctorService running the given checks.
+func NewDoctorService(
+	pomRepository domain.PomRepository,
+	locker domain.Locker,
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+	checks []DoctorCheck,
+) *DoctorService {
+	return &DoctorService{
+		projectLock: newProjectLock(pomRepository, locker),
+		locator:     locator,
+		load:        load,
+		checks:      checks,
+	}
+}
+
+// Checks returns the checks the service runs.
+func (s *DoctorService) Checks() []DoctorCheck {
+	return s.checks
+}
+
+// Run runs every check not named in skip and returns the problems found, in
+// pom.xml order; problems without a position come last.
+func (s *DoctorService) Run(skip []string) ([]*Diagnostic, error) {
+	for _, name := range skip {
+		if !slices.ContainsFunc(s.checks, func(c DoctorCheck) bool { return c.Name() == name }) {
+			return nil, &domain.ValidationError{
+				Field:   "check",
+				Message: fmt.Sprintf("unknown check: %s (valid: %s)", name, strings.Join(s.checkNames(), ", ")),
+			}
+		}
+	}
+
+	project, err := s.project()
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []*Diagnostic
+	for _, check := range s.checks {
+		if slices.Contains(skip, check.Name()) {
+			continue
+		}
+		found, err := check.Run(project)
+		if err != nil {
+			return nil, fmt.Errorf("check %s failed: %w", check.Name(), err)
+		}
+		for _, d := range found {
+			d.Check = check.Name()
+		}
+		diagnostics = append(diagnostics, found...)
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Position, diagnostics[j].Position
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return diagnostics, nil
+}
+
+// FixFailure is a fix that DoctorService.Fix could not apply.
+type FixFailure struct {
+	Diagnostic *Diagnostic
+	Err        error
+}
+
+// FixResult is the outcome of DoctorService.Fix.
+type FixResult struct {
+	// Fixed holds the diagnostics whose fix was applied
+	Fixed []*Diagnostic
+
+	// Failed holds the fixes that failed, e.g. on a network error
+	Failed []FixFailure
+}
+
+// Fix applies the fixes of the given diagnostics and saves the pom.xml once.
+// Diagnostics without a fix are skipped. A failing fix does not stop the others:
+// it is reported in the result, and the pom.xml is saved with the fixes that
+// succeeded. Only failing to save is an error.
+func (s *DoctorService) Fix(diagnostics []*Diagnostic) (*FixResult, error) {
+	result := &FixResult{}
+	for _, d := range diagnostics {
+		if d.apply == nil {
+			continue
+		}
+		if err := d.apply(s.pomRepository); err != nil {
+			result.Failed = append(result.Failed, FixFailure{Diagnostic: d, Err: err})
+			continue
+		}
+		result.Fixed = append(result.Fixed, d)
+	}
+
+	if len(result.Fixed) == 0 {
+		return result, nil
+	}
+	if err := s.pomRepository.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+	return result, nil
+}
+
+// project collects what the checks inspect. Parents are read from their
+// relativePath or the local repository, as ListDependenciesService does.
+func (s *DoctorService) project() (*DoctorProject, error) {
+	text, err := s.pomRepository.Render()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	project := &DoctorProject{
+		Pom:             s.pomRepository,
+		ParentsComplete: true,
+		Properties:      make(map[string]string),
+		Text:            string(text),
+	}
+
+	repository, path := s.pomRepository, s.pomPath
+	for depth := 0; depth < maxModelDepth; depth++ {
+		parent, err := repository.GetParent()
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+
+		repository, path = openParent(s.locator, s.load, parent, path)
+		if repository == nil {
+			project.ParentsComplete = false
+			break
+		}
+		project.Parents = append(project.Parents, repository)
+	}
+
+	// Apply the farthest parent first, so nearer poms override it
+	for i := len(project.Parents) - 1; i >= 0; i-- {
+		if err := mergeProperties(project.Properties, project.Parents[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := mergeProperties(project.Properties, s.pomRepository); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// mergeProperties copies the properties of a pom.xml into properties.
+func mergeProperties(properties map[string]string, repository domain.PomRepository) error {
+	declared, err := repository.GetProperties()
+	if err != nil {
+		return err
+	}
+	for name, value := range declared {
+		properties[name] = value
+	}
+	return nil
+}
+
+// checkNames returns the names of the checks, in order.
+func (s *DoctorService) checkNames() []string {
+	names := make([]string, len(s.checks))
+	for i, check := range s.checks {
+		names[i] = check.Name()
+	}
+	return names
+}
+
+// ReadPom loads the pom.xml without locking the project, for a check without fixes.
+func (s *DoctorService) ReadPom(path string) error {
+	s.pomPath = path
+	return s.pomRepository.Load(path)
+}
@@ -0,0 +1,476 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// sourceEncodingProperty sets the encoding of source files; without it Maven
+// uses the platform encoding and warns that the build is platform dependent
+const sourceEncodingProperty = "project.build.sourceEncoding"
+
+// testLibraries selects the artifacts only tests should depend on
+var testLibraries = []string{
+	"junit:junit", "org.junit.jupiter:*", "org.junit.platform:*", "org.junit.vintage:*",
+	"org.testng:testng", "org.mockito:*", "org.easymock:*", "io.mockk:*",
+	"org.assertj:*", "org.hamcrest:*", "org.xmlunit:*", "org.skyscreamer:jsonassert",
+	"org.testcontainers:*", "io.rest-assured:*", "org.awaitility:*",
+	"org.wiremock:*", "com.github.tomakehurst:*", "org.spockframework:*", "io.cucumber:*",
+	"org.springframework:spring-test", "org.springframework.boot:spring-boot-starter-test",
+	"io.quarkus:quarkus-junit5*", "io.micronaut.test:*",
+}
+
+// implicitPropertyPrefixes match properties read by Maven or by plugins rather
+// than referenced in the pom.xml, such as maven.compiler.release or skipTests
+var implicitPropertyPrefixes = []string{
+	"project.", "maven.", "java.", "surefire.", "failsafe.", "jacoco.", "sonar.",
+	"checkstyle.", "spotbugs.", "pmd.", "gpg.", "jib.", "quarkus.", "spring-boot.",
+	"skip", "argLine", "start-class",
+}
+
+// DefaultChecks returns the checks of mvnx doctor. The resolver looks up the
+// versions pinned by the plugin-versions fix.
+func DefaultChecks(resolver domain.Resolver) []DoctorCheck {
+	return []DoctorCheck{
+		duplicatesCheck{},
+		pluginVersionsCheck{resolver: resolver},
+		snapshotsCheck{},
+		testScopeCheck{},
+		unstableVersionsCheck{},
+		sourceEncodingCheck{},
+		unusedPropertiesCheck{},
+	}
+}
+
+// dependencySection is a list of dependencies that must not declare the same artifact twice.
+type dependencySection struct {
+	name         string
+	dependencies []*domain.Dependency
+
+	// managed is set for <dependencyManagement>
+	managed bool
+}
+
+// dependencySections returns the <dependencies> of the project, of its
+// <dependencyManagement> and of each profile.
+func dependencySections(pom domain.PomRepository) ([]dependencySection, error) {
+	dependencies, err := pom.GetDependencies()
+	if err != nil {
+		return nil, err
+	}
+	managed, err := pom.GetManagedDependencies()
+	if err != nil {
+		return nil, err
+	}
+	sections := []dependencySection{
+		{name: "<dependencies>", dependencies: dependencies},
+		{name: "<dependencyManagement>", dependencies: managed, managed: true},
+	}
+
+	profiles, err := pom.GetProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		dependencies, err := pom.GetProfileDependencies(profile.ID)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, dependencySection{name: "the <dependencies> of profile " + profile.ID, dependencies: dependencies})
+	}
+
+	return sections, nil
+}
+
+// duplicatesCheck reports artifacts declared twice in the same section; Maven
+// uses the last declaration and only warns about it. Dependency duplicates are
+// merged like mvnx dedupe does by default. Versions of <dependencies> overriding
+// those of <dependencyManagement> are reported as warnings.
+type duplicatesCheck struct{}
+
+func (duplicatesCheck) Name() string { return "duplicates" }
+
+func (duplicatesCheck) Description() string {
+	return "dependencies or plugins declared more than once in the same section"
+}
+
+func (duplicatesCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	duplicates, err := findDuplicates(project.Pom)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []*Diagnostic
+	for _, duplicate := range duplicates {
+		first, second := duplicate.Declarations[0], duplicate.Declarations[1]
+		if duplicate.Override {
+			diagnostics = append(diagnostics, &Diagnostic{
+				Severity: SeverityWarning,
+				Message: fmt.Sprintf("%s overrides version %s of <dependencyManagement>%s with %s",
+					second.ID(), first.Version, firstDeclared(first.Position), second.Version),
+				Position: second.Position,
+			})
+			continue
+		}
+
+		merged, mergeErr := mergeDeclarations(duplicate.Declarations, project.Properties, domain.VersionHighest, domain.ScopeWidest)
+		diagnostic := &Diagnostic{
+			Severity: SeverityError,
+			Message: fmt.Sprintf("%s is declared %s in %s%s%s", first.ID(), times(len(duplicate.Declarations)),
+				duplicate.Section, conflicts(duplicate), firstDeclared(first.Position)),
+			Position: second.Position,
+		}
+		if mergeErr == nil {
+			diagnostic.Fix = "merge them, keeping the highest version and the widest scope"
+			diagnostic.apply = func(repository domain.PomRepository) error {
+				_, err := repository.MergeDependencies(merged)
+				return err
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	plugins, err := project.Pom.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+	first := make(map[string]*domain.Plugin)
+	for _, plugin := range plugins {
+		key := fmt.Sprintf("%s:%t", plugin.Coordinates(), plugin.Managed)
+		previous, ok := first[key]
+		if !ok {
+			first[key] = plugin
+			continue
+		}
+		section := "<plugins>"
+		if plugin.Managed {
+			section = "<pluginManagement>"
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s is declared twice in %s%s", plugin.Coordinates(), section, firstDeclared(previous.Position)),
+			Position: plugin.Position,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+// times spells out how many times an artifact is declared.
+func times(n int) string {
+	if n == 2 {
+		return "twice"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+// conflicts describes the versions and scopes duplicate declarations disagree on.
+func conflicts(duplicate *Duplicate) string {
+	var parts []string
+	if versions := duplicate.Versions(); len(versions) > 1 {
+		parts = append(parts, "versions "+strings.Join(versions, ", "))
+	}
+	if scopes := duplicate.Scopes(); len(scopes) > 1 {
+		parts = append(parts, "scopes "+strings.Join(scopes, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " with conflicting " + strings.Join(parts, " and ")
+}
+
+// firstDeclared points to the first of two declarations, if its position is known.
+func firstDeclared(position domain.Position) string {
+	if position.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" (first at line %d)", position.Line)
+}
+
+// pluginVersionsCheck reports build plugins without a version, which Maven warns
+// about since the build then depends on the Maven version used.
+type pluginVersionsCheck struct {
+	resolver domain.Resolver
+}
+
+func (pluginVersionsCheck) Name() string { return "plugin-versions" }
+
+func (pluginVersionsCheck) Description() string {
+	return "build plugins without a version, neither in <pluginManagement> nor in a parent"
+}
+
+func (c pluginVersionsCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	// A parent that cannot be read may manage every plugin
+	if !project.ParentsComplete {
+		return nil, nil
+	}
+
+	plugins, err := project.Pom.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	versioned := make(map[string]bool)
+	for _, repository := range append([]domain.PomRepository{project.Pom}, project.Parents...) {
+		declared, err := repository.GetPlugins()
+		if err != nil {
+			return nil, err
+		}
+		for _, plugin := range declared {
+			if plugin.Version != "" {
+				versioned[plugin.Coordinates()] = true
+			}
+		}
+	}
+
+	var diagnostics []*Diagnostic
+	for _, plugin := range plugins {
+		if plugin.Managed || plugin.Version != "" || versioned[plugin.Coordinates()] {
+			continue
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("plugin %s has no version", plugin.Coordinates()),
+			Position: plugin.Position,
+			Fix:      "pin the latest version",
+			apply: func(repository domain.PomRepository) error {
+				latest, err := c.resolver.ResolveExact(plugin.GroupID, plugin.ArtifactID)
+				if err != nil {
+					return err
+				}
+				return repository.AddPlugin(&domain.Plugin{GroupID: plugin.GroupID, ArtifactID: plugin.ArtifactID, Version: latest.LatestVersion})
+			},
+		})
+	}
+	return diagnostics, nil
+}
+
+// snapshotsCheck reports SNAPSHOT dependencies and plugins of a release version,
+// which cannot be reproduced and which the release plugin refuses.
+type snapshotsCheck struct{}
+
+func (snapshotsCheck) Name() string { return "snapshots" }
+
+func (snapshotsCheck) Description() string {
+	return "SNAPSHOT dependencies or plugins in a project with a release version"
+}
+
+func (snapshotsCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	version := project.Properties["project.version"]
+	if version == "" || domain.IsSnapshot(version) {
+		return nil, nil
+	}
+
+	var diagnostics []*Diagnostic
+	err := forEachVersion(project, func(name, declared string, position domain.Position) {
+		resolved := interpolate(declared, project.Properties)
+		if domain.IsSnapshot(resolved) {
+			diagnostics = append(diagnostics, &Diagnostic{
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("%s uses SNAPSHOT version %s, but the project version %s is a release", name, resolved, version),
+				Position: position,
+			})
+		}
+	})
+	return diagnostics, err
+}
+
+// unstableVersionsCheck reports alpha, beta, milestone and release candidate versions.
+type unstableVersionsCheck struct{}
+
+func (unstableVersionsCheck) Name() string { return "unstable-versions" }
+
+func (unstableVersionsCheck) Description() string {
+	return "dependencies or plugins on alpha, beta, milestone or release candidate versions"
+}
+
+func (unstableVersionsCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	var diagnostics []*Diagnostic
+	err := forEachVersion(project, func(name, declared string, position domain.Position) {
+		resolved := interpolate(declared, project.Properties)
+		if domain.IsPreRelease(resolved) {
+			diagnostics = append(diagnostics, &Diagnostic{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s uses pre-release version %s", name, resolved),
+				Position: position,
+			})
+		}
+	})
+	return diagnostics, err
+}
+
+// forEachVersion calls fn with the version of every dependency and plugin that declares one.
+func forEachVersion(project *DoctorProject, fn func(name, version string, position domain.Position)) error {
+	sections, err := dependencySections(project.Pom)
+	if err != nil {
+		return err
+	}
+	for _, section := range sections {
+		for _, dep := range section.dependencies {
+			if dep.Version != "" {
+				fn(dep.Coordinates(), dep.Version, dep.Position)
+			}
+		}
+	}
+
+	plugins, err := project.Pom.GetPlugins()
+	if err != nil {
+		return err
+	}
+	for _, plugin := range plugins {
+		if plugin.Version != "" {
+			fn("plugin "+plugin.Coordinates(), plugin.Version, plugin.Position)
+		}
+	}
+	return nil
+}
+
+// testScopeCheck reports test libraries such as JUnit in compile scope, which
+// end up on the runtime classpath of the project and of its dependents.
+type testScopeCheck struct{}
+
+func (testScopeCheck) Name() string { return "test-scope" }
+
+func (testScopeCheck) Description() string {
+	return "test libraries such as JUnit or Mockito in compile scope"
+}
+
+func (testScopeCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	selectors := make([]*domain.DependencySelector, len(testLibraries))
+	for i, pattern := range testLibraries {
+		selector, err := domain.ParseDependencySelector(pattern)
+		if err != nil {
+			return nil, err
+		}
+		selectors[i] = selector
+	}
+
+	sections, err := dependencySections(project.Pom)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []*Diagnostic
+	for _, section := range sections {
+		// <dependencyManagement> does not put anything on the classpath
+		if section.managed {
+			continue
+		}
+		for _, dep := range section.dependencies {
+			if dep.Scope != "compile" || !slices.ContainsFunc(selectors, func(s *domain.DependencySelector) bool { return s.Matches(dep) }) {
+				continue
+			}
+			diagnostics = append(diagnostics, &Diagnostic{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("test library %s is in compile scope", dep.Coordinates()),
+				Position: dep.Position,
+				Fix:      "set the scope to test",
+				apply: func(repository domain.PomRepository) error {
+					fixed := *dep
+					fixed.Scope = "test"
+					return repository.AddDependency(&fixed)
+				},
+			})
+		}
+	}
+	return diagnostics, nil
+}
+
+// sourceEncodingCheck reports a project without project.build.sourceEncoding.
+type sourceEncodingCheck struct{}
+
+func (sourceEncodingCheck) Name() string { return "source-encoding" }
+
+func (sourceEncodingCheck) Description() string {
+	return "project.build.sourceEncoding not set, so the build depends on the platform encoding"
+}
+
+func (sourceEncodingCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	if !project.ParentsComplete || project.Properties[sourceEncodingProperty] != "" {
+		return nil, nil
+	}
+
+	return []*Diagnostic{{
+		Severity: SeverityWarning,
+		Message:  sourceEncodingProperty + " is not set; the build depends on the platform encoding",
+		Fix:      "set it to UTF-8",
+		apply: func(repository domain.PomRepository) error {
+			return repository.SetProperty(sourceEncodingProperty, "UTF-8")
+		},
+	}}, nil
+}
+
+// unusedPropertiesCheck reports properties the pom.xml never refers to.
+// Properties of parent poms, properties overriding a parent's and properties
+// read by Maven or plugins, such as maven.compiler.release, are not reported.
+type unusedPropertiesCheck struct{}
+
+func (unusedPropertiesCheck) Name() string { return "unused-properties" }
+
+func (unusedPropertiesCheck) Description() string {
+	return "properties that are never referenced in the pom.xml"
+}
+
+func (unusedPropertiesCheck) Run(project *DoctorProject) ([]*Diagnostic, error) {
+	packaging, err := project.Pom.GetPackaging()
+	if err != nil {
+		return nil, err
+	}
+	// Child modules may refer to the properties of a parent; a parent that cannot
+	// be read may use an overridden property
+	if packaging == domain.AggregatorPackaging || !project.ParentsComplete {
+		return nil, nil
+	}
+
+	inherited := make(map[string]bool)
+	for _, parent := range project.Parents {
+		properties, err := parent.GetProperties()
+		if err != nil {
+			return nil, err
+		}
+		for name := range properties {
+			inherited[name] = true
+		}
+	}
+
+	properties, err := project.Pom.GetPropertyDeclarations()
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []*Diagnostic
+	for _, property := range properties {
+		if inherited[property.Name] || isImplicitProperty(property.Name) ||
+			strings.Contains(project.Text, "${"+property.Name+"}") {
+			continue
+		}
+		name := property.Name
+		diagnostics = append(diagnostics, &Diagnostic{
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("property %s is never used", name),
+			Position: property.Position,
+			Fix:      "remove it",
+			apply: func(repository domain.PomRepository) error {
+				return repository.RemoveProperty(name)
+			},
+		})
+	}
+	return diagnostics, nil
+}
+
+// isImplicitProperty reports whether a property is read by Maven or a plugin
+// without being referenced in the pom.xml.
+func isImplicitProperty(name string) bool {
+	if slices.Contains(domain.JavaProperties, name) {
+		return true
+	}
+	for _, prefix := range implicitPropertyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
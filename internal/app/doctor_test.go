@@ -0,0 +1,168 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestDoctorService_Run(t *testing.T) {
+	type finding struct {
+		check    string
+		severity Severity
+		line     int
+		fixable  bool
+	}
+	tests := []struct {
+		name    string
+		file    string
+		skip    []string
+		want    []finding
+		invalid bool
+	}{
+		{
+			name: "all checks",
+			file: "doctor/pom.xml",
+			want: []finding{
+				{"unused-properties", SeverityInfo, 7, true},
+				{"test-scope", SeverityWarning, 16, true},
+				{"unstable-versions", SeverityWarning, 16, false},
+				{"snapshots", SeverityError, 21, false},
+				{"duplicates", SeverityError, 26, true},
+				{"plugin-versions", SeverityWarning, 34, true},
+				{"source-encoding", SeverityWarning, 0, true},
+			},
+		},
+		{
+			name: "skipped checks",
+			file: "doctor/pom.xml",
+			skip: []string{"duplicates", "unused-properties", "source-encoding"},
+			want: []finding{
+				{"test-scope", SeverityWarning, 16, true},
+				{"unstable-versions", SeverityWarning, 16, false},
+				{"snapshots", SeverityError, 21, false},
+				{"plugin-versions", SeverityWarning, 34, true},
+			},
+		},
+		{
+			name:    "unknown check",
+			file:    "doctor/pom.xml",
+			skip:    []string{"spelling"},
+			invalid: true,
+		},
+		// The encoding, the plugin version and the overridden property come from
+		// the parent, and a SNAPSHOT project may depend on snapshots
+		{
+			name: "inherited settings",
+			file: "doctor/inherited/core/pom.xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := stubResolver{"org.apache.maven.plugins:maven-surefire-plugin": "3.3.0"}
+			service := NewDoctorService(xml.NewPomRepository(), nil, stubLocator{}, loadTestRepository, DefaultChecks(resolver))
+			require.NoError(t, service.ReadPom(filepath.Join("testdata", tt.file)))
+
+			diagnostics, err := service.Run(tt.skip)
+			if tt.invalid {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []finding
+			for _, d := range diagnostics {
+				got = append(got, finding{d.Check, d.Severity, d.Position.Line, d.Fix != ""})
+				if d.Check == "duplicates" {
+					assert.Contains(t, d.Message, "conflicting versions ${guava.version}, 32.1.3-jre (first at line 11)")
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDoctorService_Fix(t *testing.T) {
+	network := &domain.NetworkError{URL: "https://search.maven.org", StatusCode: 503}
+
+	tests := []struct {
+		name       string
+		fail       string
+		wantFixed  int
+		wantFailed int
+		contains   []string
+		missing    []string
+	}{
+		{
+			name:      "all fixes",
+			wantFixed: 5,
+			contains: []string{
+				"<project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>",
+				"<version>3.3.0</version>",
+				"<version>5.11.0-RC1</version>\n      <scope>test</scope>",
+			},
+			missing: []string{"legacy.version", "32.1.3-jre"},
+		},
+		{
+			name:       "failing fix",
+			fail:       "plugin-versions",
+			wantFixed:  4,
+			wantFailed: 1,
+			// The offline fixes are still applied
+			contains: []string{
+				"<project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>",
+				"<version>5.11.0-RC1</version>\n      <scope>test</scope>",
+			},
+			missing: []string{"legacy.version", "32.1.3-jre", "<version>3.3.0</version>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := stubResolver{"org.apache.maven.plugins:maven-surefire-plugin": "3.3.0"}
+			service := NewDoctorService(xml.NewPomRepository(), fs.NewFileLocker(), stubLocator{}, loadTestRepository, DefaultChecks(resolver))
+			pomPath := loadTestPom(t, service, "doctor/pom.xml")
+
+			diagnostics, err := service.Run(nil)
+			require.NoError(t, err)
+			for _, d := range diagnostics {
+				if d.Check == tt.fail && d.apply != nil {
+					d.apply = func(domain.PomRepository) error { return network }
+				}
+			}
+
+			result, err := service.Fix(diagnostics)
+			require.NoError(t, err)
+			assert.Len(t, result.Fixed, tt.wantFixed)
+			require.Len(t, result.Failed, tt.wantFailed)
+			for _, failure := range result.Failed {
+				assert.Equal(t, tt.fail, failure.Diagnostic.Check)
+				assert.ErrorIs(t, failure.Err, network)
+			}
+
+			data, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, string(data), s)
+			}
+			for _, s := range tt.missing {
+				assert.NotContains(t, string(data), s)
+			}
+		})
+	}
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	assert.True(t, SeverityError.AtLeast(SeverityWarning))
+	assert.True(t, SeverityWarning.AtLeast(SeverityWarning))
+	assert.False(t, SeverityInfo.AtLeast(SeverityWarning))
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// dryRunPomRepository wraps a PomRepository so that every change stays in memory.
+type dryRunPomRepository struct {
+	domain.PomRepository
+}
+
+// NewDryRunPomRepository returns a PomRepository whose Save never touches the disk.
+// Use Render on the returned repository to inspect the would-be pom.xml.
+func NewDryRunPomRepository(repository domain.PomRepository) domain.PomRepository {
+	return &dryRunPomRepository{PomRepository: repository}
+}
+
+// Save discards the write; the changes remain available through Render.
+func (r *dryRunPomRepository) Save() error {
+	return nil
+}
+
+// dryRunLocker is a Locker that never locks.
+type dryRunLocker struct{}
+
+// dryRunLock is the lock returned by dryRunLocker.
+type dryRunLock struct{}
+
+// NewDryRunLocker returns a Locker for dry runs. A dry run writes nothing, so it
+// has nothing to serialize and must not create lock files either.
+func NewDryRunLocker() domain.Locker {
+	return dryRunLocker{}
+}
+
+// Lock returns immediately.
+func (dryRunLocker) Lock(string) (domain.Lock, error) {
+	return dryRunLock{}, nil
+}
+
+// Unlock does nothing.
+func (dryRunLock) Unlock() error {
+	return nil
+}
+
+// dryRunLicensePolicyStore wraps a LicensePolicyStore so that exceptions are not recorded.
+type dryRunLicensePolicyStore struct {
+	domain.LicensePolicyStore
+}
+
+// NewDryRunLicensePolicyStore returns a LicensePolicyStore whose AddException never touches the disk.
+func NewDryRunLicensePolicyStore(store domain.LicensePolicyStore) domain.LicensePolicyStore {
+	return &dryRunLicensePolicyStore{LicensePolicyStore: store}
+}
+
+// AddException discards the exception.
+func (s *dryRunLicensePolicyStore) AddException(*domain.LicenseException) error {
+	return nil
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// ExcludeDependencyService manages the exclusions of a project's dependencies.
+type ExcludeDependencyService struct {
+	projectLock
+}
+
+// NewExcludeDependencyService creates a new ExcludeDependencyService.
+func NewExcludeDependencyService(pomRepository domain.PomRepository, locker domain.Locker) *ExcludeDependencyService {
+	return &ExcludeDependencyService{
+		projectLock: newProjectLock(pomRepository, locker),
+	}
+}
+
+// ExclusionChange is an exclusion added to or removed from a dependency.
+type ExclusionChange struct {
+	Dependency *domain.Dependency
+	Exclusion  domain.Exclusion
+}
+
+// Match finds the dependencies selected by target; see DependencyMatch.
+func (s *ExcludeDependencyService) Match(target string) (*DependencyMatch, error) {
+	return matchDependencies(s.pomRepository, "", target)
+}
+
+// Exclude adds the exclusions to each dependency and saves the pom.xml once.
+// Exclusions a dependency already declares are skipped, so the result may be empty.
+func (s *ExcludeDependencyService) Exclude(dependencies []*domain.Dependency, exclusions []domain.Exclusion) ([]ExclusionChange, error) {
+	var changes []ExclusionChange
+
+	for _, dep := range uniqueDependencies(dependencies) {
+		for _, exclusion := range exclusions {
+			if dep.HasExclusion(exclusion) {
+				continue
+			}
+			if err := s.pomRepository.AddExclusion(dep, exclusion); err != nil {
+				return nil, fmt.Errorf("failed to add exclusion: %w", err)
+			}
+			dep.Exclusions = append(dep.Exclusions, exclusion)
+			changes = append(changes, ExclusionChange{Dependency: dep, Exclusion: exclusion})
+		}
+	}
+
+	if err := s.save(changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// RemoveExclusions removes the exclusions matching any of the patterns from each
+// dependency and saves the pom.xml once. Patterns use the syntax of
+// domain.ParseDependencySelector, e.g. "commons-logging" or "org.apache.logging.*:*".
+func (s *ExcludeDependencyService) RemoveExclusions(dependencies []*domain.Dependency, patterns []string) ([]ExclusionChange, error) {
+	selectors := make([]*domain.DependencySelector, len(patterns))
+	for i, pattern := range patterns {
+		selector, err := domain.ParseDependencySelector(pattern)
+		if err != nil {
+			return nil, err
+		}
+		selectors[i] = selector
+	}
+
+	var changes []ExclusionChange
+
+	for _, dep := range uniqueDependencies(dependencies) {
+		var kept []domain.Exclusion
+		for _, exclusion := range dep.Exclusions {
+			if !matchesAny(selectors, exclusion) {
+				kept = append(kept, exclusion)
+				continue
+			}
+			if err := s.pomRepository.RemoveExclusion(dep, exclusion); err != nil {
+				return nil, fmt.Errorf("failed to remove exclusion: %w", err)
+			}
+			changes = append(changes, ExclusionChange{Dependency: dep, Exclusion: exclusion})
+		}
+		dep.Exclusions = kept
+	}
+
+	if len(changes) == 0 {
+		return nil, &domain.NotFoundError{Kind: "exclusion", Name: strings.Join(patterns, ", ")}
+	}
+
+	if err := s.save(changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// save writes the pom.xml if anything changed.
+func (s *ExcludeDependencyService) save(changes []ExclusionChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+
+	return nil
+}
+
+// matchesAny reports whether any selector matches the exclusion.
+func matchesAny(selectors []*domain.DependencySelector, exclusion domain.Exclusion) bool {
+	for _, selector := range selectors {
+		if selector.MatchesCoordinates(exclusion.GroupID, exclusion.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestExcludeDependencyService(t *testing.T) {
+	logging := domain.Exclusion{GroupID: "commons-logging", ArtifactID: "commons-logging"}
+	log4j := []domain.Exclusion{
+		{GroupID: "org.apache.logging.log4j", ArtifactID: "*"},
+		{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-api"},
+	}
+
+	// Each case first excludes excluded from the dependencies matching target,
+	// then either excludes exclude or removes the exclusions matching remove
+	tests := []struct {
+		name     string
+		target   string
+		excluded []domain.Exclusion
+		exclude  []domain.Exclusion
+		remove   []string
+		changes  int
+		want     []domain.Exclusion
+		notFound bool
+	}{
+		{
+			name:    "exclude",
+			target:  "*:jackson-core",
+			exclude: []domain.Exclusion{logging},
+			changes: 2,
+			want:    []domain.Exclusion{logging},
+		},
+		{
+			name:     "exclude again",
+			target:   "*:jackson-core",
+			excluded: []domain.Exclusion{logging},
+			exclude:  []domain.Exclusion{logging},
+			want:     []domain.Exclusion{logging},
+		},
+		{
+			name:     "remove matching exclusions",
+			target:   "org.example.shaded:jackson-core",
+			excluded: append(log4j, logging),
+			remove:   []string{"org.apache.logging.*:*"},
+			changes:  2,
+			want:     []domain.Exclusion{logging},
+		},
+		{
+			name:     "remove unknown exclusion",
+			target:   "org.example.shaded:jackson-core",
+			excluded: []domain.Exclusion{logging},
+			remove:   []string{"org.slf4j:*"},
+			notFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewExcludeDependencyService(xml.NewPomRepository(), fs.NewFileLocker())
+			loadTestPom(t, service, "remove.xml")
+
+			match, err := service.Match(tt.target)
+			require.NoError(t, err)
+			if tt.excluded != nil {
+				_, err = service.Exclude(match.Dependencies, tt.excluded)
+				require.NoError(t, err)
+				match, err = service.Match(tt.target)
+				require.NoError(t, err)
+			}
+
+			var changes []ExclusionChange
+			if tt.remove != nil {
+				changes, err = service.RemoveExclusions(match.Dependencies, tt.remove)
+			} else {
+				changes, err = service.Exclude(match.Dependencies, tt.exclude)
+			}
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, changes, tt.changes)
+
+			match, err = service.Match(tt.target)
+			require.NoError(t, err)
+			for _, dep := range match.Dependencies {
+				assert.Equal(t, tt.want, dep.Exclusions, dep.ID())
+			}
+		})
+	}
+}
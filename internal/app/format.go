@@ -0,0 +1,59 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// FormatService rewrites a pom.xml in canonical form: elements in the order of
+// the POM reference, sorted dependencies and consistent indentation.
+type FormatService struct {
+	projectLock
+}
+
+// NewFormatService creates a new FormatService.
+func NewFormatService(pomRepository domain.PomRepository, locker domain.Locker) *FormatService {
+	return &FormatService{
+		projectLock: newProjectLock(pomRepository, locker),
+	}
+}
+
+// Format formats the pom.xml, sorting dependencies by keys, and saves it if
+// anything changed. It reports whether the pom.xml changed.
+func (s *FormatService) Format(keys []domain.DependencySortKey) (bool, error) {
+	changed, err := s.Check(keys)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return false, fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+	return true, nil
+}
+
+// Check formats the pom.xml in memory without saving it, and reports whether
+// formatting changes it. Render the repository to see the formatted pom.xml.
+func (s *FormatService) Check(keys []domain.DependencySortKey) (bool, error) {
+	before, err := s.pomRepository.Render()
+	if err != nil {
+		return false, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	if err := s.pomRepository.Format(keys); err != nil {
+		return false, fmt.Errorf("failed to format pom.xml: %w", err)
+	}
+
+	after, err := s.pomRepository.Render()
+	if err != nil {
+		return false, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	return !bytes.Equal(before, after), nil
+}
+
+// ReadPom loads the pom.xml without locking the project, for Check.
+func (s *FormatService) ReadPom(path string) error {
+	return s.pomRepository.Load(path)
+}
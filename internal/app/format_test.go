@@ -0,0 +1,58 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestFormatService(t *testing.T) {
+	formatted, err := os.ReadFile(filepath.Join("testdata", "formatted.xml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		file    string
+		changed bool
+	}{
+		{file: "unformatted.xml", changed: true},
+		// A formatted pom.xml stays as it is
+		{file: "formatted.xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			pomPath := copyTestPom(t, tt.file)
+			original, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+
+			checker := NewFormatService(xml.NewPomRepository(), nil)
+			require.NoError(t, checker.ReadPom(pomPath))
+			changed, err := checker.Check(domain.DefaultDependencySortKeys)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+
+			// Check leaves the file alone
+			data, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(original), string(data))
+
+			service := NewFormatService(xml.NewPomRepository(), fs.NewFileLocker())
+			require.NoError(t, service.LoadPom(pomPath))
+			changed, err = service.Format(domain.DefaultDependencySortKeys)
+			require.NoError(t, service.Close())
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+
+			data, err = os.ReadFile(pomPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(data))
+		})
+	}
+}
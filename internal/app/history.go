@@ -0,0 +1,140 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+// HistoryService records the changes mvnx makes to a pom.xml and reverts them.
+type HistoryService struct {
+	store   domain.HistoryStore
+	locker  domain.Locker
+	pomPath string
+}
+
+// NewHistoryService creates a new HistoryService for the pom.xml at pomPath.
+func NewHistoryService(store domain.HistoryStore, locker domain.Locker, pomPath string) *HistoryService {
+	return &HistoryService{
+		store:   store,
+		locker:  locker,
+		pomPath: pomPath,
+	}
+}
+
+// Record stores a change to the pom.xml. Changes that left the file identical are ignored.
+// Callers must hold the project lock, i.e. record before closing the service that made the change.
+func (s *HistoryService) Record(description string, before, after []byte) error {
+	if bytes.Equal(before, after) {
+		return nil
+	}
+
+	return s.store.Append(&domain.HistoryEntry{
+		Time:        time.Now().UTC(),
+		Description: description,
+		Before:      before,
+		After:       after,
+	})
+}
+
+// Entries returns the recorded changes, oldest first.
+func (s *HistoryService) Entries() ([]*domain.HistoryEntry, error) {
+	return s.store.List()
+}
+
+// UndoResult describes the outcome of an Undo.
+type UndoResult struct {
+	// Undone lists the reverted entries, newest first
+	Undone []*domain.HistoryEntry
+
+	// Before is the pom.xml before the undo; After is the restored pom.xml
+	Before []byte
+	After  []byte
+}
+
+// Undo reverts the last n recorded changes.
+// It refuses to run when the pom.xml was edited outside mvnx after, or between,
+// those changes, since reverting would silently discard the manual edits; force
+// overrides the check. With dryRun, the result is computed but nothing is written.
+// The project is locked for the duration of the undo.
+func (s *HistoryService) Undo(n int, force, dryRun bool) (result *UndoResult, err error) {
+	if n < 1 {
+		return nil, &domain.ValidationError{Field: "count", Message: fmt.Sprintf("invalid number of operations to undo: %d", n)}
+	}
+
+	lock, err := s.locker.Lock(s.pomPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if unlockErr := unlock(lock); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, &domain.NotFoundError{Kind: "operation to undo", Name: s.pomPath}
+	}
+	if n > len(entries) {
+		return nil, &domain.ValidationError{
+			Field:   "count",
+			Message: fmt.Sprintf("cannot undo %d operations: only %d recorded", n, len(entries)),
+		}
+	}
+
+	current, err := os.ReadFile(s.pomPath)
+	if err != nil {
+		return nil, &domain.PomParseError{Path: s.pomPath, Err: err}
+	}
+
+	undone := entries[len(entries)-n:]
+
+	if !force {
+		if !bytes.Equal(current, undone[len(undone)-1].After) {
+			return nil, &domain.ConflictError{
+				Path:   s.pomPath,
+				Reason: "pom.xml was modified after the last mvnx operation; rerun with --force to discard those changes",
+			}
+		}
+		for i := 1; i < len(undone); i++ {
+			if !bytes.Equal(undone[i].Before, undone[i-1].After) {
+				return nil, &domain.ConflictError{
+					Path:   s.pomPath,
+					Reason: fmt.Sprintf("pom.xml was modified outside mvnx before operation %d; rerun with --force to discard those changes", undone[i].ID),
+				}
+			}
+		}
+	}
+
+	result = &UndoResult{
+		Before: current,
+		After:  undone[0].Before,
+	}
+	for i := len(undone) - 1; i >= 0; i-- {
+		result.Undone = append(result.Undone, undone[i])
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := fs.WriteFileAtomic(s.pomPath, result.After, 0644); err != nil {
+		return nil, fmt.Errorf("failed to restore pom.xml: %w", err)
+	}
+
+	for _, entry := range result.Undone {
+		if err := s.store.Remove(entry.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+func newTestHistory(t *testing.T, contents string) (*HistoryService, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	pomPath := filepath.Join(dir, "pom.xml")
+	require.NoError(t, os.WriteFile(pomPath, []byte(contents), 0644))
+
+	return NewHistoryService(fs.NewHistoryStore(dir), fs.NewFileLocker(), pomPath), pomPath
+}
+
+func TestHistoryService_Undo(t *testing.T) {
+	service, pomPath := newTestHistory(t, "v2")
+	require.NoError(t, service.Record("first", []byte("v0"), []byte("v1")))
+	require.NoError(t, service.Record("second", []byte("v1"), []byte("v2")))
+
+	result, err := service.Undo(2, false, false)
+	require.NoError(t, err)
+
+	require.Len(t, result.Undone, 2)
+	assert.Equal(t, "second", result.Undone[0].Description)
+	assert.Equal(t, "first", result.Undone[1].Description)
+
+	data, err := os.ReadFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, "v0", string(data))
+
+	entries, err := service.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHistoryService_UndoDryRun(t *testing.T) {
+	service, pomPath := newTestHistory(t, "v1")
+	require.NoError(t, service.Record("first", []byte("v0"), []byte("v1")))
+
+	result, err := service.Undo(1, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, "v0", string(result.After))
+
+	data, err := os.ReadFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(data))
+}
+
+func TestHistoryService_UndoRefusesManualEdits(t *testing.T) {
+	service, pomPath := newTestHistory(t, "edited by hand")
+	require.NoError(t, service.Record("first", []byte("v0"), []byte("v1")))
+
+	_, err := service.Undo(1, false, false)
+	var conflictErr *domain.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+
+	_, err = service.Undo(1, true, false)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(pomPath)
+	require.NoError(t, err)
+	assert.Equal(t, "v0", string(data))
+}
+
+func TestHistoryService_UndoRefusesGapsBetweenOperations(t *testing.T) {
+	service, _ := newTestHistory(t, "v3")
+	require.NoError(t, service.Record("first", []byte("v0"), []byte("v1")))
+	require.NoError(t, service.Record("second", []byte("v2"), []byte("v3")))
+
+	_, err := service.Undo(1, false, true)
+	require.NoError(t, err)
+
+	_, err = service.Undo(2, false, true)
+	var conflictErr *domain.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestHistoryService_RecordSkipsNoOps(t *testing.T) {
+	service, _ := newTestHistory(t, "v0")
+	require.NoError(t, service.Record("noop", []byte("v0"), []byte("v0")))
+
+	entries, err := service.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
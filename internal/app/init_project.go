@@ -1,22 +1,93 @@
 package app
 
 import (
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/archetype"
 	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
 )
 
 // InitProjectService handles project initialization.
 type InitProjectService struct {
 	initializer *fs.ProjectInitializer
+	archetypes  domain.ArchetypeRepository
 }
 
 // NewInitProjectService creates a new InitProjectService.
-func NewInitProjectService() *InitProjectService {
+// archetypes is only used to create projects from archetypes and may be nil otherwise.
+func NewInitProjectService(archetypes domain.ArchetypeRepository) *InitProjectService {
 	return &InitProjectService{
 		initializer: fs.NewProjectInitializer(),
+		archetypes:  archetypes,
 	}
 }
 
-// Init initializes a new Maven project in the specified directory.
-func (s *InitProjectService) Init(path string) error {
-	return s.initializer.InitProject(path)
+// Preview returns the files that Init would create in the specified directory.
+// template is a built-in template name or the directory of a user-defined template.
+func (s *InitProjectService) Preview(path, template string, spec *domain.ProjectSpec) ([]fs.ProjectFile, error) {
+	tmpl, err := fs.LoadProjectTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.initializer.PreviewProject(path, tmpl, spec)
+}
+
+// Init initializes a new Maven project from a template in the specified directory
+// and returns the files it created.
+func (s *InitProjectService) Init(path, template string, spec *domain.ProjectSpec) ([]fs.ProjectFile, error) {
+	tmpl, err := fs.LoadProjectTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.initializer.InitProject(path, tmpl, spec)
+}
+
+// PreviewArchetype returns the archetype, completed from the catalog, and the files
+// InitArchetype would create from it in the specified directory.
+// properties set archetype properties and override those derived from spec.
+func (s *InitProjectService) PreviewArchetype(path string, requested *domain.Archetype, spec *domain.ProjectSpec, properties map[string]string) (*domain.Archetype, []fs.ProjectFile, error) {
+	found, err := s.archetypes.Find(requested)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jar, err := s.archetypes.Fetch(found)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vars := map[string]string{
+		"groupId":    spec.GroupID,
+		"artifactId": spec.ArtifactID,
+		"version":    spec.Version,
+		"package":    spec.Package,
+	}
+	for key, value := range properties {
+		vars[key] = value
+	}
+
+	files, err := archetype.Generate(jar, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.initializer.CheckProject(path, files); err != nil {
+		return nil, nil, err
+	}
+	return found, files, nil
+}
+
+// InitArchetype creates a new Maven project from an archetype in the specified directory,
+// see PreviewArchetype, and returns the archetype used and the files it created.
+func (s *InitProjectService) InitArchetype(path string, requested *domain.Archetype, spec *domain.ProjectSpec, properties map[string]string) (*domain.Archetype, []fs.ProjectFile, error) {
+	found, files, err := s.PreviewArchetype(path, requested, spec, properties)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.initializer.WriteProject(path, files); err != nil {
+		return nil, nil, err
+	}
+	return found, files, nil
 }
@@ -0,0 +1,228 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// minJavaRelease is the oldest release mvnx java set accepts, the oldest current JDKs still compile for
+const minJavaRelease = 8
+
+// JavaService reads and changes the Java release level of a project.
+type JavaService struct {
+	projectLock
+
+	inspector domain.ClassFileInspector
+}
+
+// NewJavaService creates a new JavaService.
+func NewJavaService(pomRepository domain.PomRepository, locker domain.Locker, inspector domain.ClassFileInspector) *JavaService {
+	return &JavaService{
+		projectLock: newProjectLock(pomRepository, locker),
+		inspector:   inspector,
+	}
+}
+
+// JavaLevel is a declaration of the Java release level with its effective release.
+type JavaLevel struct {
+	Declaration *domain.JavaDeclaration
+
+	// Release follows a property reference to the property's value; it is 0 when
+	// the value cannot be determined, e.g. for a property defined by a parent
+	Release int
+}
+
+// JavaChange is a value changed or added by Set.
+type JavaChange struct {
+	Location string
+
+	// From is empty for an added property
+	From string
+	To   string
+}
+
+// NewerDependency is a dependency compiled for a newer Java release than the project's.
+type NewerDependency struct {
+	Dependency *domain.Dependency
+	Version    string
+	Release    int
+}
+
+// Show returns the declarations of the Java release level in the pom.xml.
+// Property references are resolved with the properties of the pom.xml itself.
+func (s *JavaService) Show() ([]*JavaLevel, error) {
+	declarations, err := s.pomRepository.GetJavaDeclarations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	properties, err := s.pomRepository.GetProperties()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	levels := make([]*JavaLevel, len(declarations))
+	for i, decl := range declarations {
+		levels[i] = &JavaLevel{Declaration: decl, Release: resolveRelease(decl, properties)}
+	}
+	return levels, nil
+}
+
+// Releases returns the distinct releases of the levels, sorted; more than one means
+// the declarations disagree.
+func Releases(levels []*JavaLevel) []int {
+	seen := make(map[int]bool)
+	var releases []int
+	for _, level := range levels {
+		if level.Release != 0 && !seen[level.Release] {
+			seen[level.Release] = true
+			releases = append(releases, level.Release)
+		}
+	}
+	sort.Ints(releases)
+	return releases
+}
+
+// Set changes every declaration of the Java release level to release and saves the pom.xml.
+// Declarations referring to a property follow it: the property is changed once, whatever
+// its name, or added to the pom.xml if a parent defines it. Without any declaration, maven.compiler.release
+// is added, or maven.compiler.source and target for Java 8, which has no --release.
+func (s *JavaService) Set(release int) ([]JavaChange, error) {
+	if release < minJavaRelease {
+		return nil, &domain.ValidationError{
+			Field:   "java",
+			Message: fmt.Sprintf("invalid Java version: %d (the oldest supported release is %d)", release, minJavaRelease),
+		}
+	}
+
+	declarations, err := s.pomRepository.GetJavaDeclarations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	properties, err := s.pomRepository.GetProperties()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	var changes []JavaChange
+	var inherited []string
+	followed := make(map[string]bool)
+	for _, decl := range declarations {
+		if name := decl.PropertyReference(); name != "" {
+			if followed[name] {
+				continue
+			}
+			followed[name] = true
+
+			current, ok := properties[name]
+			if !ok {
+				inherited = append(inherited, name)
+				continue
+			}
+			// Properties such as java.version are declarations themselves, set below
+			property := &domain.JavaDeclaration{Location: "properties/" + name, Kind: decl.Kind, Value: current}
+			if slices.ContainsFunc(declarations, func(d *domain.JavaDeclaration) bool { return d.Location == property.Location }) {
+				continue
+			}
+
+			value := property.WithRelease(release)
+			if value == current {
+				continue
+			}
+			if err := s.pomRepository.SetProperty(name, value); err != nil {
+				return nil, fmt.Errorf("failed to set property %s: %w", name, err)
+			}
+			changes = append(changes, JavaChange{Location: property.Location, From: current, To: value})
+			continue
+		}
+
+		value := decl.WithRelease(release)
+		if value == decl.Value {
+			continue
+		}
+		if err := s.pomRepository.SetJavaDeclaration(decl.Location, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", decl.Location, err)
+		}
+		changes = append(changes, JavaChange{Location: decl.Location, From: decl.Value, To: value})
+	}
+
+	added := inherited
+	if len(declarations) == 0 {
+		added = []string{"maven.compiler.release"}
+		if release < 9 {
+			added = []string{"maven.compiler.source", "maven.compiler.target"}
+		}
+	}
+	for _, name := range added {
+		value := strconv.Itoa(release)
+		if release < 9 {
+			value = "1." + value
+		}
+		if err := s.pomRepository.SetProperty(name, value); err != nil {
+			return nil, fmt.Errorf("failed to set property %s: %w", name, err)
+		}
+		changes = append(changes, JavaChange{Location: "properties/" + name, To: value})
+	}
+
+	if len(changes) > 0 {
+		if err := s.pomRepository.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+		}
+	}
+
+	return changes, nil
+}
+
+// CheckDependencies returns the dependencies whose classes target a newer release than
+// release, and how many jar dependencies could not be checked because their version is
+// unknown or their jar is missing from the local repository or cannot be read. The
+// check is advisory, so an unreadable jar is counted rather than failing it.
+func (s *JavaService) CheckDependencies(dependencies []*domain.DeclaredDependency, release int) ([]NewerDependency, int, error) {
+	var newer []NewerDependency
+	unchecked := 0
+
+	for _, declared := range dependencies {
+		dep := declared.Dependency
+		if declared.Managed || dep.EffectiveType() != domain.DefaultDependencyType {
+			continue
+		}
+		if declared.ResolvedVersion == "" {
+			unchecked++
+			continue
+		}
+
+		depRelease, err := s.inspector.JavaRelease(dep, declared.ResolvedVersion)
+		if err != nil {
+			unchecked++
+			continue
+		}
+		if depRelease > release {
+			newer = append(newer, NewerDependency{Dependency: dep, Version: declared.ResolvedVersion, Release: depRelease})
+		}
+	}
+
+	return newer, unchecked, nil
+}
+
+// resolveRelease returns the release of a declaration, following a reference to a property.
+func resolveRelease(decl *domain.JavaDeclaration, properties map[string]string) int {
+	name := decl.PropertyReference()
+	if name == "" {
+		return decl.Release()
+	}
+
+	value, ok := properties[name]
+	if !ok {
+		return 0
+	}
+	return (&domain.JavaDeclaration{Kind: decl.Kind, Value: value}).Release()
+}
+
+// ReadPom loads the pom.xml from the specified path without locking the project,
+// for read-only operations such as Show.
+func (s *JavaService) ReadPom(path string) error {
+	return s.pomRepository.Load(path)
+}
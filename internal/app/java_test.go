@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// stubInspector maps artifactIds to the Java release of their jar; others are
+// not found, and a negative release stands for an unreadable jar.
+type stubInspector map[string]int
+
+func (s stubInspector) JavaRelease(dep *domain.Dependency, version string) (int, error) {
+	release, ok := s[dep.ArtifactID]
+	if !ok {
+		return 0, &domain.NotFoundError{Kind: "jar", Name: dep.ArtifactID}
+	}
+	if release < 0 {
+		return 0, errors.New("failed to read Main.class: not a class file")
+	}
+	return release, nil
+}
+
+func TestJavaService_Set(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		release     int
+		wantChanges []JavaChange
+		wantErr     bool
+	}{
+		{
+			name:    "properties and plugin",
+			file:    "java/properties.xml",
+			release: 21,
+			wantChanges: []JavaChange{
+				{Location: "properties/java.version", From: "11", To: "21"},
+				{Location: "properties/jdk.release", To: "21"},
+			},
+		},
+		{
+			name:    "property with any name",
+			file:    "java/custom-property.xml",
+			release: 21,
+			wantChanges: []JavaChange{
+				{Location: "properties/jdk", From: "11", To: "21"},
+			},
+		},
+		{
+			name:    "no declaration",
+			file:    "java/undeclared.xml",
+			release: 17,
+			wantChanges: []JavaChange{
+				{Location: "properties/maven.compiler.release", To: "17"},
+			},
+		},
+		{
+			name:    "no declaration, Java 8",
+			file:    "java/undeclared.xml",
+			release: 8,
+			wantChanges: []JavaChange{
+				{Location: "properties/maven.compiler.source", To: "1.8"},
+				{Location: "properties/maven.compiler.target", To: "1.8"},
+			},
+		},
+		{
+			name:    "up to date",
+			file:    "java/release.xml",
+			release: 17,
+		},
+		{
+			name:    "too old",
+			file:    "java/undeclared.xml",
+			release: 7,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewJavaService(xml.NewPomRepository(), fs.NewFileLocker(), stubInspector{})
+			loadTestPom(t, service, tt.file)
+
+			changes, err := service.Set(tt.release)
+			if tt.wantErr {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanges, changes)
+
+			levels, err := service.Show()
+			require.NoError(t, err)
+			assert.Equal(t, []int{tt.release}, Releases(levels))
+		})
+	}
+}
+
+func TestJavaService_CheckDependencies(t *testing.T) {
+	service := NewJavaService(xml.NewPomRepository(), fs.NewFileLocker(), stubInspector{"modern": 21, "classic": 8, "corrupt": -1})
+	loadTestPom(t, service, "java/undeclared.xml")
+
+	declared := func(artifactID, version string) *domain.DeclaredDependency {
+		return &domain.DeclaredDependency{
+			Dependency:      &domain.Dependency{GroupID: "com.acme", ArtifactID: artifactID, Version: version},
+			ResolvedVersion: version,
+		}
+	}
+
+	newer, unchecked, err := service.CheckDependencies([]*domain.DeclaredDependency{
+		declared("modern", "2.0"),
+		declared("classic", "1.0"),
+		declared("unknown", "1.0"),
+		declared("unresolved", ""),
+		declared("corrupt", "1.0"),
+	}, 17)
+	require.NoError(t, err)
+	require.Len(t, newer, 1)
+	assert.Equal(t, "modern", newer[0].Dependency.ArtifactID)
+	assert.Equal(t, 21, newer[0].Release)
+	assert.Equal(t, 3, unchecked)
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// ArtifactLicenses are the licenses of an artifact of the project.
+type ArtifactLicenses struct {
+	// Dependency is the artifact with the version the project uses
+	Dependency *domain.Dependency
+
+	// Scope is the effective scope of the artifact, see GraphRequest
+	Scope string
+
+	// Path lists the artifacts leading to a transitive dependency, see GraphRequest;
+	// empty for direct dependencies
+	Path []string
+
+	// Licenses are those of the artifact, or inherited from its nearest parent
+	// declaring some; empty when none is declared
+	Licenses []*domain.License
+
+	// Source is the groupId:artifactId:version of the pom declaring the licenses,
+	// the artifact itself or one of its parents; empty when none is declared
+	Source string
+
+	// Problem explains why the licenses could not be read, e.g. the pom is not
+	// available; empty when they were
+	Problem string
+}
+
+// LicenseReport is the outcome of LicenseService.Report.
+type LicenseReport struct {
+	// Artifacts are in the order of the dependency graph
+	Artifacts []*ArtifactLicenses
+
+	// Unresolved lists the artifacts whose pom is not available, so their
+	// dependencies are missing from the report
+	Unresolved []string
+}
+
+// artifactLicenses caches the licenses read for an artifact
+type artifactLicenses struct {
+	licenses []*domain.License
+	source   string
+	err      error
+}
+
+// LicenseService reports the licenses of the dependencies of a project, read
+// from the <licenses> of their poms and normalized to SPDX identifiers.
+type LicenseService struct {
+	locator domain.PomLocator
+	load    func(path string) (domain.PomRepository, error)
+	graph   *ListDependenciesService
+
+	// cache holds the licenses read, by groupId:artifactId:version, since many
+	// artifacts share a parent
+	cache map[string]*artifactLicenses
+}
+
+// NewLicenseService creates a new LicenseService. The locator finds the poms of
+// the dependencies and of their parents; it may download them.
+func NewLicenseService(
+	pomRepository domain.PomRepository,
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+) *LicenseService {
+	return &LicenseService{
+		locator: locator,
+		load:    load,
+		graph:   NewListDependenciesService(pomRepository, locator, load),
+		cache:   make(map[string]*artifactLicenses),
+	}
+}
+
+// LoadPom loads the pom.xml from the specified path.
+func (s *LicenseService) LoadPom(path string) error {
+	return s.graph.LoadPom(path)
+}
+
+// Report returns the licenses of the dependencies of the project, in the order of
+// the dependency graph. Each artifact is reported once, at the version Maven picks.
+// Unless transitive is set, only the dependencies declared by the project and its
+// parents are reported.
+func (s *LicenseService) Report(transitive bool) (*LicenseReport, error) {
+	graph, err := s.graph.Graph(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dependency graph: %w", err)
+	}
+
+	report := &LicenseReport{}
+	if transitive {
+		report.Unresolved = graph.Unresolved
+	}
+	seen := make(map[string]bool)
+	for _, request := range graph.Requests {
+		dep := request.Dependency
+		if seen[dep.Key()] || (!transitive && len(request.Path) > 0) || dep.IsImport() {
+			continue
+		}
+		seen[dep.Key()] = true
+
+		artifact := &ArtifactLicenses{Dependency: dep, Scope: request.Scope, Path: request.Path}
+		report.Artifacts = append(report.Artifacts, artifact)
+		if dep.Version == "" {
+			artifact.Problem = "the version is unknown"
+			continue
+		}
+
+		artifact.Licenses, artifact.Source, err = s.Licenses(dep.GroupID, dep.ArtifactID, dep.Version)
+		if err != nil {
+			artifact.Problem = err.Error()
+		}
+	}
+
+	return report, nil
+}
+
+// Licenses returns the licenses of groupId:artifactId:version and the coordinates
+// of the pom declaring them. As in Maven, an artifact without <licenses> inherits
+// those of its parent. Returns empty licenses if no pom in the chain declares any,
+// a *NotFoundError if a pom of the chain is not available, and a *NetworkError if
+// the locator fails to download one.
+func (s *LicenseService) Licenses(groupID, artifactID, version string) ([]*domain.License, string, error) {
+	return s.lookup(groupID, artifactID, version, 0)
+}
+
+// lookup returns the cached licenses of an artifact, reading them if needed.
+func (s *LicenseService) lookup(groupID, artifactID, version string, depth int) ([]*domain.License, string, error) {
+	coordinates := groupID + ":" + artifactID + ":" + version
+	if cached, ok := s.cache[coordinates]; ok {
+		return cached.licenses, cached.source, cached.err
+	}
+
+	licenses, source, err := s.readLicenses(groupID, artifactID, version, depth)
+	s.cache[coordinates] = &artifactLicenses{licenses: licenses, source: source, err: err}
+	return licenses, source, err
+}
+
+// readLicenses reads the licenses of an artifact, following its parents.
+func (s *LicenseService) readLicenses(groupID, artifactID, version string, depth int) ([]*domain.License, string, error) {
+	coordinates := groupID + ":" + artifactID + ":" + version
+	path, err := s.locator.Locate(groupID, artifactID, version)
+	if err != nil {
+		return nil, "", err
+	}
+	repository, err := s.load(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the pom of %s: %w", coordinates, err)
+	}
+
+	licenses, err := repository.GetLicenses()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the licenses of %s: %w", coordinates, err)
+	}
+	if len(licenses) > 0 {
+		return licenses, coordinates, nil
+	}
+
+	parent, err := repository.GetParent()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the parent of %s: %w", coordinates, err)
+	}
+	if parent == nil || depth >= maxModelDepth {
+		return nil, "", nil
+	}
+	return s.lookup(parent.GroupID, parent.ArtifactID, parent.Version, depth+1)
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestLicenseService_Report(t *testing.T) {
+	type artifact struct {
+		id       string
+		scope    string
+		path     []string
+		source   string
+		licenses []*domain.License
+		problem  bool
+	}
+	apache := []*domain.License{{Name: "Apache License, Version 2.0", SPDX: "Apache-2.0"}}
+
+	tests := []struct {
+		name       string
+		transitive bool
+		want       []artifact
+		unresolved []string
+	}{
+		{
+			name:       "transitive",
+			transitive: true,
+			want: []artifact{
+				// a inherits the licenses of its parent
+				{"org.example:a", "runtime", nil, "org.example:parent:1", apache, false},
+				{"org.example:missing", "compile", nil, "", nil, true},
+				// c declares no license, and is runtime through a
+				{"org.example:c", "runtime", []string{"org.example:a:1.0"}, "", nil, false},
+			},
+			unresolved: []string{"org.example:missing:1.0"},
+		},
+		{
+			name: "direct",
+			want: []artifact{
+				{"org.example:a", "runtime", nil, "org.example:parent:1", apache, false},
+				{"org.example:missing", "compile", nil, "", nil, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locator := repositoryLocator(filepath.Join("testdata", "licenses", "repository"))
+			service := NewLicenseService(xml.NewPomRepository(), locator, loadTestRepository)
+			require.NoError(t, service.LoadPom(filepath.Join("testdata", "licenses", "pom.xml")))
+
+			report, err := service.Report(tt.transitive)
+			require.NoError(t, err)
+			assert.Equal(t, tt.unresolved, report.Unresolved)
+
+			var got []artifact
+			for _, a := range report.Artifacts {
+				got = append(got, artifact{a.Dependency.ID(), a.Scope, a.Path, a.Source, a.Licenses, a.Problem != ""})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
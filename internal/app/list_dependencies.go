@@ -0,0 +1,392 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// maxModelDepth bounds the chain of parents and BOM imports followed when resolving versions
+const maxModelDepth = 16
+
+// propertyReference matches a ${name} reference in a pom.xml value
+var propertyReference = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ListFilter selects the dependencies returned by List.
+// Empty fields do not filter. If neither Direct nor Managed is set, both are listed.
+type ListFilter struct {
+	// Scope keeps only dependencies with this scope
+	Scope string
+
+	// GroupPattern keeps only dependencies whose groupId matches this glob pattern
+	GroupPattern string
+
+	// Direct lists the entries of <dependencies>
+	Direct bool
+
+	// Managed lists the entries of <dependencyManagement>
+	Managed bool
+
+	// Profile lists the <dependencies> of this profile instead of the project's;
+	// it cannot be combined with Managed
+	Profile string
+}
+
+// ListDependenciesService lists the dependencies declared in a project with their effective versions.
+// Parents and imported BOMs are read from disk only: the parent's relativePath and the
+// local Maven repository. Versions defined in poms that are not available locally are
+// reported as unresolved.
+type ListDependenciesService struct {
+	pomRepository domain.PomRepository
+	locator       domain.PomLocator
+
+	// load reads another pom.xml, such as a parent or a BOM
+	load func(path string) (domain.PomRepository, error)
+
+	pomPath string
+}
+
+// NewListDependenciesService creates a new ListDependenciesService.
+func NewListDependenciesService(
+	pomRepository domain.PomRepository,
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+) *ListDependenciesService {
+	return &ListDependenciesService{
+		pomRepository: pomRepository,
+		locator:       locator,
+		load:          load,
+	}
+}
+
+// LoadPom loads the pom.xml from the specified path.
+func (s *ListDependenciesService) LoadPom(path string) error {
+	s.pomPath = path
+	return s.pomRepository.Load(path)
+}
+
+// List returns the declared dependencies matching the filter, in pom.xml order:
+// the entries of <dependencies> first, then those of <dependencyManagement>.
+func (s *ListDependenciesService) List(filter ListFilter) ([]*domain.DeclaredDependency, error) {
+	var group *domain.DependencySelector
+	if filter.GroupPattern != "" {
+		selector, err := domain.ParseDependencySelector(filter.GroupPattern + ":*")
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "group", Message: "invalid groupId pattern: " + filter.GroupPattern}
+		}
+		group = selector
+	}
+
+	if !filter.Direct && !filter.Managed {
+		filter.Direct, filter.Managed = true, true
+	}
+
+	model, err := s.resolveModel(s.pomRepository, s.pomPath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var declared []*domain.DeclaredDependency
+	add := func(dependencies []*domain.Dependency, managed bool) {
+		for _, dep := range dependencies {
+			if filter.Scope != "" && dep.Scope != filter.Scope {
+				continue
+			}
+			if group != nil && !group.Matches(dep) {
+				continue
+			}
+			declared = append(declared, model.declare(dep, managed))
+		}
+	}
+
+	if filter.Profile != "" {
+		dependencies, err := s.pomRepository.GetProfileDependencies(filter.Profile)
+		if err != nil {
+			return nil, err
+		}
+		add(dependencies, false)
+		return declared, nil
+	}
+
+	if filter.Direct {
+		dependencies, err := s.pomRepository.GetDependencies()
+		if err != nil {
+			return nil, err
+		}
+		add(dependencies, false)
+	}
+
+	if filter.Managed {
+		dependencies, err := s.pomRepository.GetManagedDependencies()
+		if err != nil {
+			return nil, err
+		}
+		add(dependencies, true)
+	}
+
+	return declared, nil
+}
+
+// managedVersion is a version defined in a <dependencyManagement> section.
+type managedVersion struct {
+	version string
+	source  domain.VersionSource
+	origin  string
+}
+
+// unresolvedModel is a parent or BOM whose pom.xml is not available locally.
+type unresolvedModel struct {
+	source      domain.VersionSource
+	coordinates string
+}
+
+// effectiveModel holds what is needed to resolve versions in a pom.xml: its
+// properties merged with those of its parents, and its managed versions.
+type effectiveModel struct {
+	properties map[string]string
+
+	// direct holds the versions managed by the pom or its parents, uninterpolated,
+	// since properties of a child override those of its parents. imported holds the
+	// versions coming from BOMs, already interpolated within the BOM. As in Maven,
+	// direct entries win over imported ones.
+	direct   map[string]managedVersion
+	imported map[string]managedVersion
+
+	// unresolved lists the parents and BOMs that could not be read
+	unresolved []unresolvedModel
+}
+
+// resolveModel builds the effective model of the pom.xml at path, following its
+// parents and imported BOMs.
+func (s *ListDependenciesService) resolveModel(repository domain.PomRepository, path string, depth int) (*effectiveModel, error) {
+	model := &effectiveModel{
+		properties: make(map[string]string),
+		direct:     make(map[string]managedVersion),
+		imported:   make(map[string]managedVersion),
+	}
+
+	parent, err := repository.GetParent()
+	if err != nil {
+		return nil, err
+	}
+
+	var parentModel *effectiveModel
+	if parent != nil {
+		parentRepository, parentPath := openParent(s.locator, s.load, parent, path)
+		if parentRepository != nil && depth < maxModelDepth {
+			if parentModel, err = s.resolveModel(parentRepository, parentPath, depth+1); err != nil {
+				return nil, err
+			}
+		} else {
+			model.unresolved = append(model.unresolved, unresolvedModel{domain.VersionParent, parent.String()})
+		}
+	}
+
+	// Properties of the child override those of its parents
+	if parentModel != nil {
+		for name, value := range parentModel.properties {
+			model.properties[name] = value
+		}
+	}
+	properties, err := repository.GetProperties()
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range properties {
+		model.properties[name] = value
+	}
+
+	managed, err := repository.GetManagedDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []*domain.Dependency
+	for _, dep := range managed {
+		if dep.IsImport() {
+			imports = append(imports, dep)
+			continue
+		}
+		model.direct[dep.Key()] = managedVersion{version: dep.Version, source: domain.VersionManaged}
+	}
+
+	if parentModel != nil {
+		for key, mv := range parentModel.direct {
+			if _, ok := model.direct[key]; ok {
+				continue
+			}
+			if mv.source == domain.VersionManaged {
+				mv.source, mv.origin = domain.VersionParent, parent.String()
+			}
+			model.direct[key] = mv
+		}
+	}
+
+	// BOMs imported by the pom itself take precedence over those imported by its parents
+	for _, bom := range imports {
+		version := interpolate(bom.Version, model.properties)
+		coordinates := bom.GroupID + ":" + bom.ArtifactID + ":" + version
+
+		bomModel, err := s.resolveImport(bom.GroupID, bom.ArtifactID, version, depth)
+		if err != nil {
+			return nil, err
+		}
+		if bomModel == nil {
+			model.unresolved = append(model.unresolved, unresolvedModel{domain.VersionBOM, coordinates})
+			continue
+		}
+
+		for key, mv := range bomModel.direct {
+			if _, ok := model.imported[key]; !ok {
+				model.imported[key] = managedVersion{
+					version: interpolate(mv.version, bomModel.properties),
+					source:  domain.VersionBOM,
+					origin:  coordinates,
+				}
+			}
+		}
+		for key, mv := range bomModel.imported {
+			if _, ok := model.imported[key]; !ok {
+				model.imported[key] = managedVersion{version: mv.version, source: domain.VersionBOM, origin: coordinates}
+			}
+		}
+	}
+
+	if parentModel != nil {
+		for key, mv := range parentModel.imported {
+			if _, ok := model.imported[key]; !ok {
+				model.imported[key] = mv
+			}
+		}
+		model.unresolved = append(model.unresolved, parentModel.unresolved...)
+	}
+
+	return model, nil
+}
+
+// openParent loads the parent pom.xml, first from its relativePath, then from the
+// local repository. Returns nil if it cannot be found.
+func openParent(
+	locator domain.PomLocator,
+	load func(path string) (domain.PomRepository, error),
+	parent *domain.Parent,
+	childPath string,
+) (domain.PomRepository, string) {
+	if parent.RelativePath != "" {
+		path := filepath.Join(filepath.Dir(childPath), filepath.FromSlash(parent.RelativePath))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+
+		// The file at relativePath is only the parent if its coordinates match
+		if repository, err := load(path); err == nil {
+			properties, err := repository.GetProperties()
+			if err == nil && properties["project.groupId"] == parent.GroupID && properties["project.artifactId"] == parent.ArtifactID {
+				return repository, path
+			}
+		}
+	}
+
+	path, err := locator.Locate(parent.GroupID, parent.ArtifactID, parent.Version)
+	if err != nil {
+		return nil, ""
+	}
+
+	repository, err := load(path)
+	if err != nil {
+		return nil, ""
+	}
+
+	return repository, path
+}
+
+// resolveImport builds the effective model of an imported BOM.
+// Returns nil if the BOM is not available locally.
+func (s *ListDependenciesService) resolveImport(groupID, artifactID, version string, depth int) (*effectiveModel, error) {
+	if depth >= maxModelDepth {
+		return nil, nil
+	}
+
+	path, err := s.locator.Locate(groupID, artifactID, version)
+	if err != nil {
+		return nil, nil
+	}
+
+	repository, err := s.load(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	return s.resolveModel(repository, path, depth+1)
+}
+
+// declare resolves the version of a dependency declared in the pom.xml.
+func (m *effectiveModel) declare(dep *domain.Dependency, managed bool) *domain.DeclaredDependency {
+	declared := &domain.DeclaredDependency{Dependency: dep, Managed: managed}
+	if err := dep.Validate(managed); err != nil {
+		declared.Problem = err.Error()
+	}
+
+	switch {
+	case strings.Contains(dep.Version, "${"):
+		var names []string
+		for _, match := range propertyReference.FindAllStringSubmatch(dep.Version, -1) {
+			names = append(names, match[1])
+		}
+		declared.VersionSource = domain.VersionProperty
+		declared.VersionOrigin = strings.Join(names, ", ")
+		declared.ResolvedVersion = interpolate(dep.Version, m.properties)
+	case dep.Version != "":
+		declared.VersionSource = domain.VersionLiteral
+		declared.ResolvedVersion = dep.Version
+	case managed:
+		declared.VersionSource = domain.VersionUnknown
+	default:
+		key := dep.Key()
+		if mv, ok := m.direct[key]; ok {
+			declared.VersionSource = mv.source
+			declared.VersionOrigin = mv.origin
+			declared.ResolvedVersion = interpolate(mv.version, m.properties)
+		} else if mv, ok := m.imported[key]; ok {
+			declared.VersionSource = mv.source
+			declared.VersionOrigin = mv.origin
+			declared.ResolvedVersion = mv.version
+		} else if len(m.unresolved) > 0 {
+			// Most likely managed by a parent or BOM we cannot read
+			declared.VersionSource = m.unresolved[0].source
+			declared.VersionOrigin = m.unresolved[0].coordinates
+		} else {
+			declared.VersionSource = domain.VersionUnknown
+		}
+	}
+
+	return declared
+}
+
+// interpolate replaces ${name} references with property values.
+// Returns "" if a reference cannot be resolved.
+func interpolate(value string, properties map[string]string) string {
+	// References may point to other references; bound the rounds to stop cycles
+	for i := 0; i < 10 && strings.Contains(value, "${"); i++ {
+		resolved := true
+		value = propertyReference.ReplaceAllStringFunc(value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			if v, ok := properties[name]; ok {
+				return v
+			}
+			resolved = false
+			return ref
+		})
+		if !resolved {
+			return ""
+		}
+	}
+
+	if strings.Contains(value, "${") {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// stubLocator is a PomLocator backed by a map of coordinates to paths.
+type stubLocator map[string]string
+
+func (l stubLocator) Locate(groupID, artifactID, version string) (string, error) {
+	coordinates := groupID + ":" + artifactID + ":" + version
+	if path, ok := l[coordinates]; ok {
+		return path, nil
+	}
+	return "", &domain.NotFoundError{Kind: "pom", Name: coordinates}
+}
+
+// repositoryLocator is a PomLocator finding the pom of each artifact as
+// <artifactId>-<version>.pom in a directory.
+type repositoryLocator string
+
+func (l repositoryLocator) Locate(groupID, artifactID, version string) (string, error) {
+	path := filepath.Join(string(l), artifactID+"-"+version+".pom")
+	if _, err := os.Stat(path); err != nil {
+		return "", &domain.NotFoundError{Kind: "pom", Name: groupID + ":" + artifactID + ":" + version}
+	}
+	return path, nil
+}
+
+// writeTestFile writes contents to dir/name and returns its path.
+func writeTestFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+// copyTestPom copies testdata/name to pom.xml in a temporary directory, where
+// the test may change it, and returns its path.
+func copyTestPom(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return writeTestFile(t, t.TempDir(), "pom.xml", string(data))
+}
+
+// pomLoader is a service that locks the project while it changes the pom.xml.
+type pomLoader interface {
+	LoadPom(path string) error
+	Close() error
+}
+
+// loadTestPom copies testdata/name like copyTestPom and loads it into service,
+// which keeps the project lock until the test ends.
+func loadTestPom(t *testing.T, service pomLoader, name string) string {
+	t.Helper()
+
+	pomPath := copyTestPom(t, name)
+	require.NoError(t, service.LoadPom(pomPath))
+	t.Cleanup(func() { assert.NoError(t, service.Close()) })
+	return pomPath
+}
+
+func loadTestRepository(path string) (domain.PomRepository, error) {
+	repository := xml.NewPomRepository()
+	if err := repository.Load(path); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+func TestListDependenciesService_List(t *testing.T) {
+	type row struct {
+		coordinates string
+		managed     bool
+		resolved    string
+		source      domain.VersionSource
+		origin      string
+	}
+
+	// The child finds its parent through relativePath; the BOM the parent
+	// imports is found under repository unless withoutBOM is set
+	tests := []struct {
+		name       string
+		filter     ListFilter
+		withoutBOM bool
+		want       []row
+		invalid    bool
+	}{
+		{
+			name: "all",
+			want: []row{
+				// The child's guava.version overrides the parent's
+				{"com.google.guava:guava", false, "32.1-jre", domain.VersionParent, "org.example:parent:1.0"},
+				{"com.fasterxml.jackson.core:jackson-core", false, "2.17.0", domain.VersionBOM, "org.example:bom:2.0"},
+				{"org.slf4j:slf4j-api", false, "2.0.13", domain.VersionManaged, ""},
+				{"org.example:lib", false, "1.0", domain.VersionProperty, "project.version"},
+				{"junit:junit", false, "4.13.2", domain.VersionLiteral, ""},
+				{"org.slf4j:slf4j-api", true, "2.0.13", domain.VersionLiteral, ""},
+			},
+		},
+		{
+			name:       "unresolved BOM",
+			filter:     ListFilter{Direct: true, GroupPattern: "com.fasterxml.*"},
+			withoutBOM: true,
+			want: []row{
+				{"com.fasterxml.jackson.core:jackson-core", false, "", domain.VersionBOM, "org.example:bom:2.0"},
+			},
+		},
+		{
+			name:   "scope",
+			filter: ListFilter{Scope: "test"},
+			want:   []row{{"junit:junit", false, "4.13.2", domain.VersionLiteral, ""}},
+		},
+		{
+			name:   "managed",
+			filter: ListFilter{Managed: true},
+			want:   []row{{"org.slf4j:slf4j-api", true, "2.0.13", domain.VersionLiteral, ""}},
+		},
+		{
+			name:    "invalid group pattern",
+			filter:  ListFilter{GroupPattern: "org.[example"},
+			invalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locator := stubLocator{"org.example:bom:2.0": filepath.Join("testdata", "list", "repository", "bom-2.0.pom")}
+			if tt.withoutBOM {
+				locator = stubLocator{}
+			}
+			service := NewListDependenciesService(xml.NewPomRepository(), locator, loadTestRepository)
+			require.NoError(t, service.LoadPom(filepath.Join("testdata", "list", "child", "pom.xml")))
+
+			declared, err := service.List(tt.filter)
+			if tt.invalid {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []row
+			for _, d := range declared {
+				got = append(got, row{d.Dependency.Coordinates(), d.Managed, d.ResolvedVersion, d.VersionSource, d.VersionOrigin})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListDependenciesService_ListReportsInvalidScopes(t *testing.T) {
+	service := NewListDependenciesService(xml.NewPomRepository(), stubLocator{}, loadTestRepository)
+	require.NoError(t, service.LoadPom(filepath.Join("testdata", "invalid-scopes.xml")))
+
+	declared, err := service.List(ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, declared, 3)
+
+	assert.Equal(t, "${project.basedir}/lib/ojdbc11.jar", declared[0].Dependency.SystemPath)
+	assert.Empty(t, declared[0].Problem)
+	assert.Contains(t, declared[1].Problem, "system scope requires a systemPath")
+	assert.Contains(t, declared[2].Problem, "import scope requires type pom")
+}
+
+func TestInterpolate(t *testing.T) {
+	properties := map[string]string{"a": "1", "b": "${a}.2", "loop": "${loop}"}
+
+	assert.Equal(t, "1.2.3", interpolate("${b}.3", properties))
+	assert.Equal(t, "literal", interpolate("literal", properties))
+	assert.Equal(t, "", interpolate("${missing}", properties))
+	assert.Equal(t, "", interpolate("${loop}", properties))
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// lockAndLoad acquires the project lock and loads the pom.xml while holding it.
+// The lock is released again if loading fails.
+func lockAndLoad(locker domain.Locker, repository domain.PomRepository, path string) (domain.Lock, error) {
+	lock, err := locker.Lock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.Load(path); err != nil {
+		lock.Unlock() //nolint:errcheck // the load error is the one worth reporting
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// unlock releases a lock taken by lockAndLoad, if any.
+func unlock(lock domain.Lock) error {
+	if lock == nil {
+		return nil
+	}
+
+	if err := lock.Unlock(); err != nil {
+		return fmt.Errorf("failed to release project lock: %w", err)
+	}
+
+	return nil
+}
+
+// projectLock loads the pom.xml of a service that changes it while holding the
+// project lock. Services embed it for LoadPom and Close.
+type projectLock struct {
+	pomRepository domain.PomRepository
+	locker        domain.Locker
+
+	// pomPath is the pom.xml loaded last
+	pomPath string
+
+	// lock is held from LoadPom until Close
+	lock domain.Lock
+}
+
+// newProjectLock creates a projectLock loading the pom.xml into pomRepository.
+func newProjectLock(pomRepository domain.PomRepository, locker domain.Locker) projectLock {
+	return projectLock{
+		pomRepository: pomRepository,
+		locker:        locker,
+	}
+}
+
+// LoadPom locks the project and loads the pom.xml from the specified path.
+// The lock is held until Close; call it once the change is complete.
+func (p *projectLock) LoadPom(path string) error {
+	lock, err := lockAndLoad(p.locker, p.pomRepository, path)
+	if err != nil {
+		return err
+	}
+
+	p.pomPath = path
+	p.lock = lock
+	return nil
+}
+
+// Close releases the project lock taken by LoadPom.
+func (p *projectLock) Close() error {
+	lock := p.lock
+	p.lock = nil
+	return unlock(lock)
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// DependencyMatch is the set of dependencies a command argument refers to.
+type DependencyMatch struct {
+	// Target is the argument as given by the user
+	Target string
+
+	// Dependencies lists the matching dependencies in pom.xml order
+	Dependencies []*domain.Dependency
+
+	// NeedsSelection indicates that a target without wildcards matched several
+	// dependencies, e.g. an artifactId present in several groups or a
+	// groupId:artifactId declared with different classifiers, so the user must choose one
+	NeedsSelection bool
+}
+
+// matchDependencies finds the dependencies selected by target, which is an artifactId,
+// a groupId:artifactId[:type[:classifier]][:version], or a glob pattern such as "org.junit.*:*".
+// A pattern selects every dependency it matches; a target without wildcards that
+// matches several dependencies is reported as needing a selection.
+// Dependencies are looked up in the <dependencies> of the given profile, or of the
+// project when profile is empty.
+func matchDependencies(repository domain.PomRepository, profile, target string) (*DependencyMatch, error) {
+	selector, err := domain.ParseDependencySelector(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []*domain.Dependency
+	if profile != "" {
+		dependencies, err = repository.GetProfileDependencies(profile)
+	} else {
+		dependencies, err = repository.GetDependencies()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	match := &DependencyMatch{Target: target}
+	for _, dep := range dependencies {
+		if selector.Matches(dep) {
+			match.Dependencies = append(match.Dependencies, dep)
+		}
+	}
+
+	if len(match.Dependencies) == 0 {
+		return nil, &domain.NotFoundError{Kind: "dependency", Name: target}
+	}
+
+	match.NeedsSelection = !selector.IsPattern() && len(uniqueDependencies(match.Dependencies)) > 1
+
+	return match, nil
+}
+
+// uniqueDependencies drops repeated dependencies, e.g. when two arguments match the same one.
+func uniqueDependencies(dependencies []*domain.Dependency) []*domain.Dependency {
+	seen := make(map[string]bool, len(dependencies))
+	unique := make([]*domain.Dependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		if !seen[dep.Key()] {
+			seen[dep.Key()] = true
+			unique = append(unique, dep)
+		}
+	}
+	return unique
+}
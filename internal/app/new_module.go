@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+// NewModuleService adds modules to an aggregator project.
+type NewModuleService struct {
+	projectLock
+
+	initializer *fs.ProjectInitializer
+}
+
+// NewNewModuleService creates a new NewModuleService.
+func NewNewModuleService(pomRepository domain.PomRepository, locker domain.Locker) *NewModuleService {
+	return &NewModuleService{
+		projectLock: newProjectLock(pomRepository, locker),
+		initializer: fs.NewProjectInitializer(),
+	}
+}
+
+// PlannedModule is a module registered in the aggregator by Plan, ready to be created.
+type PlannedModule struct {
+	Spec *domain.ModuleSpec
+
+	// Dir is the module directory
+	Dir string
+
+	// Files are the files of the module, relative to Dir
+	Files []fs.ProjectFile
+
+	// ConvertedFrom is the packaging the aggregator had before it was changed to pom,
+	// or empty if it already had pom packaging
+	ConvertedFrom string
+
+	// OrphanedSources is set when the aggregator was converted while it has a src
+	// directory, which Maven no longer builds with pom packaging
+	OrphanedSources bool
+}
+
+// Plan prepares a module of the aggregator loaded by LoadPom from pomPath:
+// the module's parent is set to the aggregator, with a relativePath leading back to it,
+// and the module is added to the aggregator's <modules>. An aggregator without pom
+// packaging is refused unless convert is set, in which case its packaging is changed.
+// The aggregator is only changed in memory and no file is written; see Create.
+func (s *NewModuleService) Plan(pomPath string, spec *domain.ModuleSpec, convert bool) (*PlannedModule, error) {
+	properties, err := s.pomRepository.GetProperties()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	parent := &domain.Parent{
+		GroupID:    properties["project.groupId"],
+		ArtifactID: properties["project.artifactId"],
+		Version:    properties["project.version"],
+	}
+	if parent.GroupID == "" || parent.ArtifactID == "" || parent.Version == "" {
+		return nil, &domain.ValidationError{
+			Field:   "parent",
+			Message: fmt.Sprintf("%s does not declare groupId, artifactId and version, which modules inherit", pomPath),
+		}
+	}
+
+	aggregatorDir := filepath.Dir(pomPath)
+	planned := &PlannedModule{Spec: spec, Dir: filepath.Join(aggregatorDir, filepath.FromSlash(spec.Path))}
+
+	rel, err := filepath.Rel(planned.Dir, aggregatorDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate aggregator: %w", err)
+	}
+	parent.RelativePath = filepath.ToSlash(filepath.Join(rel, filepath.Base(pomPath)))
+	spec.Parent = parent
+
+	packaging, err := s.pomRepository.GetPackaging()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	if packaging != domain.AggregatorPackaging {
+		if !convert {
+			return nil, &domain.ValidationError{
+				Field: "packaging",
+				Message: fmt.Sprintf("%s has %s packaging, but an aggregator needs pom packaging; rerun with --convert to change it",
+					pomPath, packaging),
+			}
+		}
+		if err := s.pomRepository.SetPackaging(domain.AggregatorPackaging); err != nil {
+			return nil, fmt.Errorf("failed to set packaging: %w", err)
+		}
+		planned.ConvertedFrom = packaging
+		if _, err := os.Stat(filepath.Join(aggregatorDir, "src")); err == nil {
+			planned.OrphanedSources = true
+		}
+	}
+
+	modules, err := s.pomRepository.GetModules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+	for _, module := range modules {
+		if filepath.Clean(module) == filepath.Clean(spec.Path) {
+			return nil, &domain.ConflictError{Path: pomPath, Reason: fmt.Sprintf("%s is already a module", spec.Path)}
+		}
+	}
+	if err := s.pomRepository.AddModule(spec.Path); err != nil {
+		return nil, fmt.Errorf("failed to add module: %w", err)
+	}
+
+	planned.Files, err = s.initializer.PreviewModule(planned.Dir, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return planned, nil
+}
+
+// Create writes the files of a planned module and saves the aggregator pom.xml.
+func (s *NewModuleService) Create(planned *PlannedModule) error {
+	if _, err := s.initializer.InitModule(planned.Dir, planned.Spec); err != nil {
+		return err
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+
+	return nil
+}
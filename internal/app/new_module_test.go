@@ -0,0 +1,114 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestNewModuleService_Plan(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		path          string
+		convert       bool
+		convertedFrom string
+		relativePath  string
+		modules       []string
+		invalid       bool
+		conflict      bool
+	}{
+		{
+			name:         "aggregator",
+			file:         "aggregator.xml",
+			path:         "api",
+			relativePath: "../pom.xml",
+			modules:      []string{"services/orders", "api"},
+		},
+		{
+			name:     "existing module",
+			file:     "aggregator.xml",
+			path:     "services/orders",
+			conflict: true,
+		},
+		{
+			name:    "no coordinates to inherit",
+			file:    "no-coordinates.xml",
+			path:    "api",
+			invalid: true,
+		},
+		// A jar project is only turned into an aggregator on request
+		{
+			name:    "jar project",
+			file:    "jar.xml",
+			path:    "services/orders",
+			invalid: true,
+		},
+		{
+			name:          "jar project converted",
+			file:          "jar.xml",
+			path:          "services/orders",
+			convert:       true,
+			convertedFrom: "jar",
+			relativePath:  "../../pom.xml",
+			modules:       []string{"services/orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := xml.NewPomRepository()
+			service := NewNewModuleService(repository, fs.NewFileLocker())
+			pomPath := loadTestPom(t, service, tt.file)
+
+			spec, err := domain.NewModuleSpec(tt.path, "", "")
+			require.NoError(t, err)
+
+			planned, err := service.Plan(pomPath, spec, tt.convert)
+			if tt.invalid {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			if tt.conflict {
+				var conflictErr *domain.ConflictError
+				assert.ErrorAs(t, err, &conflictErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.convertedFrom, planned.ConvertedFrom)
+			assert.Equal(t, &domain.Parent{
+				GroupID:      "com.acme",
+				ArtifactID:   "shop",
+				Version:      "1.2.0-SNAPSHOT",
+				RelativePath: tt.relativePath,
+			}, spec.Parent)
+			modules, err := repository.GetModules()
+			require.NoError(t, err)
+			assert.Equal(t, tt.modules, modules)
+
+			// Nothing is written until Create
+			_, err = os.Stat(planned.Dir)
+			assert.True(t, os.IsNotExist(err))
+
+			require.NoError(t, service.Create(planned))
+			content, err := os.ReadFile(filepath.Join(planned.Dir, "pom.xml"))
+			require.NoError(t, err)
+			if tt.relativePath == "../pom.xml" {
+				// Maven's default is left out
+				assert.NotContains(t, string(content), "<relativePath>")
+			} else {
+				assert.Contains(t, string(content), "<relativePath>"+tt.relativePath+"</relativePath>")
+			}
+			assert.DirExists(t, filepath.Join(planned.Dir, "src", "main", "java"))
+		})
+	}
+}
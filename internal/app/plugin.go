@@ -0,0 +1,249 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Status values of a PluginUpgrade
+const (
+	PluginUpgraded = "upgraded"
+	PluginUpToDate = "up-to-date"
+	PluginSkipped  = "skipped"
+)
+
+// PluginService manages the build plugins of a project.
+type PluginService struct {
+	resolver domain.Resolver
+	projectLock
+}
+
+// NewPluginService creates a new PluginService.
+func NewPluginService(resolver domain.Resolver, pomRepository domain.PomRepository, locker domain.Locker) *PluginService {
+	return &PluginService{
+		resolver:    resolver,
+		projectLock: newProjectLock(pomRepository, locker),
+	}
+}
+
+// PluginUpgrade is the outcome of upgrading one plugin.
+type PluginUpgrade struct {
+	Plugin *domain.Plugin
+
+	// From and To are the declared and the latest stable version
+	From string
+	To   string
+
+	// Status is PluginUpgraded, PluginUpToDate or PluginSkipped
+	Status string
+
+	// Reason explains why the plugin was skipped
+	Reason string
+}
+
+// Search searches for plugins matching the query. A bare artifactId is first looked
+// up under domain.DefaultPluginGroupID, like Maven does for plugins without a
+// groupId, then searched for. Coordinates with a version are used as given.
+func (s *PluginService) Search(query string) (*SearchResult, error) {
+	if strings.Contains(query, ":") {
+		dep, err := domain.ParseCoordinates(query)
+		if err != nil {
+			return nil, err
+		}
+		if dep.Type != "" {
+			return nil, &domain.ValidationError{
+				Field:   "plugin",
+				Message: fmt.Sprintf("invalid plugin %q (expected groupId:artifactId[:version])", query),
+			}
+		}
+		if dep.Version != "" {
+			return &SearchResult{
+				Results: []*domain.ArtifactSearchResult{domain.NewArtifactSearchResult(dep.GroupID, dep.ArtifactID, dep.Version, 100.0)},
+			}, nil
+		}
+
+		result, err := s.resolver.ResolveExact(dep.GroupID, dep.ArtifactID)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchResult{Results: []*domain.ArtifactSearchResult{result}}, nil
+	}
+
+	result, err := s.resolver.ResolveExact(domain.DefaultPluginGroupID, query)
+	if err == nil {
+		return &SearchResult{Results: []*domain.ArtifactSearchResult{result}}, nil
+	}
+	var notFoundErr *domain.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+
+	results, err := s.resolver.Resolve(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, &domain.NotFoundError{Kind: "plugin", Name: query}
+	}
+
+	return &SearchResult{
+		Results:        results,
+		NeedsSelection: len(results) > 1,
+	}, nil
+}
+
+// Add adds the plugin to the pom.xml, or updates the version of an existing
+// declaration in the same section, and saves it.
+func (s *PluginService) Add(plugin *domain.Plugin) error {
+	if err := s.pomRepository.AddPlugin(plugin); err != nil {
+		return fmt.Errorf("failed to add plugin: %w", err)
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the plugins declared in the pom.xml, see domain.PomRepository.GetPlugins.
+func (s *PluginService) List() ([]*domain.Plugin, error) {
+	return s.pomRepository.GetPlugins()
+}
+
+// Match finds the plugins of a section selected by target: an artifactId, a
+// groupId:artifactId, or a glob pattern such as "org.codehaus.mojo:*".
+// A target without wildcards that matches several plugins is an *domain.AmbiguousError.
+func (s *PluginService) Match(target string, managed bool) ([]*domain.Plugin, error) {
+	selector, err := domain.ParseDependencySelector(target)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins, err := s.pomRepository.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*domain.Plugin
+	for _, plugin := range plugins {
+		if plugin.Managed == managed && selector.MatchesCoordinates(plugin.GroupID, plugin.ArtifactID) {
+			matched = append(matched, plugin)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, &domain.NotFoundError{Kind: "plugin", Name: target}
+	}
+	if !selector.IsPattern() && len(matched) > 1 {
+		candidates := make([]string, len(matched))
+		for i, plugin := range matched {
+			candidates[i] = plugin.Coordinates()
+		}
+		return nil, &domain.AmbiguousError{Query: target, Candidates: candidates}
+	}
+
+	return matched, nil
+}
+
+// Remove removes the plugins from the pom.xml and saves it once.
+func (s *PluginService) Remove(plugins []*domain.Plugin) error {
+	for _, plugin := range plugins {
+		if err := s.pomRepository.RemovePlugin(plugin); err != nil {
+			return fmt.Errorf("failed to remove plugin: %w", err)
+		}
+	}
+
+	if err := s.pomRepository.Save(); err != nil {
+		return fmt.Errorf("failed to save pom.xml: %w", err)
+	}
+
+	return nil
+}
+
+// Upgrade sets the plugins matching any of the targets, or all plugins if there are
+// none, to their latest stable version and saves the pom.xml once.
+// Plugins without a literal version are skipped, as are plugins already at or past
+// the latest stable version, e.g. on a newer pre-release.
+func (s *PluginService) Upgrade(targets []string) ([]PluginUpgrade, error) {
+	selectors := make([]*domain.DependencySelector, len(targets))
+	for i, target := range targets {
+		selector, err := domain.ParseDependencySelector(target)
+		if err != nil {
+			return nil, err
+		}
+		selectors[i] = selector
+	}
+
+	plugins, err := s.pomRepository.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []PluginUpgrade
+	changed := false
+	for _, plugin := range plugins {
+		if len(selectors) > 0 && !matchesAnyPlugin(selectors, plugin) {
+			continue
+		}
+
+		upgrade := PluginUpgrade{Plugin: plugin, From: plugin.Version, Status: PluginSkipped}
+		switch {
+		case plugin.Version == "":
+			upgrade.Reason = "no version declared"
+		case strings.Contains(plugin.Version, "${"):
+			upgrade.Reason = "version set by a property"
+		default:
+			latest, err := s.resolver.ResolveExact(plugin.GroupID, plugin.ArtifactID)
+			if err != nil {
+				return nil, err
+			}
+
+			upgrade.To = latest.LatestVersion
+			if domain.CompareVersions(latest.LatestVersion, plugin.Version) <= 0 {
+				upgrade.Status = PluginUpToDate
+				break
+			}
+
+			updated := *plugin
+			updated.Version = latest.LatestVersion
+			if err := s.pomRepository.AddPlugin(&updated); err != nil {
+				return nil, fmt.Errorf("failed to upgrade plugin: %w", err)
+			}
+			upgrade.Status = PluginUpgraded
+			changed = true
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+
+	if len(upgrades) == 0 && len(targets) > 0 {
+		return nil, &domain.NotFoundError{Kind: "plugin", Name: strings.Join(targets, ", ")}
+	}
+
+	if changed {
+		if err := s.pomRepository.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+		}
+	}
+
+	return upgrades, nil
+}
+
+// matchesAnyPlugin reports whether any selector matches the plugin.
+func matchesAnyPlugin(selectors []*domain.DependencySelector, plugin *domain.Plugin) bool {
+	for _, selector := range selectors {
+		if selector.MatchesCoordinates(plugin.GroupID, plugin.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadPom loads the pom.xml from the specified path without locking the project,
+// for read-only operations such as List.
+func (s *PluginService) ReadPom(path string) error {
+	return s.pomRepository.Load(path)
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// stubResolver returns the latest version of known artifacts, keyed by groupId:artifactId.
+type stubResolver map[string]string
+
+func (r stubResolver) Resolve(query string) ([]*domain.ArtifactSearchResult, error) {
+	var results []*domain.ArtifactSearchResult
+	for coordinates, version := range r {
+		g, a, _ := strings.Cut(coordinates, ":")
+		if a == query {
+			results = append(results, domain.NewArtifactSearchResult(g, a, version, 1.0))
+		}
+	}
+	return results, nil
+}
+
+func (r stubResolver) ResolveExact(groupID, artifactID string) (*domain.ArtifactSearchResult, error) {
+	version, ok := r[groupID+":"+artifactID]
+	if !ok {
+		return nil, &domain.NotFoundError{Kind: "artifact", Name: groupID + ":" + artifactID}
+	}
+	return domain.NewArtifactSearchResult(groupID, artifactID, version, 100.0), nil
+}
+
+func TestPluginService_Search(t *testing.T) {
+	resolver := stubResolver{
+		"org.apache.maven.plugins:maven-surefire-plugin": "3.2.5",
+		"org.codehaus.mojo:versions-maven-plugin":        "2.16.2",
+	}
+	service := NewPluginService(resolver, xml.NewPomRepository(), nil)
+
+	tests := []struct {
+		query    string
+		want     string
+		notFound bool
+	}{
+		{query: "maven-surefire-plugin", want: "org.apache.maven.plugins:maven-surefire-plugin:3.2.5"},
+		{query: "versions-maven-plugin", want: "org.codehaus.mojo:versions-maven-plugin:2.16.2"},
+		{query: "org.codehaus.mojo:versions-maven-plugin", want: "org.codehaus.mojo:versions-maven-plugin:2.16.2"},
+		{query: "org.codehaus.mojo:versions-maven-plugin:2.15.0", want: "org.codehaus.mojo:versions-maven-plugin:2.15.0"},
+		{query: "exec-maven-plugin", notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result, err := service.Search(tt.query)
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Results, 1)
+			assert.Equal(t, tt.want, result.Results[0].String())
+		})
+	}
+}
+
+func TestPluginService_Match(t *testing.T) {
+	tests := []struct {
+		target   string
+		managed  bool
+		want     []string
+		notFound bool
+	}{
+		// The groupId defaults to that of the Maven plugins
+		{target: "maven-compiler-plugin", want: []string{"org.apache.maven.plugins:maven-compiler-plugin"}},
+		{target: "org.apache.maven.plugins:*", want: []string{"org.apache.maven.plugins:maven-compiler-plugin", "org.apache.maven.plugins:maven-jar-plugin"}},
+		// Managed plugins are only matched in the managed section
+		{target: "maven-surefire-plugin", notFound: true},
+		{target: "maven-surefire-plugin", managed: true, want: []string{"org.apache.maven.plugins:maven-surefire-plugin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/managed=%t", tt.target, tt.managed), func(t *testing.T) {
+			service := NewPluginService(stubResolver{}, xml.NewPomRepository(), fs.NewFileLocker())
+			loadTestPom(t, service, "plugins.xml")
+
+			plugins, err := service.Match(tt.target, tt.managed)
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, plugin := range plugins {
+				got = append(got, plugin.GroupID+":"+plugin.ArtifactID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPluginService_Upgrade(t *testing.T) {
+	resolver := stubResolver{
+		"org.apache.maven.plugins:maven-compiler-plugin": "3.13.0",
+		"org.apache.maven.plugins:maven-surefire-plugin": "3.2.5",
+	}
+
+	type row struct {
+		artifactID string
+		status     string
+		from       string
+		to         string
+		reason     string
+	}
+	tests := []struct {
+		name     string
+		targets  []string
+		want     []row
+		versions []string
+		notFound bool
+	}{
+		{
+			name: "all",
+			want: []row{
+				{"maven-compiler-plugin", PluginUpgraded, "3.11.0", "3.13.0", ""},
+				{"maven-jar-plugin", PluginSkipped, "${jar.version}", "", "version set by a property"},
+				{"spring-boot-maven-plugin", PluginSkipped, "", "", "no version declared"},
+				// A newer pre-release is not downgraded to the latest stable version
+				{"maven-surefire-plugin", PluginUpToDate, "4.0.0-M1", "3.2.5", ""},
+			},
+			versions: []string{"3.13.0", "${jar.version}", "", "4.0.0-M1"},
+		},
+		{
+			name:     "target",
+			targets:  []string{"maven-compiler-plugin"},
+			want:     []row{{"maven-compiler-plugin", PluginUpgraded, "3.11.0", "3.13.0", ""}},
+			versions: []string{"3.13.0", "${jar.version}", "", "4.0.0-M1"},
+		},
+		{
+			name:     "unknown target",
+			targets:  []string{"exec-maven-plugin"},
+			notFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := xml.NewPomRepository()
+			service := NewPluginService(resolver, repository, fs.NewFileLocker())
+			loadTestPom(t, service, "plugins.xml")
+
+			upgrades, err := service.Upgrade(tt.targets)
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []row
+			for _, upgrade := range upgrades {
+				got = append(got, row{upgrade.Plugin.ArtifactID, upgrade.Status, upgrade.From, upgrade.To, upgrade.Reason})
+			}
+			assert.Equal(t, tt.want, got)
+
+			plugins, err := repository.GetPlugins()
+			require.NoError(t, err)
+			var versions []string
+			for _, plugin := range plugins {
+				versions = append(versions, plugin.Version)
+			}
+			assert.Equal(t, tt.versions, versions)
+		})
+	}
+}
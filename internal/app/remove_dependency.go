@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
@@ -8,30 +9,81 @@ import (
 
 // RemoveDependencyService handles removing dependencies from a project.
 type RemoveDependencyService struct {
-	pomRepository domain.PomRepository
+	projectLock
 }
 
 // NewRemoveDependencyService creates a new RemoveDependencyService.
-func NewRemoveDependencyService(pomRepository domain.PomRepository) *RemoveDependencyService {
+func NewRemoveDependencyService(pomRepository domain.PomRepository, locker domain.Locker) *RemoveDependencyService {
 	return &RemoveDependencyService{
-		pomRepository: pomRepository,
+		projectLock: newProjectLock(pomRepository, locker),
 	}
 }
 
-// Remove removes a dependency from the pom.xml by artifactId.
-func (s *RemoveDependencyService) Remove(artifactID string) error {
-	if err := s.pomRepository.RemoveDependency(artifactID); err != nil {
-		return fmt.Errorf("failed to remove dependency: %w", err)
+// Match finds the dependencies selected by target among those of the given profile,
+// or of the project itself when profile is empty; see DependencyMatch.
+func (s *RemoveDependencyService) Match(target, profile string) (*DependencyMatch, error) {
+	return matchDependencies(s.pomRepository, profile, target)
+}
+
+// Recheck verifies that the dependencies selected from a match made after ReadPom
+// are still those its target selects in the pom.xml reloaded by LoadPom, since the
+// file may have changed while the user was choosing. Returns the selected
+// dependencies as declared in the reloaded pom.xml, or a *ConflictError if the
+// selection no longer holds.
+func (s *RemoveDependencyService) Recheck(match *DependencyMatch, profile string, selected []*domain.Dependency) ([]*domain.Dependency, error) {
+	changed := &domain.ConflictError{
+		Path:   s.pomPath,
+		Reason: fmt.Sprintf("pom.xml changed while selecting the dependencies matching %s; rerun the command", match.Target),
+	}
+
+	current, err := matchDependencies(s.pomRepository, profile, match.Target)
+	if err != nil {
+		var notFoundErr *domain.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, changed
+		}
+		return nil, err
+	}
+
+	declared := make(map[string]*domain.Dependency, len(current.Dependencies))
+	for _, dep := range current.Dependencies {
+		declared[dep.Key()] = dep
+	}
+	if !match.NeedsSelection && len(uniqueDependencies(current.Dependencies)) != len(uniqueDependencies(match.Dependencies)) {
+		return nil, changed
+	}
+
+	rechecked := make([]*domain.Dependency, len(selected))
+	for i, dep := range selected {
+		if rechecked[i] = declared[dep.Key()]; rechecked[i] == nil {
+			return nil, changed
+		}
+	}
+	return rechecked, nil
+}
+
+// Remove removes the given dependencies from the pom.xml and saves it once.
+// Dependencies listed more than once, e.g. matched by two arguments, are removed
+// once; the returned slice holds each removed dependency in order.
+func (s *RemoveDependencyService) Remove(dependencies []*domain.Dependency) ([]*domain.Dependency, error) {
+	removed := uniqueDependencies(dependencies)
+
+	for _, dep := range removed {
+		if err := s.pomRepository.RemoveDependency(dep); err != nil {
+			return nil, fmt.Errorf("failed to remove dependency: %w", err)
+		}
 	}
 
 	if err := s.pomRepository.Save(); err != nil {
-		return fmt.Errorf("failed to save pom.xml: %w", err)
+		return nil, fmt.Errorf("failed to save pom.xml: %w", err)
 	}
 
-	return nil
+	return removed, nil
 }
 
-// LoadPom loads the pom.xml from the specified path.
-func (s *RemoveDependencyService) LoadPom(path string) error {
+// ReadPom loads the pom.xml from the specified path without locking the project,
+// e.g. to match dependencies before the user picks one; see Recheck.
+func (s *RemoveDependencyService) ReadPom(path string) error {
+	s.pomPath = path
 	return s.pomRepository.Load(path)
 }
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// dependencyIDs returns the ID of each dependency in order.
+func dependencyIDs(dependencies []*domain.Dependency) []string {
+	var ids []string
+	for _, dep := range dependencies {
+		ids = append(ids, dep.ID())
+	}
+	return ids
+}
+
+func TestRemoveDependencyService_Match(t *testing.T) {
+	tests := []struct {
+		target         string
+		profile        string
+		want           []string
+		needsSelection bool
+		notFound       bool
+	}{
+		{target: "jackson-core", want: []string{"com.fasterxml.jackson.core:jackson-core", "org.example.shaded:jackson-core"}, needsSelection: true},
+		{target: "org.example.shaded:jackson-core", want: []string{"org.example.shaded:jackson-core"}},
+		{target: "org.junit.*:*", want: []string{"org.junit.jupiter:junit-jupiter", "org.junit.platform:junit-platform-launcher"}},
+		{target: "*:jackson-core", want: []string{"com.fasterxml.jackson.core:jackson-core", "org.example.shaded:jackson-core"}},
+		{target: "junit-jupiter", want: []string{"org.junit.jupiter:junit-jupiter"}},
+		{
+			target: "io.netty:netty-transport-native-epoll",
+			want: []string{
+				"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+				"io.netty:netty-transport-native-epoll:jar:linux-aarch_64:4.1.100.Final",
+			},
+			needsSelection: true,
+		},
+		{
+			target: "io.netty:netty-transport-native-epoll::linux-aarch_64:",
+			want:   []string{"io.netty:netty-transport-native-epoll:jar:linux-aarch_64:4.1.100.Final"},
+		},
+		{target: "org.projectlombok:lombok", notFound: true},
+		// Only the profile's declarations are candidates
+		{target: "jackson-core", profile: "legacy", want: []string{"com.fasterxml.jackson.core:jackson-core"}},
+		{target: "jackson-core", profile: "release", notFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+"/"+tt.profile, func(t *testing.T) {
+			service := NewRemoveDependencyService(xml.NewPomRepository(), fs.NewFileLocker())
+			loadTestPom(t, service, "remove.xml")
+
+			match, err := service.Match(tt.target, tt.profile)
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, dependencyIDs(match.Dependencies))
+			assert.Equal(t, tt.needsSelection, match.NeedsSelection)
+		})
+	}
+}
+
+func TestRemoveDependencyService_Remove(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		profile string
+		removed int
+
+		// want and wantProfile are the dependencies left in the project
+		// and in its legacy profile
+		want        []string
+		wantProfile []string
+	}{
+		{
+			name: "other groups kept",
+			// The dependency matched twice is removed once
+			targets: []string{"org.example.shaded:jackson-core", "org.junit.*:*", "org.example.shaded:jackson-core"},
+			removed: 3,
+			want: []string{
+				"com.fasterxml.jackson.core:jackson-core",
+				"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+				"io.netty:netty-transport-native-epoll:jar:linux-aarch_64:4.1.100.Final",
+			},
+			wantProfile: []string{"com.fasterxml.jackson.core:jackson-core"},
+		},
+		{
+			name:    "other classifiers kept",
+			targets: []string{"io.netty:netty-transport-native-epoll:jar:linux-x86_64:*"},
+			removed: 1,
+			want: []string{
+				"com.fasterxml.jackson.core:jackson-core",
+				"org.example.shaded:jackson-core",
+				"org.junit.jupiter:junit-jupiter",
+				"org.junit.platform:junit-platform-launcher",
+				"io.netty:netty-transport-native-epoll:jar:linux-aarch_64:4.1.100.Final",
+			},
+			wantProfile: []string{"com.fasterxml.jackson.core:jackson-core"},
+		},
+		{
+			name:    "profile",
+			targets: []string{"jackson-core"},
+			profile: "legacy",
+			removed: 1,
+			want: []string{
+				"com.fasterxml.jackson.core:jackson-core",
+				"org.example.shaded:jackson-core",
+				"org.junit.jupiter:junit-jupiter",
+				"org.junit.platform:junit-platform-launcher",
+				"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+				"io.netty:netty-transport-native-epoll:jar:linux-aarch_64:4.1.100.Final",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRemoveDependencyService(xml.NewPomRepository(), fs.NewFileLocker())
+			pomPath := loadTestPom(t, service, "remove.xml")
+
+			var selected []*domain.Dependency
+			for _, target := range tt.targets {
+				match, err := service.Match(target, tt.profile)
+				require.NoError(t, err)
+				selected = append(selected, match.Dependencies...)
+			}
+
+			removed, err := service.Remove(selected)
+			require.NoError(t, err)
+			assert.Len(t, removed, tt.removed)
+
+			repository, err := loadTestRepository(pomPath)
+			require.NoError(t, err)
+			dependencies, err := repository.GetDependencies()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, dependencyIDs(dependencies))
+			dependencies, err = repository.GetProfileDependencies("legacy")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProfile, dependencyIDs(dependencies))
+		})
+	}
+}
+
+func TestRemoveDependencyService_Recheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		pick     int
+		edit     func(string) string
+		conflict bool
+	}{
+		{
+			name:   "unchanged",
+			target: "jackson-core",
+			pick:   1,
+			edit:   func(pom string) string { return pom },
+		},
+		{
+			name:   "unrelated change",
+			target: "jackson-core",
+			pick:   1,
+			edit: func(pom string) string {
+				return strings.Replace(pom, "<version>2.17.0</version>", "<version>2.17.1</version>", 1)
+			},
+		},
+		{
+			name:   "picked dependency removed",
+			target: "jackson-core",
+			pick:   1,
+			edit: func(pom string) string {
+				return strings.Replace(pom, "org.example.shaded", "org.example.relocated", 1)
+			},
+			conflict: true,
+		},
+		{
+			name:   "pattern matches more",
+			target: "org.junit.*:*",
+			pick:   -1,
+			edit: func(pom string) string {
+				return strings.Replace(pom, "io.netty", "org.junit.vintage", 1)
+			},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pomPath := copyTestPom(t, "remove.xml")
+			pom, err := os.ReadFile(pomPath)
+			require.NoError(t, err)
+
+			service := NewRemoveDependencyService(xml.NewPomRepository(), fs.NewFileLocker())
+			require.NoError(t, service.ReadPom(pomPath))
+			match, err := service.Match(tt.target, "")
+			require.NoError(t, err)
+			selected := match.Dependencies
+			if tt.pick >= 0 {
+				selected = selected[tt.pick : tt.pick+1]
+			}
+
+			// Another process edits the pom.xml while the user picks
+			require.NoError(t, os.WriteFile(pomPath, []byte(tt.edit(string(pom))), 0644))
+
+			require.NoError(t, service.LoadPom(pomPath))
+			defer func() { assert.NoError(t, service.Close()) }()
+
+			rechecked, err := service.Recheck(match, "", selected)
+			if tt.conflict {
+				var conflictErr *domain.ConflictError
+				assert.ErrorAs(t, err, &conflictErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rechecked, len(selected))
+			assert.Equal(t, selected[0].Key(), rechecked[0].Key())
+		})
+	}
+}
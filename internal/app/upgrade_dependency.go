@@ -0,0 +1,136 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Status values of a DependencyUpgrade
+const (
+	DependencyUpgraded = "upgraded"
+	DependencyUpToDate = "up-to-date"
+	DependencySkipped  = "skipped"
+)
+
+// DependencyUpgrade is the outcome of upgrading one dependency.
+type DependencyUpgrade struct {
+	Dependency *domain.Dependency
+
+	// From and To are the declared and the latest stable version
+	From string
+	To   string
+
+	// Status is DependencyUpgraded, DependencyUpToDate or DependencySkipped
+	Status string
+
+	// Reason explains why the dependency was skipped
+	Reason string
+}
+
+// UpgradeDependencyService handles upgrading the dependencies of a project to
+// their latest stable version.
+type UpgradeDependencyService struct {
+	resolver domain.Resolver
+	projectLock
+}
+
+// NewUpgradeDependencyService creates a new UpgradeDependencyService.
+func NewUpgradeDependencyService(resolver domain.Resolver, pomRepository domain.PomRepository, locker domain.Locker) *UpgradeDependencyService {
+	return &UpgradeDependencyService{
+		resolver:    resolver,
+		projectLock: newProjectLock(pomRepository, locker),
+	}
+}
+
+// Upgrade sets the dependencies matching any of the targets, or all dependencies if
+// there are none, to their latest stable version and saves the pom.xml once.
+// Dependencies are those of the given profile, or of the project itself when profile
+// is empty. Dependencies without a literal version are skipped, as are dependencies
+// already at or past the latest stable version, e.g. on a newer pre-release.
+func (s *UpgradeDependencyService) Upgrade(targets []string, profile string) ([]DependencyUpgrade, error) {
+	selectors := make([]*domain.DependencySelector, len(targets))
+	for i, target := range targets {
+		selector, err := domain.ParseDependencySelector(target)
+		if err != nil {
+			return nil, err
+		}
+		selectors[i] = selector
+	}
+
+	var (
+		dependencies []*domain.Dependency
+		err          error
+	)
+	if profile != "" {
+		dependencies, err = s.pomRepository.GetProfileDependencies(profile)
+	} else {
+		dependencies, err = s.pomRepository.GetDependencies()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []DependencyUpgrade
+	changed := false
+	for _, dep := range dependencies {
+		if len(selectors) > 0 && !matchesAnyDependency(selectors, dep) {
+			continue
+		}
+
+		upgrade := DependencyUpgrade{Dependency: dep, From: dep.Version, Status: DependencySkipped}
+		switch {
+		case dep.Version == "":
+			upgrade.Reason = "version managed by a parent or BOM"
+		case strings.Contains(dep.Version, "${"):
+			upgrade.Reason = "version set by a property"
+		case strings.HasPrefix(dep.Version, "[") || strings.HasPrefix(dep.Version, "("):
+			upgrade.Reason = "version range"
+		case dep.Scope == "system":
+			upgrade.Reason = "system scope"
+		default:
+			latest, err := s.resolver.ResolveExact(dep.GroupID, dep.ArtifactID)
+			if err != nil {
+				return nil, err
+			}
+
+			upgrade.To = latest.LatestVersion
+			if domain.CompareVersions(latest.LatestVersion, dep.Version) <= 0 {
+				upgrade.Status = DependencyUpToDate
+				break
+			}
+
+			updated := *dep
+			updated.Version = latest.LatestVersion
+			if err := s.pomRepository.AddDependency(&updated); err != nil {
+				return nil, fmt.Errorf("failed to upgrade dependency: %w", err)
+			}
+			upgrade.Status = DependencyUpgraded
+			changed = true
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+
+	if len(upgrades) == 0 && len(targets) > 0 {
+		return nil, &domain.NotFoundError{Kind: "dependency", Name: strings.Join(targets, ", ")}
+	}
+
+	if changed {
+		if err := s.pomRepository.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save pom.xml: %w", err)
+		}
+	}
+
+	return upgrades, nil
+}
+
+// matchesAnyDependency reports whether any selector matches the dependency.
+func matchesAnyDependency(selectors []*domain.DependencySelector, dep *domain.Dependency) bool {
+	for _, selector := range selectors {
+		if selector.Matches(dep) {
+			return true
+		}
+	}
+	return false
+}
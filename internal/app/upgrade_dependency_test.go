@@ -0,0 +1,115 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+func TestUpgradeDependencyService_Upgrade(t *testing.T) {
+	resolver := stubResolver{
+		"com.google.guava:guava":            "33.2.1-jre",
+		"org.testcontainers:testcontainers": "1.20.1",
+		"org.junit.jupiter:junit-jupiter":   "5.10.2",
+	}
+
+	type row struct {
+		coordinates string
+		profile     string
+		status      string
+		from        string
+		to          string
+		reason      string
+	}
+	tests := []struct {
+		name    string
+		targets []string
+		profile string
+		want    []row
+
+		// versions and profileVersions are the versions declared in the
+		// project and in its it profile afterwards
+		versions        []string
+		profileVersions []string
+		notFound        bool
+	}{
+		{
+			name: "project",
+			want: []row{
+				{"com.google.guava:guava", "", DependencyUpgraded, "32.0.0-jre", "33.2.1-jre", ""},
+				{"com.fasterxml.jackson.core:jackson-databind", "", DependencySkipped, "${jackson.version}", "", "version set by a property"},
+				{"org.slf4j:slf4j-api", "", DependencySkipped, "", "", "version managed by a parent or BOM"},
+			},
+			versions: []string{"33.2.1-jre", "${jackson.version}", ""},
+			// Profile dependencies are left alone
+			profileVersions: []string{"1.19.0", "6.0.0-M1"},
+		},
+		{
+			name:    "profile",
+			profile: "it",
+			want: []row{
+				{"org.testcontainers:testcontainers", "it", DependencyUpgraded, "1.19.0", "1.20.1", ""},
+				// A newer pre-release is not downgraded to the latest stable version
+				{"org.junit.jupiter:junit-jupiter", "it", DependencyUpToDate, "6.0.0-M1", "5.10.2", ""},
+			},
+			versions:        []string{"32.0.0-jre", "${jackson.version}", ""},
+			profileVersions: []string{"1.20.1", "6.0.0-M1"},
+		},
+		{
+			name:     "target outside the profile",
+			targets:  []string{"guava"},
+			profile:  "it",
+			notFound: true,
+		},
+		{
+			name:     "unknown profile",
+			profile:  "release",
+			notFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := xml.NewPomRepository()
+			service := NewUpgradeDependencyService(resolver, repository, fs.NewFileLocker())
+			loadTestPom(t, service, "upgrade.xml")
+
+			upgrades, err := service.Upgrade(tt.targets, tt.profile)
+			if tt.notFound {
+				var notFoundErr *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []row
+			for _, upgrade := range upgrades {
+				got = append(got, row{upgrade.Dependency.Coordinates(), upgrade.Dependency.Profile, upgrade.Status, upgrade.From, upgrade.To, upgrade.Reason})
+			}
+			assert.Equal(t, tt.want, got)
+
+			dependencies, err := repository.GetDependencies()
+			require.NoError(t, err)
+			assert.Equal(t, tt.versions, dependencyVersions(dependencies))
+			dependencies, err = repository.GetProfileDependencies("it")
+			require.NoError(t, err)
+			assert.Equal(t, tt.profileVersions, dependencyVersions(dependencies))
+			// The scope is kept
+			assert.Equal(t, "test", dependencies[0].Scope)
+		})
+	}
+}
+
+// dependencyVersions returns the declared version of each dependency in order.
+func dependencyVersions(dependencies []*domain.Dependency) []string {
+	var versions []string
+	for _, dep := range dependencies {
+		versions = append(versions, dep.Version)
+	}
+	return versions
+}
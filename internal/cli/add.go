@@ -1,10 +1,10 @@
 package cli
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -18,6 +18,24 @@ import (
 var (
 	// scope flag for add command
 	scope string
+
+	// artifact flags for add command
+	artifactType string
+	classifier   string
+	optional     bool
+	systemPath   string
+
+	// profile flags for add command
+	addProfile    string
+	addActivation []string
+
+	// selection flags for add command
+	pickFirst      bool
+	pickIndex      int
+	nonInteractive bool
+
+	// allowLicense is the justification for adding an artifact the license policy refuses
+	allowLicense string
 )
 
 // addCmd represents the add command
@@ -25,113 +43,369 @@ var addCmd = &cobra.Command{
 	Use:   "add <query>",
 	Short: "Add a dependency to the project",
 	Long: `Add a dependency to the project's pom.xml.
-Query can be a simple search term (e.g., "lombok") or an exact coordinate (e.g., "org.projectlombok:lombok").`,
-	Args: cobra.ExactArgs(1),
+Query can be a simple search term (e.g., "lombok") or an exact coordinate (e.g., "org.projectlombok:lombok").
+
+Coordinates may also carry a version, type and classifier, in Maven's
+groupId:artifactId[:type[:classifier]]:version syntax. A given version is used
+as is; otherwise the latest stable version is looked up on Maven Central.
+
+When the query matches several artifacts, mvnx asks which one to add. Without a
+terminal (CI, pipes) the choice must be made up front with --yes or --pick;
+otherwise the command fails and lists the candidates.
+
+--profile adds the dependency to the <dependencies> of a <profile>. A missing
+profile is created, activated by the --activation conditions:
+  default                 active unless another profile is selected
+  property:name[=value]   the property is set (to value); !name when it is not set
+  jdk:range               the JDK version matches, e.g. 17 or [17,)
+  os:family               the OS family, e.g. windows or unix
+  file:path               the file exists; !path when it is missing
+--activation is ignored for profiles that already exist.
+
+If the project, or a directory above it, has a mvnx-license-policy.yaml, the
+licenses of the artifact are checked against it (see mvnx licenses). An
+artifact whose license is denied, or unknown when the policy says
+"unknown: deny", is refused; other unknown licenses are warned about.
+--allow-license adds the artifact anyway and records the justification as an
+exception in the policy, which accepts the artifact from then on.`,
+	Example: `  mvnx add lombok --scope provided
+  mvnx add org.postgresql:postgresql:42.7.3
+  mvnx add io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.110.Final
+  mvnx add org.example:my-lib --type test-jar --scope test
+  mvnx add org.testcontainers:postgresql --scope test --profile it --activation property:env=it
+  mvnx add org.example:vendor-sdk:2.1 --allow-license "Commercial license, contract #1234"`,
+	Args: exactArgs(1),
 	RunE: runAdd,
 }
 
 func init() {
-	addCmd.Flags().StringVar(&scope, "scope", "compile", "dependency scope (compile, test, provided, runtime)")
+	addCmd.Flags().StringVar(&scope, "scope", "compile", "dependency scope (compile, test, provided, runtime, system)")
+	addCmd.Flags().StringVar(&systemPath, "system-path", "", "path of the jar of a system scope dependency, e.g. ${project.basedir}/lib/foo.jar")
+	addCmd.Flags().StringVar(&artifactType, "type", "", "dependency type, e.g. test-jar or pom (default jar)")
+	addCmd.Flags().StringVar(&classifier, "classifier", "", "dependency classifier, e.g. linux-x86_64")
+	addCmd.Flags().BoolVar(&optional, "optional", false, "mark the dependency as optional")
+	addCmd.Flags().StringVar(&addProfile, "profile", "", "add the dependency to this profile, creating it if needed")
+	addCmd.Flags().StringArrayVar(&addActivation, "activation", nil, "activation of a created profile, e.g. property:env=it (repeatable)")
+	addCmd.Flags().BoolVarP(&pickFirst, "yes", "y", false, "pick the top search result without prompting")
+	addCmd.Flags().BoolVar(&pickFirst, "first", false, "alias for --yes")
+	addCmd.Flags().IntVar(&pickIndex, "pick", 0, "pick the n-th search result (1-based) without prompting")
+	addCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt; fail if the query is ambiguous")
+	addCmd.Flags().StringVar(&allowLicense, "allow-license", "", "add the artifact despite the license policy, recording this justification")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
+	if pickFirst && pickIndex != 0 {
+		return usageErrorf("--yes/--first and --pick cannot be used together")
+	}
+	if pickIndex < 0 {
+		return usageErrorf("invalid --pick value: %d (must be 1 or greater)", pickIndex)
+	}
+
 	// Validate scope
-	validScopes := map[string]bool{
-		"compile":  true,
-		"test":     true,
-		"provided": true,
-		"runtime":  true,
+	if err := domain.ValidateScope(scope); err != nil {
+		return err
+	}
+	if scope == "import" {
+		return &domain.ValidationError{
+			Field:   "scope",
+			Message: "import scope is only allowed for BOMs in <dependencyManagement>, which mvnx add does not edit",
+		}
 	}
-	if !validScopes[scope] {
-		return fmt.Errorf("invalid scope: %s (valid: compile, test, provided, runtime)", scope)
+	if (scope == "system") != (systemPath != "") {
+		return usageErrorf("--system-path is required with --scope system, and only allowed with it")
+	}
+	if addProfile == "" && len(addActivation) > 0 {
+		return usageErrorf("--activation requires --profile")
+	}
+	profile, err := parseProfile(addProfile, addActivation)
+	if err != nil {
+		return err
 	}
 
 	// Find project
-	cwd, err := os.Getwd()
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create services
+	resolver := maven.NewResolver()
+	pomRepo := newPomRepository()
+	licenses := app.NewLicenseService(xml.NewPomRepository(), newPomLocator(false), loadPom)
+	service := app.NewAddDependencyService(resolver, pomRepo, newLocker(), licenses, newLicensePolicyStore(project.Path))
+
+	// Search for artifacts
+	logf("Searching for: %s\n", query)
+
+	searchResult, err := service.Search(query)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
-	projectFinder := app.NewProjectFinder()
-	project, err := projectFinder.FindProject(cwd)
+	// Select artifact
+	selectedArtifact, err := selectArtifact(query, searchResult)
 	if err != nil {
-		return fmt.Errorf("no Maven project found. Run 'mvnx init' to create one.")
+		return err
 	}
 
-	if verbose {
-		fmt.Printf("Found pom.xml at: %s\n", project.PomLocation)
+	dep, err := selectedArtifact.ToDependency(scope)
+	if err != nil {
+		return err
+	}
+	if err := applyArtifactFlags(dep, searchResult.Requested); err != nil {
+		return err
+	}
+	dep.Profile = addProfile
+	if err := dep.Validate(false); err != nil {
+		return err
 	}
 
-	// Create services
-	resolver := maven.NewResolver()
-	pomRepo := xml.NewPomRepository()
-	service := app.NewAddDependencyService(resolver, pomRepo)
+	// Check the license before taking the lock too, since it may download poms
+	check, err := service.CheckLicense(dep, allowLicense)
+	if err != nil {
+		var policyErr *domain.LicensePolicyError
+		if errors.As(err, &policyErr) {
+			return fmt.Errorf("%w\nrerun with --allow-license <justification> to add it anyway", err)
+		}
+		return err
+	}
 
-	// Load pom.xml
+	// Lock the project and load pom.xml only now, so the lock is not held
+	// during the search, while the user picks an artifact or while the
+	// license is looked up
 	if err := service.LoadPom(project.PomLocation); err != nil {
 		return fmt.Errorf("failed to load pom.xml: %w", err)
 	}
+	defer closeService(service)
 
-	// Search for artifacts
-	if verbose {
-		fmt.Printf("Searching for: %s\n", query)
-	}
-
-	searchResult, err := service.Search(query)
+	change, err := beginPomChange(project.PomLocation)
 	if err != nil {
 		return err
 	}
 
-	// Select artifact
-	var selectedArtifact *domain.ArtifactSearchResult
+	result := &addResult{
+		Pom:        project.PomLocation,
+		Dependency: newDependencyView(dep),
+		DryRun:     dryRun,
+	}
 
-	if searchResult.NeedsSelection {
-		// Interactive selection
-		selectedArtifact, err = selectArtifact(searchResult.Results)
+	// Create the profile first, so the dependency has a place to go
+	description := "add " + dep.String()
+	if profile != nil {
+		created, err := service.EnsureProfile(profile)
 		if err != nil {
 			return err
 		}
-	} else {
-		// Use the only result
-		selectedArtifact = searchResult.Results[0]
+		if created {
+			result.CreatedProfile = newProfileView(profile)
+		} else if len(profile.Activation) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: profile %s already exists; --activation is ignored\n", profile.ID)
+		}
+		description += " to profile " + profile.ID
 	}
 
 	// Add the dependency
-	if err := service.Add(selectedArtifact, scope); err != nil {
+	if err := service.Add(dep, check); err != nil {
+		return err
+	}
+	if check != nil {
+		result.License = newLicenseCheckView(check)
+		if check.Recorded {
+			description += " (license exception: " + allowLicense + ")"
+		} else if check.Exception == nil && check.Verdict != domain.LicenseAllowed {
+			fmt.Fprintf(os.Stderr, "Warning: the license of %s is %s\n", dep.String(), licenseCheckSummary(result.License))
+		}
+	}
+	change.Record(description)
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("✓ Added %s\n", selectedArtifact.String())
+	return printer.Print(result)
+}
 
-	return nil
+// parseProfile returns the profile selected by --profile with the --activation
+// conditions, or nil without --profile.
+func parseProfile(id string, activation []string) (*domain.Profile, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	conditions := make([]domain.ProfileActivation, len(activation))
+	for i, s := range activation {
+		condition, err := domain.ParseProfileActivation(s)
+		if err != nil {
+			return nil, err
+		}
+		conditions[i] = condition
+	}
+	return domain.NewProfile(id, conditions)
 }
 
-// selectArtifact presents an interactive selection menu and returns the chosen artifact
-func selectArtifact(results []*domain.ArtifactSearchResult) (*domain.ArtifactSearchResult, error) {
-	fmt.Println("\nMultiple artifacts found:")
-	fmt.Println()
+// addResult is the output of the add command.
+type addResult struct {
+	Pom        string         `json:"pom" yaml:"pom"`
+	Dependency dependencyView `json:"dependency" yaml:"dependency"`
+	DryRun     bool           `json:"dryRun" yaml:"dryRun"`
+	Diff       string         `json:"diff,omitempty" yaml:"diff,omitempty"`
 
-	for i, result := range results {
-		fmt.Printf("[%d] %s:%s\n", i+1, result.GroupID, result.ArtifactID)
-		fmt.Printf("    Version: %s\n", result.LatestVersion)
-		fmt.Println()
+	// CreatedProfile is set when the profile of the dependency did not exist
+	CreatedProfile *profileView `json:"createdProfile,omitempty" yaml:"createdProfile,omitempty"`
+
+	// License is set when the project has a license policy
+	License *licenseCheckView `json:"license,omitempty" yaml:"license,omitempty"`
+}
+
+// licenseCheckView is the stable machine-readable representation of a license check.
+type licenseCheckView struct {
+	Verdict  string        `json:"verdict" yaml:"verdict"`
+	Licenses []licenseView `json:"licenses" yaml:"licenses"`
+	Problem  string        `json:"problem,omitempty" yaml:"problem,omitempty"`
+
+	// Justification is that of the policy exception accepting the artifact, if any
+	Justification string `json:"justification,omitempty" yaml:"justification,omitempty"`
+
+	// Recorded tells whether the exception was added to the policy by this command
+	Recorded bool `json:"recorded" yaml:"recorded"`
+}
+
+// newLicenseCheckView converts a license check to its view.
+func newLicenseCheckView(c *app.LicenseCheck) *licenseCheckView {
+	view := &licenseCheckView{
+		Verdict:  string(c.Verdict),
+		Licenses: []licenseView{},
+		Problem:  c.Problem,
+		Recorded: c.Recorded,
+	}
+	for _, l := range c.Licenses {
+		view.Licenses = append(view.Licenses, licenseView{SPDX: l.SPDX, Name: l.Name, URL: l.URL})
 	}
+	if c.Exception != nil {
+		view.Justification = c.Exception.Justification
+	}
+	return view
+}
 
-	fmt.Printf("Select artifact (1-%d): ", len(results))
+// licenseCheckSummary describes the verdict of a license check with the
+// licenses, e.g. "denied (GPL-3.0-only)" or "unknown (none declared)".
+func licenseCheckSummary(c *licenseCheckView) string {
+	labels := make([]string, len(c.Licenses))
+	for i, l := range c.Licenses {
+		labels[i] = l.label()
+	}
+	switch {
+	case c.Problem != "":
+		return c.Verdict + " (" + c.Problem + ")"
+	case len(labels) == 0:
+		return c.Verdict + " (none declared)"
+	default:
+		return c.Verdict + " (" + strings.Join(labels, ", ") + ")"
+	}
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return nil, fmt.Errorf("failed to read input: %w", err)
+// WriteText prints a confirmation line followed by the diff, if any.
+func (r *addResult) WriteText(w io.Writer) error {
+	d := r.Dependency
+	verb := "✓ Added"
+	if r.DryRun {
+		verb = "Would add"
+	}
+	if _, err := fmt.Fprintf(w, "%s %s%s\n", verb, d.id, profileSuffix(" to", d.Profile)); err != nil {
+		return err
+	}
+	if p := r.CreatedProfile; p != nil {
+		created := "  Created profile "
+		if r.DryRun {
+			created = "  Would create profile "
+		}
+		activation := "activated with -P " + p.ID
+		if len(p.Activation) > 0 {
+			activation = "activation: " + strings.Join(p.Activation, ", ")
+		}
+		if _, err := fmt.Fprintf(w, "%s%s (%s)\n", created, p.ID, activation); err != nil {
+			return err
+		}
+	}
+	if c := r.License; c != nil && c.Justification != "" {
+		accepted := "  License " + licenseCheckSummary(c) + " accepted by exception: "
+		if c.Recorded && r.DryRun {
+			accepted = "  Would record license exception for " + licenseCheckSummary(c) + ": "
+		} else if c.Recorded {
+			accepted = "  Recorded license exception for " + licenseCheckSummary(c) + ": "
+		}
+		if _, err := fmt.Fprintln(w, accepted+c.Justification); err != nil {
+			return err
+		}
 	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns the added dependency as a single row.
+func (r *addResult) TSV() ([]string, [][]string) {
+	d := r.Dependency
+	return []string{"groupId", "artifactId", "version", "scope", "pom"},
+		[][]string{{d.GroupID, d.ArtifactID, d.Version, d.Scope, r.Pom}}
+}
 
-	input = strings.TrimSpace(input)
-	selection, err := strconv.Atoi(input)
-	if err != nil || selection < 1 || selection > len(results) {
-		return nil, fmt.Errorf("invalid selection: %s", input)
+// applyArtifactFlags sets the type, classifier, optional flag and systemPath of dep from the
+// coordinates given in the query and the --type, --classifier, --optional and --system-path flags.
+// A flag that contradicts the coordinates is a usage error.
+func applyArtifactFlags(dep *domain.Dependency, requested *domain.Dependency) error {
+	if requested != nil {
+		dep.Type, dep.Classifier = requested.Type, requested.Classifier
+	}
+
+	if artifactType != "" {
+		if dep.Type != "" && dep.Type != artifactType {
+			return usageErrorf("--type %s contradicts the type %s in the coordinates", artifactType, dep.Type)
+		}
+		dep.Type = artifactType
+	}
+	if classifier != "" {
+		if dep.Classifier != "" && dep.Classifier != classifier {
+			return usageErrorf("--classifier %s contradicts the classifier %s in the coordinates", classifier, dep.Classifier)
+		}
+		dep.Classifier = classifier
+	}
+	dep.Optional = optional
+	dep.SystemPath = systemPath
+
+	return nil
+}
+
+// selectArtifact picks the artifact to add from the search results.
+// Explicit --yes/--pick choices win; otherwise a single result is used as-is and
+// ambiguous results are resolved interactively, or rejected when no terminal is available.
+func selectArtifact(query string, searchResult *app.SearchResult) (*domain.ArtifactSearchResult, error) {
+	results := searchResult.Results
+
+	switch {
+	case pickIndex > 0:
+		if pickIndex > len(results) {
+			return nil, usageErrorf("invalid --pick value: %d (only %d results found)", pickIndex, len(results))
+		}
+		return results[pickIndex-1], nil
+	case pickFirst || !searchResult.NeedsSelection:
+		return results[0], nil
+	case nonInteractive || !isTerminal():
+		return nil, ambiguousQueryError(query, results)
+	default:
+		return pickArtifact(results)
+	}
+}
+
+// ambiguousQueryError builds an error listing the candidates for an ambiguous query,
+// so scripts can rerun the command with --pick or an exact coordinate.
+func ambiguousQueryError(query string, results []*domain.ArtifactSearchResult) error {
+	candidates := make([]string, len(results))
+	for i, result := range results {
+		candidates[i] = result.String()
 	}
 
-	return results[selection-1], nil
+	return fmt.Errorf("%w\nrerun with --pick <n>, --yes, or an exact groupId:artifactId",
+		&domain.AmbiguousError{Query: query, Candidates: candidates})
 }
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/osv"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var (
+	auditFix    bool
+	auditDirect bool
+	auditFailOn string
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check dependencies for known vulnerabilities",
+	Long: `Check the dependencies of the project against a local copy of the OSV
+advisory database for the Maven ecosystem, without network access. Import the
+database first with 'mvnx audit import', from the zip export published by
+osv.dev (https://osv-vulnerabilities.storage.googleapis.com/Maven/all.zip) or
+any OSV-formatted dump of the GitHub Advisory Database.
+
+Every artifact is checked at the version Maven picks, the nearest to the
+project, comparing versions with Maven's ordering. Transitive dependencies are
+read from the local Maven repository, as mvnx converge does; use --direct to
+check only the dependencies the project declares.
+
+For each vulnerable artifact, the advisories are reported with their severity
+and the first version fixing them, along with the safe version: the lowest
+higher version no known advisory affects. --fix upgrades to it: a declared
+version is changed in place, or through its property, and transitive
+dependencies are pinned in <dependencyManagement>.
+
+The command exits with status 10 when vulnerabilities at or above the --fail-on
+severity remain; advisories without a severity rating always count.`,
+	Example: `  mvnx audit import ~/Downloads/all.zip
+  mvnx audit
+  mvnx audit --fail-on high
+  mvnx audit --fix --dry-run`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runAudit,
+}
+
+// auditImportCmd represents the audit import command
+var auditImportCmd = &cobra.Command{
+	Use:   "import <zip>",
+	Short: "Import an OSV advisory database export",
+	Long: `Import the Maven advisories of an OSV zip export, one JSON entry per file,
+replacing the previous import. Withdrawn advisories and other ecosystems are
+skipped.
+
+The database is stored in the user cache directory, or in the directory named
+by the MVNX_ADVISORY_DB environment variable.`,
+	Example: `  mvnx audit import all.zip`,
+	Args:    exactArgs(1),
+	RunE:    runAuditImport,
+}
+
+func init() {
+	auditCmd.Flags().BoolVar(&auditFix, "fix", false, "upgrade vulnerable dependencies to the nearest safe version")
+	auditCmd.Flags().BoolVar(&auditDirect, "direct", false, "only check the dependencies declared by the project")
+	auditCmd.Flags().StringVar(&auditFailOn, "fail-on", string(domain.AdvisoryLow), "exit with status 10 on vulnerabilities of this severity or worse (low, moderate, high, critical, never)")
+
+	auditCmd.AddCommand(auditImportCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	var failOn domain.AdvisorySeverity
+	if auditFailOn != failOnNever {
+		failOn = domain.ParseAdvisorySeverity(auditFailOn)
+		if failOn == domain.AdvisoryUnknown {
+			return usageErrorf("invalid --fail-on: %s (valid: low, moderate, high, critical, never)", auditFailOn)
+		}
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	database := osv.NewDatabase()
+	result := &auditResult{
+		Pom:      project.PomLocation,
+		Findings: []findingView{},
+		Fixed:    []findingView{},
+		DryRun:   auditFix && dryRun,
+	}
+
+	var report *app.AuditReport
+	var remaining []*app.AuditFinding
+	if auditFix {
+		pomRepo := newPomRepository()
+		service := app.NewAuditService(pomRepo, newLocker(), maven.NewLocalRepository(), loadPom, database)
+
+		// Lock the project and load pom.xml
+		if err := service.LoadPom(project.PomLocation); err != nil {
+			return fmt.Errorf("failed to load pom.xml: %w", err)
+		}
+		defer closeService(service)
+
+		change, err := beginPomChange(project.PomLocation)
+		if err != nil {
+			return err
+		}
+
+		if report, err = service.Audit(!auditDirect); err != nil {
+			return err
+		}
+		fixes, err := service.Fix(report.Findings)
+		if err != nil {
+			return err
+		}
+
+		for _, fix := range fixes {
+			if fix.Fixed {
+				result.Fixed = append(result.Fixed, newFindingView(fix.Finding))
+			} else {
+				remaining = append(remaining, fix.Finding)
+			}
+		}
+		if len(result.Fixed) > 0 {
+			change.Record("audit --fix")
+		}
+
+		result.Diff, err = change.Diff(pomRepo)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Reading needs no project lock
+		service := app.NewAuditService(xml.NewPomRepository(), nil, maven.NewLocalRepository(), loadPom, database)
+		if err := service.ReadPom(project.PomLocation); err != nil {
+			return fmt.Errorf("failed to load pom.xml: %w", err)
+		}
+
+		if report, err = service.Audit(!auditDirect); err != nil {
+			return err
+		}
+		remaining = report.Findings
+	}
+
+	result.Artifacts = report.Artifacts
+	failing := 0
+	for _, finding := range remaining {
+		result.Findings = append(result.Findings, newFindingView(finding))
+		if failOn == "" {
+			continue
+		}
+		for _, advisory := range finding.Advisories {
+			if advisory.Severity == domain.AdvisoryUnknown || advisory.Severity.AtLeast(failOn) {
+				failing++
+				break
+			}
+		}
+	}
+
+	if len(report.Unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: could not check %s whose version is unknown: %s\n",
+			plural(len(report.Unknown), "dependency"), strings.Join(report.Unknown, ", "))
+	}
+	warnUnresolved(report.Unresolved)
+
+	if err := printer.Print(result); err != nil {
+		return err
+	}
+	if failing > 0 {
+		return &checkFailedError{message: fmt.Sprintf("%d vulnerable artifact(s) at or above %s severity", failing, failOn)}
+	}
+	return nil
+}
+
+func runAuditImport(cmd *cobra.Command, args []string) error {
+	database := osv.NewDatabase()
+	summary, err := database.Import(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(&auditImportResult{
+		Database:   database.Path(),
+		Source:     summary.Source,
+		Advisories: summary.Advisories,
+		Packages:   summary.Packages,
+	})
+}
+
+// advisoryView is the stable machine-readable representation of an advisory.
+type advisoryView struct {
+	ID       string   `json:"id" yaml:"id"`
+	Aliases  []string `json:"aliases" yaml:"aliases"`
+	Severity string   `json:"severity" yaml:"severity"`
+	Summary  string   `json:"summary" yaml:"summary"`
+
+	// FixedIn is the first version fixing the advisory; empty when no fix is known
+	FixedIn string `json:"fixedIn" yaml:"fixedIn"`
+}
+
+// findingView is the stable machine-readable representation of a vulnerable artifact.
+type findingView struct {
+	Dependency string `json:"dependency" yaml:"dependency"`
+	Version    string `json:"version" yaml:"version"`
+
+	// Path leads from a direct dependency to a transitive one; empty for direct dependencies
+	Path        []string       `json:"path" yaml:"path"`
+	Advisories  []advisoryView `json:"advisories" yaml:"advisories"`
+	SafeVersion string         `json:"safeVersion" yaml:"safeVersion"`
+}
+
+// newFindingView converts a finding to its view.
+func newFindingView(f *app.AuditFinding) findingView {
+	view := findingView{
+		Dependency:  f.Dependency.ID(),
+		Version:     f.Dependency.Version,
+		Path:        f.Path,
+		SafeVersion: f.SafeVersion,
+	}
+	if view.Path == nil {
+		view.Path = []string{}
+	}
+	for _, a := range f.Advisories {
+		aliases := a.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		view.Advisories = append(view.Advisories, advisoryView{
+			ID:       a.ID,
+			Aliases:  aliases,
+			Severity: string(a.Severity),
+			Summary:  a.Summary,
+			FixedIn:  a.FirstFixed(f.Dependency.Version),
+		})
+	}
+	return view
+}
+
+// auditResult is the output of the audit command.
+type auditResult struct {
+	Pom       string `json:"pom" yaml:"pom"`
+	Artifacts int    `json:"artifacts" yaml:"artifacts"`
+
+	// Findings are the vulnerable artifacts left in the project
+	Findings []findingView `json:"findings" yaml:"findings"`
+
+	// Fixed are the artifacts upgraded by --fix
+	Fixed  []findingView `json:"fixed" yaml:"fixed"`
+	DryRun bool          `json:"dryRun" yaml:"dryRun"`
+	Diff   string        `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints the upgrades, then each vulnerable artifact with its
+// advisories, a summary and the diff, if any.
+func (r *auditResult) WriteText(w io.Writer) error {
+	verb := "✓ Upgraded"
+	if r.DryRun {
+		verb = "Would upgrade"
+	}
+	for _, f := range r.Fixed {
+		if _, err := fmt.Fprintf(w, "%s %s from %s to %s (%s)\n", verb, f.Dependency, f.Version, f.SafeVersion, advisoryIDs(f)); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range r.Findings {
+		line := f.Dependency + " " + f.Version
+		if len(f.Path) > 0 {
+			line += " (via " + strings.Join(f.Path, " > ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, a := range f.Advisories {
+			id := a.ID
+			if len(a.Aliases) > 0 {
+				id += " (" + strings.Join(a.Aliases, ", ") + ")"
+			}
+			if _, err := fmt.Fprintf(w, "  %-8s  %s  fixed in %s  %s\n", a.Severity, id, orDash(a.FixedIn), a.Summary); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "  safe version: %s\n", orDash(f.SafeVersion)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, r.summary()); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// summary counts the vulnerable artifacts left, e.g. "2 vulnerable artifacts among 57 checked".
+func (r *auditResult) summary() string {
+	if len(r.Findings) == 0 {
+		return fmt.Sprintf("No known vulnerabilities in %s", plural(r.Artifacts, "artifact"))
+	}
+
+	fixable := 0
+	for _, f := range r.Findings {
+		if f.SafeVersion != "" {
+			fixable++
+		}
+	}
+	summary := fmt.Sprintf("%s among %d checked", plural(len(r.Findings), "vulnerable artifact"), r.Artifacts)
+	if fixable > 0 {
+		summary += fmt.Sprintf("; %d can be upgraded with --fix", fixable)
+	}
+	return summary
+}
+
+// advisoryIDs joins the advisory IDs of a finding.
+func advisoryIDs(f findingView) string {
+	ids := make([]string, len(f.Advisories))
+	for i, a := range f.Advisories {
+		ids[i] = a.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+// TSV returns one row per advisory of a vulnerable artifact left.
+func (r *auditResult) TSV() ([]string, [][]string) {
+	var rows [][]string
+	for _, f := range r.Findings {
+		for _, a := range f.Advisories {
+			rows = append(rows, []string{
+				f.Dependency, f.Version, a.ID, a.Severity, a.FixedIn, f.SafeVersion, strings.Join(f.Path, " > "),
+			})
+		}
+	}
+	return []string{"dependency", "version", "advisory", "severity", "fixedIn", "safeVersion", "path"}, rows
+}
+
+// auditImportResult is the output of the audit import command.
+type auditImportResult struct {
+	Database   string `json:"database" yaml:"database"`
+	Source     string `json:"source" yaml:"source"`
+	Advisories int    `json:"advisories" yaml:"advisories"`
+	Packages   int    `json:"packages" yaml:"packages"`
+}
+
+// WriteText prints what was imported.
+func (r *auditImportResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "✓ Imported %s affecting %s into %s\n",
+		plural(r.Advisories, "advisory"), plural(r.Packages, "artifact"), r.Database)
+	return err
+}
+
+// TSV returns the import as a single row.
+func (r *auditImportResult) TSV() ([]string, [][]string) {
+	return []string{"database", "source", "advisories", "packages"},
+		[][]string{{r.Database, r.Source, fmt.Sprint(r.Advisories), fmt.Sprint(r.Packages)}}
+}
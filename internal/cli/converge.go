@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var convergeFix bool
+
+// convergeCmd represents the converge command
+var convergeCmd = &cobra.Command{
+	Use:   "converge",
+	Short: "Check that dependencies converge on one version of each artifact",
+	Long: `Walk the transitive dependency graph and report every artifact requested at
+more than one version, with the paths requesting each version. This is what the
+enforcer's dependencyConvergence rule checks: Maven silently keeps the nearest
+version, which may not be the one a library was built against.
+
+The graph is built from the poms in the local Maven repository, as Maven sees
+it: the direct dependencies of every scope, then the compile and runtime
+dependencies of each artifact, skipping optional ones and honoring exclusions.
+Versions managed in <dependencyManagement> apply to transitive dependencies.
+Artifacts whose pom is not available locally are reported on stderr; run a
+Maven build once to download them.
+
+The command exits with status 10 when versions diverge, for use in CI. With
+--fix, the version Maven picks is pinned in <dependencyManagement> instead; if
+an existing entry takes its version from a property, the property is updated,
+unless other versions use it too.`,
+	Example: `  mvnx converge
+  mvnx converge --fix --dry-run
+  mvnx converge -o json`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runConverge,
+}
+
+func init() {
+	convergeCmd.Flags().BoolVar(&convergeFix, "fix", false, "pin the version Maven picks for each diverging artifact in <dependencyManagement>")
+}
+
+func runConverge(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	if !convergeFix {
+		return runConvergeCheck(project.PomLocation)
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewConvergeService(pomRepo, newLocker(), maven.NewLocalRepository(), loadPom)
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	report, err := service.Check()
+	if err != nil {
+		return err
+	}
+	if err := service.Pin(report.Divergences); err != nil {
+		return err
+	}
+	if len(report.Divergences) > 0 {
+		change.Record("converge")
+	}
+
+	result := newConvergeResult(project.PomLocation, report)
+	result.Fixed, result.DryRun = true, dryRun
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	warnUnresolved(report.Unresolved)
+	return printer.Print(result)
+}
+
+// runConvergeCheck reports the diverging artifacts without changing the pom.xml.
+func runConvergeCheck(pomPath string) error {
+	// Reading needs no project lock
+	service := app.NewConvergeService(xml.NewPomRepository(), nil, maven.NewLocalRepository(), loadPom)
+	if err := service.ReadPom(pomPath); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	report, err := service.Check()
+	if err != nil {
+		return err
+	}
+
+	warnUnresolved(report.Unresolved)
+	if err := printer.Print(newConvergeResult(pomPath, report)); err != nil {
+		return err
+	}
+	if len(report.Divergences) > 0 {
+		return &checkFailedError{message: fmt.Sprintf("%d artifact(s) requested at more than one version", len(report.Divergences))}
+	}
+	return nil
+}
+
+// warnUnresolved warns that the dependencies of some artifacts could not be checked.
+func warnUnresolved(unresolved []string) {
+	if len(unresolved) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: could not check the dependencies of %s whose pom is not in the local repository: %s\n",
+		plural(len(unresolved), "artifact"), strings.Join(unresolved, ", "))
+}
+
+// versionRequestView is a version of a diverging artifact and the paths requesting it.
+type versionRequestView struct {
+	Version string `json:"version" yaml:"version"`
+
+	// Paths lead from a direct dependency to the artifact requesting the version;
+	// an empty path is the project itself
+	Paths [][]string `json:"paths" yaml:"paths"`
+}
+
+// divergenceView is the stable machine-readable representation of a diverging artifact.
+type divergenceView struct {
+	Dependency string               `json:"dependency" yaml:"dependency"`
+	Winner     string               `json:"winner" yaml:"winner"`
+	Versions   []versionRequestView `json:"versions" yaml:"versions"`
+}
+
+// convergeResult is the output of the converge command.
+type convergeResult struct {
+	Pom         string           `json:"pom" yaml:"pom"`
+	Artifacts   int              `json:"artifacts" yaml:"artifacts"`
+	Divergences []divergenceView `json:"divergences" yaml:"divergences"`
+	Unresolved  []string         `json:"unresolved" yaml:"unresolved"`
+	Fixed       bool             `json:"fixed" yaml:"fixed"`
+	DryRun      bool             `json:"dryRun" yaml:"dryRun"`
+	Diff        string           `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// newConvergeResult converts a convergence report to its view.
+func newConvergeResult(pomPath string, report *app.ConvergenceReport) *convergeResult {
+	result := &convergeResult{
+		Pom:         pomPath,
+		Artifacts:   report.Artifacts,
+		Divergences: []divergenceView{},
+		Unresolved:  report.Unresolved,
+	}
+	if result.Unresolved == nil {
+		result.Unresolved = []string{}
+	}
+	for _, d := range report.Divergences {
+		view := divergenceView{Dependency: d.Dependency.ID(), Winner: d.Winner}
+		for _, v := range d.Versions {
+			paths := make([][]string, len(v.Paths))
+			for i, path := range v.Paths {
+				paths[i] = path
+				if path == nil {
+					paths[i] = []string{}
+				}
+			}
+			view.Versions = append(view.Versions, versionRequestView{Version: v.Version, Paths: paths})
+		}
+		result.Divergences = append(result.Divergences, view)
+	}
+	return result
+}
+
+// pathString describes how a version is requested, e.g. "org.example:a:1.0 > org.example:b:2.0".
+func pathString(path []string) string {
+	if len(path) == 0 {
+		return "the project"
+	}
+	return strings.Join(path, " > ")
+}
+
+// WriteText prints each diverging artifact with the paths requesting each of
+// its versions, or what was pinned, followed by the diff, if any.
+func (r *convergeResult) WriteText(w io.Writer) error {
+	if len(r.Divergences) == 0 {
+		_, err := fmt.Fprintf(w, "All %s converge on one version\n", plural(r.Artifacts, "artifact"))
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%d of %s requested at more than one version:\n",
+		len(r.Divergences), plural(r.Artifacts, "artifact")); err != nil {
+		return err
+	}
+	for _, d := range r.Divergences {
+		if _, err := fmt.Fprintf(w, "\n%s (Maven picks %s)\n", d.Dependency, d.Winner); err != nil {
+			return err
+		}
+		for _, v := range d.Versions {
+			for _, path := range v.Paths {
+				if _, err := fmt.Fprintf(w, "  %s  requested by %s\n", v.Version, pathString(path)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if r.Fixed {
+		verb := "✓ Pinned"
+		if r.DryRun {
+			verb = "Would pin"
+		}
+		if _, err := fmt.Fprintf(w, "\n%s %s in <dependencyManagement>\n", verb, plural(len(r.Divergences), "version")); err != nil {
+			return err
+		}
+	}
+
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per path requesting a version of a diverging artifact.
+func (r *convergeResult) TSV() ([]string, [][]string) {
+	var rows [][]string
+	for _, d := range r.Divergences {
+		for _, v := range d.Versions {
+			for _, path := range v.Paths {
+				rows = append(rows, []string{d.Dependency, v.Version, d.Winner, strings.Join(path, " > ")})
+			}
+		}
+	}
+	return []string{"dependency", "version", "winner", "path"}, rows
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var (
+	dedupeCheck           bool
+	dedupeVersionStrategy string
+	dedupeScopeStrategy   string
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Merge duplicate dependency declarations",
+	Long: `Merge dependencies declared more than once in the same section: <dependencies>,
+<dependencyManagement> or the <dependencies> of a profile. Declarations are the
+same when groupId, artifactId, type and classifier match. Maven uses the last
+one and only warns, so a forgotten copy silently changes the build.
+
+The first declaration is kept, with the version and scope chosen by the
+strategies; it is optional only if all declarations are, and keeps the
+exclusions of all of them:
+
+  --version-strategy  highest (default), last (what Maven uses), first
+  --scope-strategy    widest (default), last, first
+
+The widest scope puts the dependency on every classpath any declaration did,
+e.g. runtime and provided become compile. System and import scopes are never
+merged with others.
+
+A <dependencies> entry whose version differs from its <dependencyManagement>
+entry is reported but not merged, since both are legitimate declarations.
+
+With --check, nothing is written and the command exits with status 10 when
+duplicates are found.`,
+	Example: `  mvnx dedupe
+  mvnx dedupe --check
+  mvnx dedupe --version-strategy last --dry-run`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runDedupe,
+}
+
+func init() {
+	dedupeCmd.Flags().BoolVar(&dedupeCheck, "check", false, "only report duplicates, exiting with status 10 if there are any")
+	dedupeCmd.Flags().StringVar(&dedupeVersionStrategy, "version-strategy", string(domain.VersionHighest), "version to keep (highest, last, first)")
+	dedupeCmd.Flags().StringVar(&dedupeScopeStrategy, "scope-strategy", string(domain.ScopeWidest), "scope to keep (widest, last, first)")
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	versions, err := domain.ParseVersionStrategy(dedupeVersionStrategy)
+	if err != nil {
+		return err
+	}
+	scopes, err := domain.ParseScopeStrategy(dedupeScopeStrategy)
+	if err != nil {
+		return err
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	if dedupeCheck {
+		return runDedupeCheck(project.PomLocation)
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewDedupeService(pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	duplicates, err := service.Find()
+	if err != nil {
+		return err
+	}
+	merges, err := service.Merge(duplicates, versions, scopes)
+	if err != nil {
+		return err
+	}
+
+	result := &dedupeResult{Pom: project.PomLocation, Duplicates: []duplicateView{}, DryRun: dryRun}
+	merged := 0
+	for _, m := range merges {
+		view := newDuplicateView(m.Duplicate)
+		if m.Merged != nil {
+			merged++
+			view.Merged = &mergedView{Version: m.Merged.Version, Scope: m.Merged.Scope, Removed: m.Removed}
+		}
+		view.Reason = m.Reason
+		result.Duplicates = append(result.Duplicates, view)
+	}
+	if merged > 0 {
+		change.Record("dedupe")
+	}
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// runDedupeCheck reports the duplicates without merging them.
+func runDedupeCheck(pomPath string) error {
+	// Reading needs no project lock
+	service := app.NewDedupeService(xml.NewPomRepository(), nil)
+	if err := service.ReadPom(pomPath); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	duplicates, err := service.Find()
+	if err != nil {
+		return err
+	}
+
+	result := &dedupeResult{Pom: pomPath, Duplicates: []duplicateView{}, Check: true}
+	for _, d := range duplicates {
+		result.Duplicates = append(result.Duplicates, newDuplicateView(d))
+	}
+
+	if err := printer.Print(result); err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return &checkFailedError{message: fmt.Sprintf("%d duplicate declaration(s) found", len(duplicates))}
+	}
+	return nil
+}
+
+// declarationView is one of the declarations of a duplicate artifact.
+type declarationView struct {
+	Version string `json:"version" yaml:"version"`
+	Scope   string `json:"scope" yaml:"scope"`
+
+	// Line is 0 when unknown
+	Line int `json:"line" yaml:"line"`
+}
+
+// mergedView is the declaration kept by dedupe.
+type mergedView struct {
+	Version string `json:"version" yaml:"version"`
+	Scope   string `json:"scope" yaml:"scope"`
+	Removed int    `json:"removed" yaml:"removed"`
+}
+
+// duplicateView is the stable machine-readable representation of a duplicate artifact.
+type duplicateView struct {
+	Dependency   string            `json:"dependency" yaml:"dependency"`
+	Section      string            `json:"section" yaml:"section"`
+	Declarations []declarationView `json:"declarations" yaml:"declarations"`
+
+	// Versions and Scopes are the distinct values declared; more than one is a conflict
+	Versions []string `json:"versions" yaml:"versions"`
+	Scopes   []string `json:"scopes" yaml:"scopes"`
+	Override bool     `json:"override" yaml:"override"`
+
+	// Merged is nil when nothing was merged, and Reason then explains why
+	Merged *mergedView `json:"merged,omitempty" yaml:"merged,omitempty"`
+	Reason string      `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// newDuplicateView converts a duplicate to its view.
+func newDuplicateView(d *app.Duplicate) duplicateView {
+	view := duplicateView{
+		Dependency: d.Declarations[0].ID(),
+		Section:    d.Section,
+		Versions:   d.Versions(),
+		Scopes:     d.Scopes(),
+		Override:   d.Override,
+	}
+	if view.Versions == nil {
+		view.Versions = []string{}
+	}
+	if view.Scopes == nil {
+		view.Scopes = []string{}
+	}
+	for _, dep := range d.Declarations {
+		view.Declarations = append(view.Declarations, declarationView{
+			Version: dep.Version,
+			Scope:   dep.Scope,
+			Line:    dep.Position.Line,
+		})
+	}
+	return view
+}
+
+// dedupeResult is the output of the dedupe command.
+type dedupeResult struct {
+	Pom        string          `json:"pom" yaml:"pom"`
+	Duplicates []duplicateView `json:"duplicates" yaml:"duplicates"`
+	Check      bool            `json:"check" yaml:"check"`
+	DryRun     bool            `json:"dryRun" yaml:"dryRun"`
+	Diff       string          `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints each duplicate with its declarations, or what was merged,
+// followed by the diff, if any.
+func (r *dedupeResult) WriteText(w io.Writer) error {
+	if len(r.Duplicates) == 0 {
+		_, err := fmt.Fprintln(w, "No duplicate declarations found")
+		return err
+	}
+
+	verb := "✓ Merged"
+	if r.DryRun {
+		verb = "Would merge"
+	}
+	for _, d := range r.Duplicates {
+		var line string
+		switch {
+		case r.Check && d.Override:
+			line = fmt.Sprintf("%s in <dependencies> overrides the version of its <dependencyManagement> entry", d.Dependency)
+		case r.Check:
+			line = fmt.Sprintf("%s is declared %d times in %s", d.Dependency, len(d.Declarations), d.Section)
+			if len(d.Versions) > 1 || len(d.Scopes) > 1 {
+				line += " with conflicting versions or scopes"
+			}
+		case d.Merged != nil:
+			line = fmt.Sprintf("%s %s in %s into %s (%s), removing %d declaration(s)",
+				verb, d.Dependency, d.Section, orDash(d.Merged.Version), d.Merged.Scope, d.Merged.Removed)
+		default:
+			line = fmt.Sprintf("Skipped %s in %s: %s", d.Dependency, d.Section, d.Reason)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		if !r.Check {
+			continue
+		}
+		for _, decl := range d.Declarations {
+			location := "line ?"
+			if decl.Line != 0 {
+				location = "line " + strconv.Itoa(decl.Line)
+			}
+			if _, err := fmt.Fprintf(w, "  %s: %s (%s)\n", location, orDash(decl.Version), decl.Scope); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per duplicate.
+func (r *dedupeResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Duplicates))
+	for i, d := range r.Duplicates {
+		merged := ""
+		if d.Merged != nil {
+			merged = d.Merged.Version
+		}
+		rows[i] = []string{
+			d.Dependency, d.Section, strings.Join(d.Versions, ","), strings.Join(d.Scopes, ","),
+			fmt.Sprint(d.Override), merged, d.Reason,
+		}
+	}
+	return []string{"dependency", "section", "versions", "scopes", "override", "merged", "reason"}, rows
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/diff"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// ANSI escape sequences used to color diffs
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// newPomRepository creates the repository used by mutating commands.
+// With --dry-run, changes are kept in memory and never saved.
+func newPomRepository() domain.PomRepository {
+	var repository domain.PomRepository = xml.NewPomRepository()
+	if dryRun {
+		repository = app.NewDryRunPomRepository(repository)
+	}
+	return repository
+}
+
+// newLocker creates the locker that serializes pom.xml edits across mvnx processes.
+// With --dry-run nothing is locked, since nothing is written.
+func newLocker() domain.Locker {
+	if dryRun {
+		return app.NewDryRunLocker()
+	}
+	return fs.NewFileLocker()
+}
+
+// newLicensePolicyStore finds the license policy of the project in projectPath.
+// With --dry-run exceptions are not recorded.
+func newLicensePolicyStore(projectPath string) domain.LicensePolicyStore {
+	var store domain.LicensePolicyStore = fs.NewLicensePolicyFile(projectPath)
+	if dryRun {
+		store = app.NewDryRunLicensePolicyStore(store)
+	}
+	return store
+}
+
+// closeService releases the project lock held by a mutating service.
+// A failure is only reported as a warning, since the command has already completed.
+func closeService(service io.Closer) {
+	if err := service.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// pomChange remembers a pom.xml as it was before a mutating command,
+// so that --dry-run and --diff can show what the command changed.
+type pomChange struct {
+	path   string
+	before []byte
+}
+
+// beginPomChange snapshots the pom.xml at path.
+// Call it while holding the project lock, so the snapshot matches what the command loaded.
+func beginPomChange(path string) (*pomChange, error) {
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &domain.PomParseError{Path: path, Err: err}
+	}
+
+	return &pomChange{path: path, before: before}, nil
+}
+
+// Diff returns the unified diff between the snapshot and the new contents,
+// or an empty string when neither --dry-run nor --diff was requested.
+// In dry-run mode the new contents come from the repository, otherwise from disk.
+func (c *pomChange) Diff(repository domain.PomRepository) (string, error) {
+	if !dryRun && !showDiff {
+		return "", nil
+	}
+
+	var (
+		after []byte
+		err   error
+	)
+	if dryRun {
+		after, err = repository.Render()
+	} else {
+		after, err = os.ReadFile(c.path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	name := diffName(c.path)
+	return diff.Unified("a/"+name, "b/"+name, string(c.before), string(after)), nil
+}
+
+// Record adds the change to the project's history so it can be undone.
+// Nothing is recorded in dry-run mode. Failing to record is reported as a
+// warning, since the pom.xml has already been written at this point.
+func (c *pomChange) Record(description string) {
+	if dryRun {
+		return
+	}
+
+	after, err := os.ReadFile(c.path)
+	if err == nil {
+		history := newHistoryService(domain.NewProject(filepath.Dir(c.path), c.path))
+		err = history.Record(description, c.before, after)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record history: %v\n", err)
+	}
+}
+
+// diffName returns the path shown in diff headers, relative to the working directory when possible.
+func diffName(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filepath.Base(path)
+	}
+
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// writeDiff prints a unified diff, colored when stdout is a terminal.
+// Setting NO_COLOR disables colors.
+func writeDiff(w io.Writer, unified string) error {
+	if unified == "" {
+		return nil
+	}
+
+	if !useColor() {
+		_, err := io.WriteString(w, unified)
+		return err
+	}
+
+	for _, line := range strings.SplitAfter(unified, "\n") {
+		if line == "" {
+			continue
+		}
+
+		color := ""
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			color = ansiBold
+		case strings.HasPrefix(line, "@@"):
+			color = ansiCyan
+		case strings.HasPrefix(line, "-"):
+			color = ansiRed
+		case strings.HasPrefix(line, "+"):
+			color = ansiGreen
+		}
+
+		if color == "" {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text := strings.TrimSuffix(line, "\n")
+		if _, err := fmt.Fprintf(w, "%s%s%s\n", color, text, ansiReset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// useColor reports whether text output should contain ANSI colors.
+func useColor() bool {
+	return os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// failOnNever disables the check_failed exit code of doctor.
+const failOnNever = "never"
+
+var (
+	doctorFix    bool
+	doctorSkip   []string
+	doctorFailOn string
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the pom.xml for common problems",
+	Long: `Check the pom.xml for common problems and report them with their severity
+and position, in the file:line:column form editors and CI systems understand.
+
+Checks:
+  duplicates          dependencies or plugins declared more than once in the same section (error)
+  plugin-versions     build plugins without a version (warning, fixable)
+  snapshots           SNAPSHOT dependencies or plugins in a release project (error)
+  test-scope          test libraries such as JUnit or Mockito in compile scope (warning, fixable)
+  unstable-versions   alpha, beta, milestone or release candidate versions (warning)
+  source-encoding     project.build.sourceEncoding not set (warning, fixable)
+  unused-properties   properties never referenced in the pom.xml (info, fixable)
+
+Settings inherited from parent poms are taken into account when the parents can
+be read from their relativePath or the local repository (~/.m2/repository).
+
+With --fix, the fixable problems are fixed in the pom.xml. The command exits
+with status 10 when problems at or above the --fail-on severity remain.`,
+	Example: `  mvnx doctor
+  mvnx doctor --fix --dry-run
+  mvnx doctor --skip unused-properties --fail-on warning`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "fix the problems that can be fixed automatically")
+	doctorCmd.Flags().StringArrayVar(&doctorSkip, "skip", nil, "skip a check by name (repeatable)")
+	doctorCmd.Flags().StringVar(&doctorFailOn, "fail-on", string(app.SeverityError), "exit with status 10 on problems of this severity or worse (error, warning, info, never)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	var failOn app.Severity
+	if doctorFailOn != failOnNever {
+		var err error
+		if failOn, err = app.ParseSeverity(doctorFailOn); err != nil {
+			return usageErrorf("invalid --fail-on: %s (valid: error, warning, info, never)", doctorFailOn)
+		}
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	result := &doctorResult{
+		Pom:         project.PomLocation,
+		Diagnostics: []diagnosticView{},
+		Fixed:       []diagnosticView{},
+		FailedFixes: []fixFailureView{},
+		DryRun:      doctorFix && dryRun,
+		file:        diffName(project.PomLocation),
+	}
+
+	var (
+		diagnostics []*app.Diagnostic
+
+		// fixErr reports the fixes that failed, once the others are saved
+		fixErr error
+	)
+	if doctorFix {
+		pomRepo := newPomRepository()
+		service := app.NewDoctorService(pomRepo, newLocker(), maven.NewLocalRepository(), loadPom, app.DefaultChecks(maven.NewResolver()))
+
+		// Lock the project and load pom.xml
+		if err := service.LoadPom(project.PomLocation); err != nil {
+			return fmt.Errorf("failed to load pom.xml: %w", err)
+		}
+		defer closeService(service)
+
+		change, err := beginPomChange(project.PomLocation)
+		if err != nil {
+			return err
+		}
+
+		found, err := service.Run(doctorSkip)
+		if err != nil {
+			return err
+		}
+		fixes, err := service.Fix(found)
+		if err != nil {
+			return err
+		}
+
+		// Problems whose fix failed are left in the pom.xml
+		for _, d := range found {
+			if slices.Contains(fixes.Fixed, d) {
+				result.Fixed = append(result.Fixed, newDiagnosticView(d))
+			} else {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+		for _, failure := range fixes.Failed {
+			result.FailedFixes = append(result.FailedFixes, fixFailureView{
+				diagnosticView: newDiagnosticView(failure.Diagnostic),
+				Error:          failure.Err.Error(),
+			})
+		}
+		if len(fixes.Fixed) > 0 {
+			change.Record("doctor --fix")
+		}
+		if len(fixes.Failed) > 0 {
+			fixErr = fmt.Errorf("%d of %d fixes could not be applied: %w",
+				len(fixes.Failed), len(fixes.Failed)+len(fixes.Fixed), fixes.Failed[0].Err)
+		}
+
+		result.Diff, err = change.Diff(pomRepo)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Reading needs no project lock
+		service := app.NewDoctorService(xml.NewPomRepository(), nil, maven.NewLocalRepository(), loadPom, app.DefaultChecks(maven.NewResolver()))
+		if err := service.ReadPom(project.PomLocation); err != nil {
+			return fmt.Errorf("failed to load pom.xml: %w", err)
+		}
+
+		if diagnostics, err = service.Run(doctorSkip); err != nil {
+			return err
+		}
+	}
+
+	failing := 0
+	for _, d := range diagnostics {
+		result.Diagnostics = append(result.Diagnostics, newDiagnosticView(d))
+		if failOn != "" && d.Severity.AtLeast(failOn) {
+			failing++
+		}
+	}
+
+	if err := printer.Print(result); err != nil {
+		return err
+	}
+	if fixErr != nil {
+		return fixErr
+	}
+	if failing > 0 {
+		return &checkFailedError{message: fmt.Sprintf("%d problem(s) at or above %s severity", failing, failOn)}
+	}
+	return nil
+}
+
+// diagnosticView is the stable machine-readable representation of a problem found by doctor.
+type diagnosticView struct {
+	Check    string `json:"check" yaml:"check"`
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+
+	// Line and Column are 0 for problems of the file as a whole
+	Line   int `json:"line" yaml:"line"`
+	Column int `json:"column" yaml:"column"`
+
+	// Fix describes the automatic fix; empty when the problem must be fixed by hand
+	Fix string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// newDiagnosticView converts a diagnostic to its view.
+func newDiagnosticView(d *app.Diagnostic) diagnosticView {
+	return diagnosticView{
+		Check:    d.Check,
+		Severity: string(d.Severity),
+		Message:  d.Message,
+		Line:     d.Position.Line,
+		Column:   d.Position.Column,
+		Fix:      d.Fix,
+	}
+}
+
+// fixFailureView is a fix that could not be applied, with the reason.
+type fixFailureView struct {
+	diagnosticView `yaml:",inline"`
+	Error          string `json:"error" yaml:"error"`
+}
+
+// doctorResult is the output of the doctor command.
+type doctorResult struct {
+	Pom string `json:"pom" yaml:"pom"`
+
+	// Diagnostics are the problems left in the pom.xml
+	Diagnostics []diagnosticView `json:"diagnostics" yaml:"diagnostics"`
+
+	// Fixed are the problems fixed by --fix
+	Fixed []diagnosticView `json:"fixed" yaml:"fixed"`
+
+	// FailedFixes are the fixes --fix could not apply; their problems are also
+	// in Diagnostics
+	FailedFixes []fixFailureView `json:"failedFixes" yaml:"failedFixes"`
+	DryRun      bool             `json:"dryRun" yaml:"dryRun"`
+	Diff        string           `json:"diff,omitempty" yaml:"diff,omitempty"`
+
+	// file is the pom.xml path shown in text output, relative to the working directory
+	file string
+}
+
+// WriteText prints the fixes, then one compiler-style line per problem and a summary.
+func (r *doctorResult) WriteText(w io.Writer) error {
+	verb := "✓ Fixed"
+	if r.DryRun {
+		verb = "Would fix"
+	}
+	for _, d := range r.Fixed {
+		if _, err := fmt.Fprintf(w, "%s %s: %s (%s)\n", verb, r.location(d), d.Message, d.Fix); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range r.FailedFixes {
+		if _, err := fmt.Fprintf(w, "✗ Could not fix %s: %s (%s): %s\n", r.location(d.diagnosticView), d.Message, d.Fix, d.Error); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range r.Diagnostics {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", r.location(d), d.Severity, d.Message, d.Check); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, r.summary()); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// location formats the position of a problem as file:line:column, or file alone.
+func (r *doctorResult) location(d diagnosticView) string {
+	if d.Line == 0 {
+		return r.file
+	}
+	return fmt.Sprintf("%s:%d:%d", r.file, d.Line, d.Column)
+}
+
+// summary counts the remaining problems by severity, e.g. "3 problems (1 error, 2 warnings)".
+func (r *doctorResult) summary() string {
+	if len(r.Diagnostics) == 0 {
+		return "No problems found"
+	}
+
+	counts := make(map[string]int)
+	fixable := 0
+	for _, d := range r.Diagnostics {
+		counts[d.Severity]++
+		if d.Fix != "" {
+			fixable++
+		}
+	}
+
+	var parts []string
+	for _, severity := range app.Severities {
+		n := counts[string(severity)]
+		switch {
+		case n == 0:
+		case severity == app.SeverityInfo:
+			parts = append(parts, fmt.Sprintf("%d info", n))
+		default:
+			parts = append(parts, plural(n, string(severity)))
+		}
+	}
+
+	summary := fmt.Sprintf("%s (%s)", plural(len(r.Diagnostics), "problem"), strings.Join(parts, ", "))
+	if fixable > 0 {
+		summary += fmt.Sprintf("; %d can be fixed with --fix", fixable)
+	}
+	return summary
+}
+
+// TSV returns one row per remaining problem.
+func (r *doctorResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Diagnostics))
+	for i, d := range r.Diagnostics {
+		rows[i] = []string{d.Severity, d.Check, strconv.Itoa(d.Line), strconv.Itoa(d.Column), d.Message, d.Fix}
+	}
+	return []string{"severity", "check", "line", "column", "message", "fix"}, rows
+}
+
+// plural formats a count with a noun, in the plural unless the count is one:
+// an "s" is added, or "y" becomes "ies".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Exit codes returned by mvnx. They are part of the public interface; see docs/output.md.
+const (
+	ExitOK              = 0
+	ExitError           = 1
+	ExitUsage           = 2
+	ExitNotFound        = 3
+	ExitAmbiguous       = 4
+	ExitNoStableVersion = 5
+	ExitNoProject       = 6
+	ExitPomParse        = 7
+	ExitNetwork         = 8
+	ExitConflict        = 9
+	ExitCheckFailed     = 10
+	ExitLicensePolicy   = 11
+	ExitCancelled       = 130
+)
+
+// errSelectionCancelled is returned when the user aborts an interactive prompt.
+var errSelectionCancelled = errors.New("selection cancelled")
+
+// checkFailedError reports that a check command, such as doctor, found problems.
+// The command has already printed them, so ReportError prints nothing more.
+type checkFailedError struct {
+	message string
+}
+
+func (e *checkFailedError) Error() string {
+	return e.message
+}
+
+// usageError reports invalid command-line arguments or flags.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// usageErrorf creates a usageError with a formatted message.
+func usageErrorf(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// errorInfo describes how an error is reported to the user.
+type errorInfo struct {
+	// Code is a stable, machine-readable identifier for the error class
+	Code string `json:"code" yaml:"code"`
+
+	// ExitCode is the process exit status
+	ExitCode int `json:"exitCode" yaml:"exitCode"`
+
+	// Message is the full human-readable error message
+	Message string `json:"message" yaml:"message"`
+
+	// Candidates lists the matches of an ambiguous query
+	Candidates []string `json:"candidates,omitempty" yaml:"candidates,omitempty"`
+}
+
+// classifyError maps an error to its code and exit status.
+func classifyError(err error) errorInfo {
+	info := errorInfo{Code: "error", ExitCode: ExitError, Message: err.Error()}
+
+	var (
+		usageErr      *usageError
+		validationErr *domain.ValidationError
+		notFoundErr   *domain.NotFoundError
+		ambiguousErr  *domain.AmbiguousError
+		noStableErr   *domain.NoStableVersionError
+		noProjectErr  *domain.ProjectNotFoundError
+		pomParseErr   *domain.PomParseError
+		networkErr    *domain.NetworkError
+		conflictErr   *domain.ConflictError
+		checkErr      *checkFailedError
+		policyErr     *domain.LicensePolicyError
+	)
+
+	switch {
+	case errors.Is(err, errSelectionCancelled):
+		info.Code, info.ExitCode = "cancelled", ExitCancelled
+	case errors.As(err, &usageErr), errors.As(err, &validationErr):
+		info.Code, info.ExitCode = "usage", ExitUsage
+	case errors.As(err, &ambiguousErr):
+		info.Code, info.ExitCode = "ambiguous", ExitAmbiguous
+		info.Candidates = ambiguousErr.Candidates
+	case errors.As(err, &noStableErr):
+		info.Code, info.ExitCode = "no_stable_version", ExitNoStableVersion
+	case errors.As(err, &notFoundErr):
+		info.Code, info.ExitCode = "not_found", ExitNotFound
+	case errors.As(err, &noProjectErr):
+		info.Code, info.ExitCode = "no_project", ExitNoProject
+	case errors.As(err, &pomParseErr):
+		info.Code, info.ExitCode = "pom_parse", ExitPomParse
+	case errors.As(err, &networkErr):
+		info.Code, info.ExitCode = "network", ExitNetwork
+	case errors.As(err, &conflictErr):
+		info.Code, info.ExitCode = "conflict", ExitConflict
+	case errors.As(err, &checkErr):
+		info.Code, info.ExitCode = "check_failed", ExitCheckFailed
+	case errors.As(err, &policyErr):
+		info.Code, info.ExitCode = "license_policy", ExitLicensePolicy
+	}
+
+	return info
+}
+
+// errorResult is the machine-readable representation of a failed command.
+type errorResult struct {
+	Error errorInfo `json:"error" yaml:"error"`
+}
+
+// WriteText prints the error message.
+func (r *errorResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Error: %s\n", r.Error.Message)
+	return err
+}
+
+// TSV returns the error as a single row.
+func (r *errorResult) TSV() ([]string, [][]string) {
+	return []string{"code", "exitCode", "message"},
+		[][]string{{r.Error.Code, fmt.Sprint(r.Error.ExitCode), r.Error.Message}}
+}
+
+// ReportError prints err in the selected output format and returns the exit code for it.
+// JSON and YAML errors are written to stdout so scripts can parse them; text and TSV go to stderr.
+func ReportError(err error) int {
+	result := &errorResult{Error: classifyError(err)}
+	if result.Error.Code == "check_failed" {
+		return result.Error.ExitCode
+	}
+
+	switch printer.Format() {
+	case output.FormatJSON, output.FormatYAML:
+		if printErr := printer.Print(result); printErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	default:
+		_ = result.WriteText(os.Stderr)
+	}
+
+	return result.Error.ExitCode
+}
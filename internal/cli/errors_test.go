@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     string
+		exitCode int
+	}{
+		{
+			name:     "generic error",
+			err:      errors.New("boom"),
+			code:     "error",
+			exitCode: ExitError,
+		},
+		{
+			name:     "usage error",
+			err:      usageErrorf("invalid --pick value: %d", 0),
+			code:     "usage",
+			exitCode: ExitUsage,
+		},
+		{
+			name:     "validation error",
+			err:      &domain.ValidationError{Field: "scope", Message: "invalid scope: foo"},
+			code:     "usage",
+			exitCode: ExitUsage,
+		},
+		{
+			name:     "wrapped not found",
+			err:      fmt.Errorf("failed to remove dependency: %w", &domain.NotFoundError{Kind: "dependency", Name: "lombok"}),
+			code:     "not_found",
+			exitCode: ExitNotFound,
+		},
+		{
+			name:     "ambiguous",
+			err:      &domain.AmbiguousError{Query: "postgres", Candidates: []string{"a:b:1", "c:d:2"}},
+			code:     "ambiguous",
+			exitCode: ExitAmbiguous,
+		},
+		{
+			name:     "no stable version",
+			err:      &domain.NoStableVersionError{Coordinates: "a:b", Latest: "1.0-RC1"},
+			code:     "no_stable_version",
+			exitCode: ExitNoStableVersion,
+		},
+		{
+			name:     "no project",
+			err:      &domain.ProjectNotFoundError{Path: "/tmp"},
+			code:     "no_project",
+			exitCode: ExitNoProject,
+		},
+		{
+			name:     "pom parse",
+			err:      &domain.PomParseError{Path: "pom.xml", Err: errors.New("unexpected EOF")},
+			code:     "pom_parse",
+			exitCode: ExitPomParse,
+		},
+		{
+			name:     "network",
+			err:      &domain.NetworkError{URL: "https://search.maven.org", StatusCode: 503},
+			code:     "network",
+			exitCode: ExitNetwork,
+		},
+		{
+			name:     "conflict",
+			err:      &domain.ConflictError{Path: "pom.xml", Reason: "pom.xml already exists"},
+			code:     "conflict",
+			exitCode: ExitConflict,
+		},
+		{
+			name:     "check failed",
+			err:      &checkFailedError{message: "2 problems found"},
+			code:     "check_failed",
+			exitCode: ExitCheckFailed,
+		},
+		{
+			name:     "license policy",
+			err:      fmt.Errorf("%w\nrerun with --allow-license", &domain.LicensePolicyError{Artifact: "org.example:lib:1.0", Verdict: domain.LicenseDenied, Licenses: []string{"GPL-3.0-only"}}),
+			code:     "license_policy",
+			exitCode: ExitLicensePolicy,
+		},
+		{
+			name:     "cancelled",
+			err:      errSelectionCancelled,
+			code:     "cancelled",
+			exitCode: ExitCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := classifyError(tt.err)
+			assert.Equal(t, tt.code, info.Code)
+			assert.Equal(t, tt.exitCode, info.ExitCode)
+			assert.Equal(t, tt.err.Error(), info.Message)
+		})
+	}
+}
+
+func TestClassifyError_AmbiguousCandidates(t *testing.T) {
+	err := fmt.Errorf("%w\nrerun with --pick", &domain.AmbiguousError{Query: "q", Candidates: []string{"a:b:1"}})
+
+	assert.Equal(t, []string{"a:b:1"}, classifyError(err).Candidates)
+}
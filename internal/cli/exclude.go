@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// removeExclusion flag for exclude command
+var removeExclusion bool
+
+// excludeCmd represents the exclude command
+var excludeCmd = &cobra.Command{
+	Use:   "exclude <dependency> <groupId:artifactId>...",
+	Short: "Exclude transitive dependencies from a dependency",
+	Long: `Add <exclusions> to a dependency so the given transitive dependencies are left out.
+
+The dependency is an artifactId, a groupId:artifactId, or a glob pattern such as
+"org.springframework.boot:*", in which case every matching dependency gets the
+exclusions. Exclusions are groupId:artifactId; Maven accepts "*" as a whole
+groupId or artifactId, e.g. "org.apache.logging.log4j:*".
+
+With --remove, exclusions matching the given patterns are removed instead.`,
+	Example: `  mvnx exclude spring-boot-starter-web commons-logging:commons-logging
+  mvnx exclude 'org.springframework.boot:*' 'org.apache.logging.log4j:*'
+  mvnx exclude --remove spring-boot-starter-web commons-logging`,
+	Args: usageArgs(cobra.MinimumNArgs(2)),
+	RunE: runExclude,
+}
+
+func init() {
+	excludeCmd.Flags().BoolVar(&removeExclusion, "remove", false, "remove matching exclusions instead of adding them")
+	excludeCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt; fail if the artifactId is ambiguous")
+}
+
+func runExclude(cmd *cobra.Command, args []string) error {
+	target, patterns := args[0], args[1:]
+
+	// Validate exclusions before touching the project
+	var exclusions []domain.Exclusion
+	if !removeExclusion {
+		for _, pattern := range patterns {
+			exclusion, err := domain.ParseExclusion(pattern)
+			if err != nil {
+				return err
+			}
+			exclusions = append(exclusions, exclusion)
+		}
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewExcludeDependencyService(pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	match, err := service.Match(target)
+	if err != nil {
+		return err
+	}
+	dependencies, err := selectDependencies(match)
+	if err != nil {
+		return err
+	}
+
+	var changes []app.ExclusionChange
+	if removeExclusion {
+		changes, err = service.RemoveExclusions(dependencies, patterns)
+	} else {
+		changes, err = service.Exclude(dependencies, exclusions)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := &excludeResult{
+		Pom:        project.PomLocation,
+		Removed:    removeExclusion,
+		Exclusions: make([]exclusionView, len(changes)),
+		DryRun:     dryRun,
+	}
+	descriptions := make([]string, len(changes))
+	for i, c := range changes {
+		result.Exclusions[i] = exclusionView{
+			Dependency: c.Dependency.ID(),
+			GroupID:    c.Exclusion.GroupID,
+			ArtifactID: c.Exclusion.ArtifactID,
+		}
+		descriptions[i] = c.Exclusion.String() + " from " + c.Dependency.ID()
+	}
+
+	if len(changes) > 0 {
+		verb := "exclude "
+		if removeExclusion {
+			verb = "remove exclusion "
+		}
+		change.Record(verb + strings.Join(descriptions, ", "))
+	}
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// exclusionView is the stable machine-readable representation of an exclusion change.
+type exclusionView struct {
+	// Dependency identifies the dependency the exclusion belongs to, see domain.Dependency.ID
+	Dependency string `json:"dependency" yaml:"dependency"`
+	GroupID    string `json:"groupId" yaml:"groupId"`
+	ArtifactID string `json:"artifactId" yaml:"artifactId"`
+}
+
+// excludeResult is the output of the exclude command.
+type excludeResult struct {
+	Pom        string          `json:"pom" yaml:"pom"`
+	Removed    bool            `json:"removed" yaml:"removed"`
+	Exclusions []exclusionView `json:"exclusions" yaml:"exclusions"`
+	DryRun     bool            `json:"dryRun" yaml:"dryRun"`
+	Diff       string          `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints a confirmation line per exclusion followed by the diff, if any.
+func (r *excludeResult) WriteText(w io.Writer) error {
+	if len(r.Exclusions) == 0 {
+		_, err := fmt.Fprintln(w, "Already excluded, nothing to change")
+		return err
+	}
+
+	verb := "✓ Excluded"
+	switch {
+	case r.Removed && r.DryRun:
+		verb = "Would remove exclusion"
+	case r.Removed:
+		verb = "✓ Removed exclusion"
+	case r.DryRun:
+		verb = "Would exclude"
+	}
+
+	for _, e := range r.Exclusions {
+		if _, err := fmt.Fprintf(w, "%s %s:%s from %s\n", verb, e.GroupID, e.ArtifactID, e.Dependency); err != nil {
+			return err
+		}
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per exclusion.
+func (r *excludeResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Exclusions))
+	for i, e := range r.Exclusions {
+		rows[i] = []string{e.Dependency, e.GroupID, e.ArtifactID, r.Pom}
+	}
+	return []string{"dependency", "groupId", "artifactId", "pom"}, rows
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/diff"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var (
+	fmtCheck  bool
+	fmtSortBy string
+)
+
+// fmtCmd represents the fmt command
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Format the pom.xml in canonical form",
+	Long: `Format the pom.xml in canonical form, so reviews no longer argue about layout:
+
+  - the elements of <project> follow the order of the Maven POM reference
+  - the dependencies of the project, <dependencyManagement> and profiles are
+    sorted by the --sort-by keys; BOM imports stay first, in their order
+  - every element is on its own line, indented one level deeper than its
+    parent, and runs of blank lines become one
+
+Comments move with the element they precede, or that they follow on the same
+line. Sorting changes the classpath order, which only matters when two
+dependencies contain the same classes.
+
+Once a pom.xml with three or more dependencies is sorted by the default keys,
+mvnx add inserts new dependencies in sorted position.
+With --check, nothing is written and the command exits with status 10 when the
+pom.xml is not formatted, for use in CI.`,
+	Example: `  mvnx fmt
+  mvnx fmt --check --diff
+  mvnx fmt --sort-by groupId,artifactId`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runFmt,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "only check whether the pom.xml is formatted, exiting with status 10 if not")
+	fmtCmd.Flags().StringVar(&fmtSortBy, "sort-by", sortKeysString(domain.DefaultDependencySortKeys), "comma-separated dependency sort keys (scope, groupId, artifactId), or none")
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	keys, err := domain.ParseDependencySortKeys(fmtSortBy)
+	if err != nil {
+		return err
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	if fmtCheck {
+		return runFmtCheck(project.PomLocation, keys)
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewFormatService(pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	changed, err := service.Format(keys)
+	if err != nil {
+		return err
+	}
+	if changed {
+		change.Record("fmt")
+	}
+
+	result := &fmtResult{Pom: project.PomLocation, Changed: changed, DryRun: dryRun}
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// runFmtCheck reports whether the pom.xml is formatted without changing it.
+func runFmtCheck(pomPath string, keys []domain.DependencySortKey) error {
+	// Reading needs no project lock
+	pomRepo := xml.NewPomRepository()
+	service := app.NewFormatService(pomRepo, nil)
+	if err := service.ReadPom(pomPath); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	change, err := beginPomChange(pomPath)
+	if err != nil {
+		return err
+	}
+
+	changed, err := service.Check(keys)
+	if err != nil {
+		return err
+	}
+
+	result := &fmtResult{Pom: pomPath, Changed: changed, Check: true}
+
+	// The formatted pom.xml only exists in memory, whatever --dry-run says
+	if changed && (showDiff || dryRun) {
+		after, err := pomRepo.Render()
+		if err != nil {
+			return err
+		}
+		name := diffName(pomPath)
+		result.Diff = diff.Unified("a/"+name, "b/"+name, string(change.before), string(after))
+	}
+
+	if err := printer.Print(result); err != nil {
+		return err
+	}
+	if changed {
+		return &checkFailedError{message: "pom.xml is not formatted"}
+	}
+	return nil
+}
+
+// sortKeysString joins sort keys as ParseDependencySortKeys accepts them.
+func sortKeysString(keys []domain.DependencySortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = string(key)
+	}
+	return strings.Join(parts, ",")
+}
+
+// fmtResult is the output of the fmt command.
+type fmtResult struct {
+	Pom string `json:"pom" yaml:"pom"`
+
+	// Changed tells whether formatting changes the pom.xml; with --check it
+	// means the pom.xml is not formatted
+	Changed bool   `json:"changed" yaml:"changed"`
+	Check   bool   `json:"check" yaml:"check"`
+	DryRun  bool   `json:"dryRun" yaml:"dryRun"`
+	Diff    string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints whether the pom.xml was, or needs to be, formatted, followed by the diff, if any.
+func (r *fmtResult) WriteText(w io.Writer) error {
+	name := diffName(r.Pom)
+
+	var message string
+	switch {
+	case !r.Changed:
+		message = name + " is already formatted"
+	case r.Check:
+		message = name + " is not formatted; run 'mvnx fmt' to fix it"
+	case r.DryRun:
+		message = "Would format " + name
+	default:
+		message = "✓ Formatted " + name
+	}
+
+	if _, err := fmt.Fprintln(w, message); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns the outcome as a single row.
+func (r *fmtResult) TSV() ([]string, [][]string) {
+	return []string{"pom", "changed", "check"},
+		[][]string{{r.Pom, fmt.Sprint(r.Changed), fmt.Sprint(r.Check)}}
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/diff"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+var (
+	// showPatch flag for history command
+	showPatch bool
+
+	// forceUndo flag for undo command
+	forceUndo bool
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the pom.xml changes made by mvnx",
+	Long: `Show the pom.xml changes made by mvnx in this project, newest first.
+The journal is kept in .mvnx/history next to pom.xml and holds the last 50 operations.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runHistory,
+}
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo [n]",
+	Short: "Revert the last n mvnx operations",
+	Long: `Revert the last n (default 1) pom.xml changes made by mvnx.
+
+Undo refuses to run if pom.xml was edited by hand after those operations,
+because reverting would discard the manual edits. Use --force to revert anyway.`,
+	Args: usageArgs(cobra.MaximumNArgs(1)),
+	RunE: runUndo,
+}
+
+func init() {
+	historyCmd.Flags().BoolVarP(&showPatch, "patch", "p", false, "show the diff of each operation")
+	undoCmd.Flags().BoolVar(&forceUndo, "force", false, "revert even if pom.xml was modified outside mvnx")
+}
+
+// historyEntryView is the stable machine-readable representation of a history entry.
+type historyEntryView struct {
+	ID          int    `json:"id" yaml:"id"`
+	Time        string `json:"time" yaml:"time"`
+	Description string `json:"description" yaml:"description"`
+	Added       int    `json:"linesAdded" yaml:"linesAdded"`
+	Removed     int    `json:"linesRemoved" yaml:"linesRemoved"`
+	Diff        string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// newHistoryEntryView converts a history entry to its view, including its diff if requested.
+func newHistoryEntryView(entry *domain.HistoryEntry, withDiff bool) historyEntryView {
+	view := historyEntryView{
+		ID:          entry.ID,
+		Time:        entry.Time.Format(time.RFC3339),
+		Description: entry.Description,
+	}
+
+	for _, hunk := range diff.Hunks(string(entry.Before), string(entry.After), 0) {
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case diff.Insert:
+				view.Added++
+			case diff.Delete:
+				view.Removed++
+			}
+		}
+	}
+
+	if withDiff {
+		view.Diff = diff.Unified("a/pom.xml", "b/pom.xml", string(entry.Before), string(entry.After))
+	}
+
+	return view
+}
+
+// historyResult is the output of the history command.
+type historyResult struct {
+	Pom     string             `json:"pom" yaml:"pom"`
+	Entries []historyEntryView `json:"entries" yaml:"entries"`
+}
+
+// WriteText prints one line per entry, followed by its diff when requested.
+func (r *historyResult) WriteText(w io.Writer) error {
+	if len(r.Entries) == 0 {
+		_, err := fmt.Fprintln(w, "No mvnx operations recorded")
+		return err
+	}
+
+	for _, e := range r.Entries {
+		if _, err := fmt.Fprintf(w, "#%d  %s  %s  (+%d -%d)\n", e.ID, e.Time, e.Description, e.Added, e.Removed); err != nil {
+			return err
+		}
+		if err := writeDiff(w, e.Diff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TSV returns one row per entry.
+func (r *historyResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Entries))
+	for i, e := range r.Entries {
+		rows[i] = []string{strconv.Itoa(e.ID), e.Time, e.Description, strconv.Itoa(e.Added), strconv.Itoa(e.Removed)}
+	}
+	return []string{"id", "time", "description", "linesAdded", "linesRemoved"}, rows
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	service := newHistoryService(project)
+
+	entries, err := service.Entries()
+	if err != nil {
+		return err
+	}
+
+	result := &historyResult{
+		Pom:     project.PomLocation,
+		Entries: make([]historyEntryView, 0, len(entries)),
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		result.Entries = append(result.Entries, newHistoryEntryView(entries[i], showPatch))
+	}
+
+	return printer.Print(result)
+}
+
+// undoResult is the output of the undo command.
+type undoResult struct {
+	Pom    string             `json:"pom" yaml:"pom"`
+	Undone []historyEntryView `json:"undone" yaml:"undone"`
+	DryRun bool               `json:"dryRun" yaml:"dryRun"`
+	Diff   string             `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints the reverted operations followed by the diff, if any.
+func (r *undoResult) WriteText(w io.Writer) error {
+	verb := "✓ Undid"
+	if r.DryRun {
+		verb = "Would undo"
+	}
+	for _, e := range r.Undone {
+		if _, err := fmt.Fprintf(w, "%s #%d %s\n", verb, e.ID, e.Description); err != nil {
+			return err
+		}
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per reverted operation.
+func (r *undoResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Undone))
+	for i, e := range r.Undone {
+		rows[i] = []string{strconv.Itoa(e.ID), e.Time, e.Description}
+	}
+	return []string{"id", "time", "description"}, rows
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	count := 1
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return usageErrorf("invalid number of operations: %s (must be 1 or greater)", args[0])
+		}
+		count = n
+	}
+
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	service := newHistoryService(project)
+
+	undo, err := service.Undo(count, forceUndo, dryRun)
+	if err != nil {
+		return err
+	}
+
+	result := &undoResult{
+		Pom:    project.PomLocation,
+		Undone: make([]historyEntryView, len(undo.Undone)),
+		DryRun: dryRun,
+	}
+	for i, entry := range undo.Undone {
+		result.Undone[i] = newHistoryEntryView(entry, false)
+	}
+	if dryRun || showDiff {
+		name := diffName(project.PomLocation)
+		result.Diff = diff.Unified("a/"+name, "b/"+name, string(undo.Before), string(undo.After))
+	}
+
+	return printer.Print(result)
+}
+
+// newHistoryService creates the history service for a project.
+func newHistoryService(project *domain.Project) *app.HistoryService {
+	return app.NewHistoryService(fs.NewHistoryStore(filepath.Dir(project.PomLocation)), newLocker(), project.PomLocation)
+}
@@ -2,11 +2,34 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/diff"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+)
+
+var (
+	// template flag for init command
+	initTemplate string
+
+	// project flags for init command
+	initGroupID    string
+	initArtifactID string
+	initJava       string
+	initPackage    string
+
+	// archetype flags for init command
+	initArchetype  string
+	initCatalog    string
+	initProperties []string
 )
 
 // initCmd represents the init command
@@ -14,27 +37,188 @@ var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize a new Maven project",
 	Long: `Initialize a new Maven project in the current directory.
-Creates a minimal pom.xml and standard directory structure.`,
+Creates a pom.xml and standard directory structure from a template.
+
+Built-in templates:
+  minimal      pom.xml only (default)
+  library      jar with JUnit 5 and a starter class and test
+  app          executable jar with a main class
+  cli          command-line app with picocli, packaged as a single jar
+  spring-boot  Spring Boot web application
+  quarkus      Quarkus REST application
+  micronaut    Micronaut HTTP application
+
+--template also accepts the path of a directory holding your own template.
+Files ending in ".tmpl" are rendered with Go's text/template, can use
+{{.GroupID}}, {{.ArtifactID}}, {{.Version}}, {{.JavaVersion}}, {{.Package}} and
+{{.PackagePath}}, and lose the suffix; other files, such as mvnw, are copied
+as they are, permissions included. A "__package__" directory is replaced by the
+package directories.
+
+--archetype creates the project from a Maven archetype instead, without a JVM.
+The archetype jar is read from the local repository (~/.m2/repository) or
+downloaded from the repository named in the catalog, else from the repository
+serving the catalog, else from Maven Central or the repository named by the
+MAVEN_REPO_URL environment variable. Without a version, the newest version in
+the catalog is used; --catalog selects the catalog: a file, a URL, "local"
+(~/.m2/repository/archetype-catalog.xml, the default) or "remote" (that of
+Maven Central or MAVEN_REPO_URL). Archetype properties without a default are
+set with --property.`,
+	Example: `  mvnx init
+  mvnx init --template spring-boot --group-id com.acme --artifact-id orders --java 21
+  mvnx init --template ~/templates/service --package com.acme.billing
+  mvnx init --archetype org.apache.maven.archetypes:maven-archetype-quickstart:1.4 --group-id com.acme
+  mvnx init --archetype com.acme:service-archetype --catalog https://repo.acme.com/archetype-catalog.xml --property port=8080`,
+	Args: usageArgs(cobra.NoArgs),
 	RunE: runInit,
 }
 
+func init() {
+	initCmd.Flags().StringVar(&initTemplate, "template", fs.DefaultTemplate, "project template: "+strings.Join(fs.TemplateNames(), ", ")+", or a template directory")
+	initCmd.Flags().StringVar(&initGroupID, "group-id", domain.DefaultProjectGroupID, "groupId of the project")
+	initCmd.Flags().StringVar(&initArtifactID, "artifact-id", domain.DefaultProjectArtifactID, "artifactId of the project")
+	initCmd.Flags().StringVar(&initJava, "java", domain.DefaultJavaVersion, "Java release level, e.g. 21")
+	initCmd.Flags().StringVar(&initPackage, "package", "", "base package of the generated sources (default groupId.artifactId)")
+	initCmd.Flags().StringVar(&initArchetype, "archetype", "", "create the project from a Maven archetype, groupId:artifactId[:version]")
+	initCmd.Flags().StringVar(&initCatalog, "catalog", "", "archetype catalog: a path, a URL, local or remote (default local)")
+	initCmd.Flags().StringArrayVar(&initProperties, "property", nil, "archetype property as key=value (repeatable)")
+}
+
 func runInit(cmd *cobra.Command, args []string) error {
+	if initArchetype != "" && cmd.Flags().Changed("template") {
+		return usageErrorf("--template and --archetype cannot be used together")
+	}
+	if initArchetype == "" && (initCatalog != "" || len(initProperties) > 0) {
+		return usageErrorf("--catalog and --property require --archetype")
+	}
+
+	spec, err := domain.NewProjectSpec(initGroupID, initArtifactID, initJava, initPackage)
+	if err != nil {
+		return err
+	}
+
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	// Initialize project
-	service := app.NewInitProjectService()
-	if err := service.Init(cwd); err != nil {
+	result := &initResult{
+		Pom:    filepath.Join(cwd, "pom.xml"),
+		DryRun: dryRun,
+	}
+
+	var files []fs.ProjectFile
+	if initArchetype != "" {
+		files, err = initFromArchetype(cwd, spec, result)
+	} else {
+		files, err = initFromTemplate(cwd, spec, result)
+	}
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("✓ Initialized Maven project")
-	fmt.Println("  Created pom.xml")
-	fmt.Println("  Created src/main/java")
-	fmt.Println("  Created src/test/java")
+	var diffs []string
+	for _, file := range files {
+		if file.Path != "pom.xml" {
+			result.Files = append(result.Files, file.Path)
+		}
+		if dryRun || showDiff {
+			diffs = append(diffs, diff.Unified("/dev/null", "b/"+file.Path, "", string(file.Content)))
+		}
+	}
+	result.Diff = strings.Join(diffs, "")
+
+	return printer.Print(result)
+}
 
-	return nil
+// initFromTemplate creates the project from the --template template.
+func initFromTemplate(cwd string, spec *domain.ProjectSpec, result *initResult) ([]fs.ProjectFile, error) {
+	service := app.NewInitProjectService(nil)
+
+	result.Template = initTemplate
+	result.Directories = fs.ProjectDirectories
+
+	if dryRun {
+		return service.Preview(cwd, initTemplate, spec)
+	}
+	return service.Init(cwd, initTemplate, spec)
+}
+
+// initFromArchetype creates the project from the --archetype archetype.
+func initFromArchetype(cwd string, spec *domain.ProjectSpec, result *initResult) ([]fs.ProjectFile, error) {
+	requested, err := domain.ParseArchetypeCoordinates(initArchetype)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]string, len(initProperties))
+	for _, property := range initProperties {
+		key, value, ok := strings.Cut(property, "=")
+		if !ok || key == "" {
+			return nil, usageErrorf("invalid --property %q (expected key=value)", property)
+		}
+		properties[key] = value
+	}
+
+	service := app.NewInitProjectService(maven.NewArchetypeRepository(initCatalog))
+	logf("Generating from archetype: %s\n", requested)
+
+	var used *domain.Archetype
+	var files []fs.ProjectFile
+	if dryRun {
+		used, files, err = service.PreviewArchetype(cwd, requested, spec, properties)
+	} else {
+		used, files, err = service.InitArchetype(cwd, requested, spec, properties)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Archetype = used.String()
+	result.Directories = []string{}
+	return files, nil
+}
+
+// initResult is the output of the init command.
+type initResult struct {
+	Pom         string   `json:"pom" yaml:"pom"`
+	Template    string   `json:"template,omitempty" yaml:"template,omitempty"`
+	Archetype   string   `json:"archetype,omitempty" yaml:"archetype,omitempty"`
+	Files       []string `json:"files,omitempty" yaml:"files,omitempty"`
+	Directories []string `json:"directories" yaml:"directories"`
+	DryRun      bool     `json:"dryRun" yaml:"dryRun"`
+	Diff        string   `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints what was created, followed by the diff, if any.
+func (r *initResult) WriteText(w io.Writer) error {
+	lines := []string{"✓ Initialized Maven project", "  Created pom.xml"}
+	verb := "  Created "
+	if r.DryRun {
+		lines = []string{"Would initialize Maven project", "  Would create pom.xml"}
+		verb = "  Would create "
+	}
+	for _, file := range r.Files {
+		lines = append(lines, verb+file)
+	}
+	for _, dir := range r.Directories {
+		lines = append(lines, verb+dir)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(lines, "\n")); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per created path.
+func (r *initResult) TSV() ([]string, [][]string) {
+	rows := [][]string{{"file", r.Pom}}
+	for _, file := range r.Files {
+		rows = append(rows, []string{"file", file})
+	}
+	for _, dir := range r.Directories {
+		rows = append(rows, []string{"directory", dir})
+	}
+	return []string{"kind", "path"}, rows
 }
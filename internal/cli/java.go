@@ -0,0 +1,350 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// javaCmd represents the java command
+var javaCmd = &cobra.Command{
+	Use:   "java",
+	Short: "Show or change the Java release level of the project",
+	Long: `Show or change the Java release level, wherever the pom.xml declares it:
+
+  - the maven.compiler.release, source and target properties (and their test
+    variants), and java.version, used by parents such as Spring Boot's
+  - <release>, <source> and <target> of maven-compiler-plugin, including
+    <pluginManagement> and execution configurations
+  - the JDK version required through maven-toolchains-plugin
+
+Both commands warn about dependencies whose classes were compiled for a newer
+Java release, which the project could not run with. Only jars already in the
+local repository (~/.m2/repository) are checked.`,
+}
+
+// javaShowCmd represents the java show command
+var javaShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show where the Java release level is declared",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runJavaShow,
+}
+
+// javaSetCmd represents the java set command
+var javaSetCmd = &cobra.Command{
+	Use:   "set <release>",
+	Short: "Change the Java release level everywhere it is declared",
+	Long: `Change every declaration of the Java release level to the given release, so
+they no longer drift apart. Values keep their style: "1.8" stays in the "1.x"
+form and toolchain ranges such as "[17,)" keep their brackets.
+
+A declaration that refers to a property, e.g. <release>${java.version}</release>,
+follows the property instead; a property defined by a parent is overridden in the
+pom.xml. Without any declaration, maven.compiler.release is added.`,
+	Example: `  mvnx java set 21
+  mvnx java set 17 --dry-run`,
+	Args: exactArgs(1),
+	RunE: runJavaSet,
+}
+
+func init() {
+	javaCmd.AddCommand(javaShowCmd)
+	javaCmd.AddCommand(javaSetCmd)
+}
+
+func runJavaShow(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Reading needs no project lock
+	service := app.NewJavaService(xml.NewPomRepository(), nil, maven.NewLocalRepository())
+	if err := service.ReadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	levels, err := service.Show()
+	if err != nil {
+		return err
+	}
+
+	result := &javaShowResult{
+		Pom:          project.PomLocation,
+		Releases:     app.Releases(levels),
+		Declarations: make([]javaDeclarationView, len(levels)),
+	}
+	for i, level := range levels {
+		result.Declarations[i] = javaDeclarationView{
+			Location: level.Declaration.Location,
+			Kind:     level.Declaration.Kind,
+			Value:    level.Declaration.Value,
+			Release:  level.Release,
+		}
+	}
+
+	// Dependencies must run on the oldest release the project is compiled for
+	if len(result.Releases) > 0 {
+		result.javaDependencyCheck, err = checkJavaDependencies(service, project.PomLocation, result.Releases[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	return printer.Print(result)
+}
+
+func runJavaSet(cmd *cobra.Command, args []string) error {
+	release, err := domain.ParseJavaRelease(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewJavaService(pomRepo, newLocker(), maven.NewLocalRepository())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	changes, err := service.Set(release)
+	if err != nil {
+		return err
+	}
+
+	result := &javaSetResult{
+		Pom:     project.PomLocation,
+		Release: release,
+		Changes: make([]javaChangeView, len(changes)),
+		DryRun:  dryRun,
+	}
+	for i, c := range changes {
+		result.Changes[i] = javaChangeView{Location: c.Location, From: c.From, To: c.To}
+	}
+	if len(changes) > 0 {
+		change.Record("java set " + strconv.Itoa(release))
+	}
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	// The release is already changed, so a failing check only warns
+	result.javaDependencyCheck, err = checkJavaDependencies(service, project.PomLocation, release)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check the Java release of the dependencies: %v\n", err)
+	}
+
+	return printer.Print(result)
+}
+
+// checkJavaDependencies finds the direct dependencies compiled for a newer release than release.
+func checkJavaDependencies(service *app.JavaService, pomPath string, release int) (javaDependencyCheck, error) {
+	check := javaDependencyCheck{NewerDependencies: []newerDependencyView{}}
+
+	lister := app.NewListDependenciesService(xml.NewPomRepository(), maven.NewLocalRepository(), loadPom)
+	if err := lister.LoadPom(pomPath); err != nil {
+		return check, fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	declared, err := lister.List(app.ListFilter{Direct: true})
+	if err != nil {
+		return check, err
+	}
+
+	newer, unchecked, err := service.CheckDependencies(declared, release)
+	if err != nil {
+		return check, err
+	}
+
+	check.Unchecked = unchecked
+	for _, n := range newer {
+		check.NewerDependencies = append(check.NewerDependencies, newerDependencyView{
+			Dependency: n.Dependency.ID(),
+			Version:    n.Version,
+			Release:    n.Release,
+		})
+	}
+	return check, nil
+}
+
+// javaDeclarationView is the stable machine-readable representation of a Java level declaration.
+type javaDeclarationView struct {
+	Location string `json:"location" yaml:"location"`
+	Kind     string `json:"kind" yaml:"kind"`
+	Value    string `json:"value" yaml:"value"`
+
+	// Release is 0 when the value refers to a property defined elsewhere
+	Release int `json:"release" yaml:"release"`
+}
+
+// newerDependencyView is a dependency compiled for a newer Java release than the project.
+type newerDependencyView struct {
+	Dependency string `json:"dependency" yaml:"dependency"`
+	Version    string `json:"version" yaml:"version"`
+	Release    int    `json:"release" yaml:"release"`
+}
+
+// javaDependencyCheck is the outcome of checking the class files of the dependencies.
+type javaDependencyCheck struct {
+	NewerDependencies []newerDependencyView `json:"newerDependencies" yaml:"newerDependencies"`
+
+	// Unchecked counts the dependencies whose jar is not in the local repository
+	// or cannot be read
+	Unchecked int `json:"unchecked" yaml:"unchecked"`
+}
+
+// writeWarnings prints a warning per dependency compiled for a newer release.
+func (c javaDependencyCheck) writeWarnings(w io.Writer, release int) error {
+	for _, d := range c.NewerDependencies {
+		if _, err := fmt.Fprintf(w, "Warning: %s:%s is compiled for Java %d, newer than Java %d\n", d.Dependency, d.Version, d.Release, release); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// javaShowResult is the output of the java show command.
+type javaShowResult struct {
+	Pom string `json:"pom" yaml:"pom"`
+
+	// Releases are the distinct releases declared; more than one means they disagree
+	Releases     []int                 `json:"releases" yaml:"releases"`
+	Declarations []javaDeclarationView `json:"declarations" yaml:"declarations"`
+
+	javaDependencyCheck `yaml:",inline"`
+}
+
+// WriteText prints the declarations as an aligned table, followed by a summary.
+func (r *javaShowResult) WriteText(w io.Writer) error {
+	if len(r.Declarations) == 0 {
+		_, err := fmt.Fprintln(w, "No Java release level declared; it comes from a parent or the compiler plugin defaults")
+		return err
+	}
+
+	rows := make([][]string, len(r.Declarations))
+	for i, d := range r.Declarations {
+		release := "-"
+		if d.Release != 0 {
+			release = strconv.Itoa(d.Release)
+		}
+		rows[i] = []string{d.Location, d.Value, release}
+	}
+	if err := output.WriteTable(w, []string{"LOCATION", "VALUE", "RELEASE"}, rows); err != nil {
+		return err
+	}
+
+	var summary string
+	switch len(r.Releases) {
+	case 0:
+		summary = "Java release: unknown (set by a parent)"
+	case 1:
+		summary = fmt.Sprintf("Java release: %d", r.Releases[0])
+	default:
+		releases := make([]string, len(r.Releases))
+		for i, release := range r.Releases {
+			releases[i] = strconv.Itoa(release)
+		}
+		summary = fmt.Sprintf("Java release levels disagree: %s; align them with 'mvnx java set <release>'", strings.Join(releases, ", "))
+	}
+	if _, err := fmt.Fprintf(w, "\n%s\n", summary); err != nil {
+		return err
+	}
+
+	if len(r.Releases) == 0 {
+		return nil
+	}
+	return r.writeWarnings(w, r.Releases[0])
+}
+
+// TSV returns one row per declaration.
+func (r *javaShowResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Declarations))
+	for i, d := range r.Declarations {
+		rows[i] = []string{d.Location, d.Kind, d.Value, strconv.Itoa(d.Release)}
+	}
+	return []string{"location", "kind", "value", "release"}, rows
+}
+
+// javaChangeView is the stable machine-readable representation of a changed declaration.
+type javaChangeView struct {
+	Location string `json:"location" yaml:"location"`
+
+	// From is omitted for added properties
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// javaSetResult is the output of the java set command.
+type javaSetResult struct {
+	Pom     string           `json:"pom" yaml:"pom"`
+	Release int              `json:"release" yaml:"release"`
+	Changes []javaChangeView `json:"changes" yaml:"changes"`
+	DryRun  bool             `json:"dryRun" yaml:"dryRun"`
+	Diff    string           `json:"diff,omitempty" yaml:"diff,omitempty"`
+
+	javaDependencyCheck `yaml:",inline"`
+}
+
+// WriteText prints a line per change and the dependency warnings, followed by the diff, if any.
+func (r *javaSetResult) WriteText(w io.Writer) error {
+	var lines []string
+	switch {
+	case len(r.Changes) == 0:
+		lines = append(lines, fmt.Sprintf("Already Java %d, nothing to change", r.Release))
+	case r.DryRun:
+		lines = append(lines, fmt.Sprintf("Would set Java release to %d", r.Release))
+	default:
+		lines = append(lines, fmt.Sprintf("✓ Set Java release to %d", r.Release))
+	}
+	for _, c := range r.Changes {
+		if c.From == "" {
+			lines = append(lines, fmt.Sprintf("  %s: added %s", c.Location, c.To))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s: %s → %s", c.Location, c.From, c.To))
+		}
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(lines, "\n")); err != nil {
+		return err
+	}
+
+	if err := r.writeWarnings(w, r.Release); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per change.
+func (r *javaSetResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Changes))
+	for i, c := range r.Changes {
+		rows[i] = []string{c.Location, c.From, c.To, r.Pom}
+	}
+	return []string{"location", "from", "to", "pom"}, rows
+}
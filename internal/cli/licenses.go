@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var (
+	licensesDirect  bool
+	licensesOffline bool
+)
+
+// licensesCmd represents the licenses command
+var licensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: "Report the licenses of the dependencies",
+	Long: `Report the license of every dependency of the project, direct and
+transitive, at the version Maven picks. Licenses are read from the <licenses>
+of each artifact's pom, or of its nearest parent declaring some, and normalized
+to SPDX identifiers (e.g. "The Apache Software License, Version 2.0" is
+Apache-2.0). Licenses whose name and URL are not recognized are reported
+without an identifier.
+
+Poms missing from the local Maven repository are downloaded from Maven Central,
+or from the repository named by the MAVEN_REPO_URL environment variable, into
+the local repository; use --offline to read the local repository only.
+
+Use --output csv or tsv for a spreadsheet, or json for tooling.`,
+	Example: `  mvnx licenses
+  mvnx licenses --direct
+  mvnx licenses --output csv > licenses.csv`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runLicenses,
+}
+
+func init() {
+	licensesCmd.Flags().BoolVar(&licensesDirect, "direct", false, "only report the dependencies declared by the project")
+	licensesCmd.Flags().BoolVar(&licensesOffline, "offline", false, "read poms from the local Maven repository only")
+}
+
+func runLicenses(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	service := app.NewLicenseService(xml.NewPomRepository(), newPomLocator(licensesOffline), loadPom)
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	report, err := service.Report(!licensesDirect)
+	if err != nil {
+		return err
+	}
+
+	result := &licensesResult{Pom: project.PomLocation, Artifacts: []artifactLicensesView{}}
+	var problems []string
+	for _, artifact := range report.Artifacts {
+		result.Artifacts = append(result.Artifacts, newArtifactLicensesView(artifact))
+		if artifact.Problem != "" {
+			problems = append(problems, artifact.Dependency.ID()+" ("+artifact.Problem+")")
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: could not read the licenses of %s: %s\n",
+			plural(len(problems), "artifact"), strings.Join(problems, ", "))
+	}
+	warnUnresolved(report.Unresolved)
+
+	return printer.Print(result)
+}
+
+// newPomLocator returns where to find the poms of artifacts: the local Maven
+// repository, downloading the missing ones unless offline.
+func newPomLocator(offline bool) domain.PomLocator {
+	local := maven.NewLocalRepository()
+	if offline {
+		return local
+	}
+	return maven.NewRemoteRepository(local)
+}
+
+// licenseView is the stable machine-readable representation of a license.
+type licenseView struct {
+	// SPDX is the SPDX identifier; empty when the license is not recognized
+	SPDX string `json:"spdx" yaml:"spdx"`
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url" yaml:"url"`
+}
+
+// artifactLicensesView is the stable machine-readable representation of the
+// licenses of an artifact.
+type artifactLicensesView struct {
+	Dependency string `json:"dependency" yaml:"dependency"`
+	Version    string `json:"version" yaml:"version"`
+	Scope      string `json:"scope" yaml:"scope"`
+
+	// Path leads from a direct dependency to a transitive one; empty for direct dependencies
+	Path     []string      `json:"path" yaml:"path"`
+	Licenses []licenseView `json:"licenses" yaml:"licenses"`
+
+	// Source is the pom declaring the licenses, the artifact or one of its parents
+	Source  string `json:"source" yaml:"source"`
+	Problem string `json:"problem,omitempty" yaml:"problem,omitempty"`
+}
+
+// newArtifactLicensesView converts the licenses of an artifact to their view.
+func newArtifactLicensesView(a *app.ArtifactLicenses) artifactLicensesView {
+	view := artifactLicensesView{
+		Dependency: a.Dependency.ID(),
+		Version:    a.Dependency.Version,
+		Scope:      a.Scope,
+		Path:       a.Path,
+		Licenses:   []licenseView{},
+		Source:     a.Source,
+		Problem:    a.Problem,
+	}
+	if view.Path == nil {
+		view.Path = []string{}
+	}
+	for _, l := range a.Licenses {
+		view.Licenses = append(view.Licenses, licenseView{SPDX: l.SPDX, Name: l.Name, URL: l.URL})
+	}
+	return view
+}
+
+// label returns the SPDX identifier of the license, or else its name or URL.
+func (l licenseView) label() string {
+	switch {
+	case l.SPDX != "":
+		return l.SPDX
+	case l.Name != "":
+		return l.Name
+	default:
+		return l.URL
+	}
+}
+
+// licensesResult is the output of the licenses command.
+type licensesResult struct {
+	Pom       string                 `json:"pom" yaml:"pom"`
+	Artifacts []artifactLicensesView `json:"artifacts" yaml:"artifacts"`
+}
+
+// WriteText prints a table of the artifacts and their licenses, then a summary.
+func (r *licensesResult) WriteText(w io.Writer) error {
+	if len(r.Artifacts) == 0 {
+		_, err := fmt.Fprintln(w, "No dependencies")
+		return err
+	}
+
+	rows := make([][]string, len(r.Artifacts))
+	unknown := 0
+	for i, a := range r.Artifacts {
+		labels := make([]string, len(a.Licenses))
+		for j, l := range a.Licenses {
+			labels[j] = l.label()
+		}
+		license := strings.Join(labels, " OR ")
+		switch {
+		case a.Problem != "":
+			license = "? (unavailable)"
+		case len(a.Licenses) == 0:
+			license = "? (none declared)"
+		}
+		if a.Problem != "" || !a.identified() {
+			unknown++
+		}
+		rows[i] = []string{a.Dependency, orDash(a.Version), a.Scope, license}
+	}
+	if err := output.WriteTable(w, []string{"DEPENDENCY", "VERSION", "SCOPE", "LICENSE"}, rows); err != nil {
+		return err
+	}
+
+	summary := plural(len(r.Artifacts), "artifact")
+	if unknown > 0 {
+		summary += fmt.Sprintf("; %d without an SPDX license", unknown)
+	}
+	_, err := fmt.Fprintln(w, summary)
+	return err
+}
+
+// identified reports whether the artifact declares licenses that all have an
+// SPDX identifier.
+func (a artifactLicensesView) identified() bool {
+	for _, l := range a.Licenses {
+		if l.SPDX == "" {
+			return false
+		}
+	}
+	return len(a.Licenses) > 0
+}
+
+// TSV returns one row per license of an artifact, and a row with empty license
+// columns for artifacts without any.
+func (r *licensesResult) TSV() ([]string, [][]string) {
+	var rows [][]string
+	for _, a := range r.Artifacts {
+		path := strings.Join(a.Path, " > ")
+		if len(a.Licenses) == 0 {
+			rows = append(rows, []string{a.Dependency, a.Version, a.Scope, "", "", "", a.Source, path})
+		}
+		for _, l := range a.Licenses {
+			rows = append(rows, []string{a.Dependency, a.Version, a.Scope, l.SPDX, l.Name, l.URL, a.Source, path})
+		}
+	}
+	return []string{"dependency", "version", "scope", "spdx", "name", "url", "source", "path"}, rows
+}
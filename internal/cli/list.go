@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+var (
+	// filter flags for list command
+	listScope   string
+	listGroup   string
+	listDirect  bool
+	listManaged bool
+	listProfile string
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the dependencies declared in the project",
+	Long: `List the dependencies declared in the project's pom.xml, with the version as
+written and the version Maven will use.
+
+The version source is one of:
+  literal   written in the declaration
+  property  a ${property} reference
+  managed   the project's own <dependencyManagement>
+  parent    the <dependencyManagement> of a parent pom
+  bom       a BOM imported in <dependencyManagement>
+  unknown   not declared or managed anywhere mvnx can see
+
+Parents and BOMs are read from the parent's relativePath and from the local
+Maven repository (~/.m2/repository); nothing is downloaded. Versions defined in
+poms that are not available locally are left unresolved.
+
+--profile lists the dependencies declared in a <profile> instead of those of the
+project itself.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listScope, "scope", "", "only list dependencies with this scope")
+	listCmd.Flags().StringVar(&listGroup, "group", "", "only list dependencies whose groupId matches this pattern (e.g. org.junit.*)")
+	listCmd.Flags().BoolVar(&listDirect, "direct", false, "only list <dependencies> entries")
+	listCmd.Flags().BoolVar(&listManaged, "managed", false, "only list <dependencyManagement> entries")
+	listCmd.Flags().StringVar(&listProfile, "profile", "", "list the <dependencies> of this profile")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	if listDirect && listManaged {
+		return usageErrorf("--direct and --managed cannot be used together")
+	}
+	if listProfile != "" && listManaged {
+		return usageErrorf("--profile and --managed cannot be used together")
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	service := app.NewListDependenciesService(xml.NewPomRepository(), maven.NewLocalRepository(), loadPom)
+
+	// Load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	declared, err := service.List(app.ListFilter{
+		Scope:        listScope,
+		GroupPattern: listGroup,
+		Direct:       listDirect,
+		Managed:      listManaged,
+		Profile:      listProfile,
+	})
+	if err != nil {
+		return err
+	}
+
+	result := &listResult{
+		Pom:          project.PomLocation,
+		Profile:      listProfile,
+		Dependencies: make([]declaredDependencyView, len(declared)),
+	}
+	for i, d := range declared {
+		result.Dependencies[i] = newDeclaredDependencyView(d)
+	}
+
+	return printer.Print(result)
+}
+
+// loadPom reads a pom.xml that is not edited, such as a parent or an imported BOM.
+func loadPom(path string) (domain.PomRepository, error) {
+	repository := xml.NewPomRepository()
+	if err := repository.Load(path); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+// declaredDependencyView is the stable machine-readable representation of a declared dependency.
+type declaredDependencyView struct {
+	GroupID         string   `json:"groupId" yaml:"groupId"`
+	ArtifactID      string   `json:"artifactId" yaml:"artifactId"`
+	Scope           string   `json:"scope" yaml:"scope"`
+	Managed         bool     `json:"managed" yaml:"managed"`
+	Version         string   `json:"version,omitempty" yaml:"version,omitempty"`
+	ResolvedVersion string   `json:"resolvedVersion,omitempty" yaml:"resolvedVersion,omitempty"`
+	VersionSource   string   `json:"versionSource" yaml:"versionSource"`
+	VersionOrigin   string   `json:"versionOrigin,omitempty" yaml:"versionOrigin,omitempty"`
+	Type            string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Classifier      string   `json:"classifier,omitempty" yaml:"classifier,omitempty"`
+	Optional        bool     `json:"optional,omitempty" yaml:"optional,omitempty"`
+	Exclusions      []string `json:"exclusions,omitempty" yaml:"exclusions,omitempty"`
+	Problem         string   `json:"problem,omitempty" yaml:"problem,omitempty"`
+
+	// name labels the dependency in the text table: groupId:artifactId, or its
+	// Key when it has a type or classifier
+	name string
+}
+
+// newDeclaredDependencyView converts a declared dependency to its view.
+func newDeclaredDependencyView(d *domain.DeclaredDependency) declaredDependencyView {
+	view := declaredDependencyView{
+		GroupID:         d.Dependency.GroupID,
+		ArtifactID:      d.Dependency.ArtifactID,
+		Scope:           d.Dependency.Scope,
+		Managed:         d.Managed,
+		Version:         d.Dependency.Version,
+		ResolvedVersion: d.ResolvedVersion,
+		VersionSource:   string(d.VersionSource),
+		VersionOrigin:   d.VersionOrigin,
+		Problem:         d.Problem,
+		Classifier:      d.Dependency.Classifier,
+		Optional:        d.Dependency.Optional,
+		name:            d.Dependency.Coordinates(),
+	}
+	if d.Dependency.EffectiveType() != domain.DefaultDependencyType || d.Dependency.Classifier != "" {
+		view.Type = d.Dependency.EffectiveType()
+		view.name = d.Dependency.Key()
+	}
+	for _, exclusion := range d.Dependency.Exclusions {
+		view.Exclusions = append(view.Exclusions, exclusion.String())
+	}
+	return view
+}
+
+// listResult is the output of the list command.
+type listResult struct {
+	Pom          string                   `json:"pom" yaml:"pom"`
+	Profile      string                   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Dependencies []declaredDependencyView `json:"dependencies" yaml:"dependencies"`
+}
+
+// WriteText prints the dependencies as an aligned table, followed by a warning per invalid declaration.
+func (r *listResult) WriteText(w io.Writer) error {
+	if len(r.Dependencies) == 0 {
+		_, err := fmt.Fprintln(w, "No dependencies found")
+		return err
+	}
+
+	rows := make([][]string, len(r.Dependencies))
+	for i, d := range r.Dependencies {
+		kind := "direct"
+		if d.Managed {
+			kind = "managed"
+		}
+		source := d.VersionSource
+		if d.VersionOrigin != "" {
+			source += " (" + d.VersionOrigin + ")"
+		}
+		rows[i] = []string{d.name, d.Scope, kind, orDash(d.Version), orDash(d.ResolvedVersion), source}
+	}
+
+	if err := output.WriteTable(w, []string{"DEPENDENCY", "SCOPE", "KIND", "VERSION", "RESOLVED", "SOURCE"}, rows); err != nil {
+		return err
+	}
+
+	for _, d := range r.Dependencies {
+		if d.Problem == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "warning: %s\n", d.Problem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TSV returns one row per dependency.
+func (r *listResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Dependencies))
+	for i, d := range r.Dependencies {
+		rows[i] = []string{
+			d.GroupID, d.ArtifactID, d.Scope, fmt.Sprint(d.Managed),
+			d.Version, d.ResolvedVersion, d.VersionSource, d.VersionOrigin, d.Problem,
+			d.Type, d.Classifier,
+		}
+	}
+	return []string{"groupId", "artifactId", "scope", "managed", "version", "resolvedVersion", "versionSource", "versionOrigin", "problem", "type", "classifier"}, rows
+}
+
+// orDash returns s, or "-" when it is empty, for table cells.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/diff"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+var (
+	// module flags for new-module command
+	newModuleArtifactID string
+	newModulePackaging  string
+	newModuleConvert    bool
+)
+
+// newModuleCmd represents the new-module command
+var newModuleCmd = &cobra.Command{
+	Use:   "new-module <directory>",
+	Short: "Add a module to a multi-module project",
+	Long: `Create a module in the given directory and register it in the <modules> of the
+enclosing project, the aggregator.
+
+The module's pom.xml has the aggregator as <parent>, inheriting its groupId and
+version, with a <relativePath> when the aggregator is not in the directory
+above. The directory is relative to the working directory and must be inside the
+aggregator, e.g. "core" or "services/orders"; the artifactId defaults to its
+last element.
+
+An aggregator needs pom packaging. --convert changes the packaging of a jar (or
+other) project to pom; sources under its src directory are no longer built
+afterwards and should be moved into a module.`,
+	Example: `  mvnx new-module core
+  mvnx new-module services/orders --artifact-id orders-service
+  mvnx new-module web --packaging war --convert`,
+	Args: exactArgs(1),
+	RunE: runNewModule,
+}
+
+func init() {
+	newModuleCmd.Flags().StringVar(&newModuleArtifactID, "artifact-id", "", "artifactId of the module (default the directory name)")
+	newModuleCmd.Flags().StringVar(&newModulePackaging, "packaging", domain.DefaultPackaging, "packaging of the module, e.g. jar, war or pom")
+	newModuleCmd.Flags().BoolVar(&newModuleConvert, "convert", false, "change the packaging of the aggregator to pom if needed")
+}
+
+func runNewModule(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// The directory is given relative to the working directory, but listed relative to the aggregator
+	dir, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", args[0], err)
+	}
+	rel, err := filepath.Rel(project.Path, dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", args[0], err)
+	}
+
+	spec, err := domain.NewModuleSpec(rel, newModuleArtifactID, newModulePackaging)
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewNewModuleService(pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	planned, err := service.Plan(project.PomLocation, spec, newModuleConvert)
+	if err != nil {
+		return err
+	}
+	if planned.OrphanedSources {
+		fmt.Fprintf(os.Stderr, "Warning: %s has a src directory, which is no longer built with pom packaging; move it into a module\n", project.Path)
+	}
+
+	if !dryRun {
+		if err := service.Create(planned); err != nil {
+			return err
+		}
+		change.Record("new-module " + spec.Path)
+	}
+
+	result := &newModuleResult{
+		Pom:           project.PomLocation,
+		Module:        spec.Path,
+		GroupID:       spec.Parent.GroupID,
+		ArtifactID:    spec.ArtifactID,
+		Packaging:     spec.Packaging,
+		ModulePom:     filepath.Join(planned.Dir, "pom.xml"),
+		Directories:   []string{},
+		ConvertedFrom: planned.ConvertedFrom,
+		DryRun:        dryRun,
+	}
+	if spec.HasSources() {
+		result.Directories = fs.ProjectDirectories
+	}
+
+	pomDiff, err := change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+	diffs := []string{pomDiff}
+	if dryRun || showDiff {
+		for _, file := range planned.Files {
+			name := diffName(filepath.Join(planned.Dir, filepath.FromSlash(file.Path)))
+			diffs = append(diffs, diff.Unified("/dev/null", "b/"+name, "", string(file.Content)))
+		}
+	}
+	result.Diff = strings.Join(diffs, "")
+
+	return printer.Print(result)
+}
+
+// newModuleResult is the output of the new-module command.
+type newModuleResult struct {
+	// Pom is the aggregator pom.xml
+	Pom string `json:"pom" yaml:"pom"`
+
+	// Module is the module directory as listed in <modules>
+	Module     string `json:"module" yaml:"module"`
+	GroupID    string `json:"groupId" yaml:"groupId"`
+	ArtifactID string `json:"artifactId" yaml:"artifactId"`
+	Packaging  string `json:"packaging" yaml:"packaging"`
+	ModulePom  string `json:"modulePom" yaml:"modulePom"`
+
+	// Directories are the source directories created in the module
+	Directories []string `json:"directories" yaml:"directories"`
+
+	// ConvertedFrom is the former packaging of the aggregator, if --convert changed it
+	ConvertedFrom string `json:"convertedFrom,omitempty" yaml:"convertedFrom,omitempty"`
+
+	DryRun bool   `json:"dryRun" yaml:"dryRun"`
+	Diff   string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints what was created and changed, followed by the diff, if any.
+func (r *newModuleResult) WriteText(w io.Writer) error {
+	modulePom := path.Join(r.Module, "pom.xml")
+	lines := []string{
+		fmt.Sprintf("✓ Created module %s (%s:%s)", r.Module, r.GroupID, r.ArtifactID),
+		"  Created " + modulePom,
+	}
+	verb, registered := "  Created ", "  Added"
+	if r.DryRun {
+		lines = []string{
+			fmt.Sprintf("Would create module %s (%s:%s)", r.Module, r.GroupID, r.ArtifactID),
+			"  Would create " + modulePom,
+		}
+		verb, registered = "  Would create ", "  Would add"
+	}
+	for _, dir := range r.Directories {
+		if strings.HasSuffix(dir, "/java") {
+			lines = append(lines, verb+path.Join(r.Module, dir))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("%s %s to <modules>", registered, r.Module))
+	if r.ConvertedFrom != "" {
+		changed := "  Changed"
+		if r.DryRun {
+			changed = "  Would change"
+		}
+		lines = append(lines, fmt.Sprintf("%s packaging from %s to pom", changed, r.ConvertedFrom))
+	}
+
+	if _, err := fmt.Fprintln(w, strings.Join(lines, "\n")); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns a single row describing the module.
+func (r *newModuleResult) TSV() ([]string, [][]string) {
+	return []string{"module", "groupId", "artifactId", "packaging", "pom"},
+		[][]string{{r.Module, r.GroupID, r.ArtifactID, r.Packaging, r.ModulePom}}
+}
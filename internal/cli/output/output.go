@@ -0,0 +1,138 @@
+// Package output renders command results in human-readable or machine-readable formats.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies an output format.
+type Format string
+
+const (
+	// FormatText is the default human-readable format
+	FormatText Format = "text"
+
+	// FormatJSON renders results as indented JSON
+	FormatJSON Format = "json"
+
+	// FormatYAML renders results as YAML
+	FormatYAML Format = "yaml"
+
+	// FormatTSV renders results as tab-separated rows with a header line
+	FormatTSV Format = "tsv"
+
+	// FormatCSV renders the same rows as FormatTSV as RFC 4180 CSV
+	FormatCSV Format = "csv"
+)
+
+// Formats lists all supported formats in the order they are documented.
+var Formats = []Format{FormatText, FormatJSON, FormatYAML, FormatTSV, FormatCSV}
+
+// ParseFormat validates a format name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid output format: %s (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// Result is implemented by every value a command emits.
+// JSON and YAML are produced by marshalling the value itself, so results
+// declare their stable schema through json and yaml struct tags.
+type Result interface {
+	// WriteText writes the human-readable representation.
+	WriteText(w io.Writer) error
+
+	// TSV returns a header and the rows for tab-separated and CSV output.
+	TSV() (header []string, rows [][]string)
+}
+
+// Printer writes results to an io.Writer in a fixed format.
+type Printer struct {
+	format Format
+	out    io.Writer
+}
+
+// NewPrinter creates a new Printer.
+func NewPrinter(format Format, out io.Writer) *Printer {
+	return &Printer{
+		format: format,
+		out:    out,
+	}
+}
+
+// Format returns the format the printer renders.
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// IsText reports whether the printer renders human-readable text.
+func (p *Printer) IsText() bool {
+	return p.format == FormatText
+}
+
+// Print renders a result.
+func (p *Printer) Print(result Result) error {
+	switch p.format {
+	case FormatJSON:
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(result)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(p.out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTSV:
+		header, rows := result.TSV()
+		return writeTSV(p.out, header, rows)
+	case FormatCSV:
+		header, rows := result.TSV()
+		w := csv.NewWriter(p.out)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		return w.WriteAll(rows)
+	default:
+		return result.WriteText(p.out)
+	}
+}
+
+// writeTSV writes a header line followed by one line per row.
+// Tabs and newlines inside fields are replaced by spaces to keep the format line-oriented.
+func writeTSV(w io.Writer, header []string, rows [][]string) error {
+	if _, err := fmt.Fprintln(w, joinTSV(header)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, joinTSV(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinTSV joins fields with tabs after sanitizing them.
+func joinTSV(fields []string) string {
+	sanitized := make([]string, len(fields))
+	for i, field := range fields {
+		sanitized[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(field)
+	}
+	return strings.Join(sanitized, "\t")
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testResult struct {
+	Name  string   `json:"name" yaml:"name"`
+	Items []string `json:"items" yaml:"items"`
+}
+
+func (r *testResult) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s has %d items\n", r.Name, len(r.Items))
+	return err
+}
+
+func (r *testResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Items))
+	for i, item := range r.Items {
+		rows[i] = []string{r.Name, item}
+	}
+	return []string{"name", "item"}, rows
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats {
+		parsed, err := ParseFormat(string(f))
+		assert.NoError(t, err)
+		assert.Equal(t, f, parsed)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestPrinter_Print(t *testing.T) {
+	result := &testResult{Name: "demo", Items: []string{"a", "b\tc"}}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format:   FormatText,
+			expected: "demo has 2 items\n",
+		},
+		{
+			format:   FormatJSON,
+			expected: "{\n  \"name\": \"demo\",\n  \"items\": [\n    \"a\",\n    \"b\\tc\"\n  ]\n}\n",
+		},
+		{
+			format:   FormatYAML,
+			expected: "name: demo\nitems:\n  - a\n  - \"b\\tc\"\n",
+		},
+		{
+			format:   FormatTSV,
+			expected: "name\titem\ndemo\ta\ndemo\tb c\n",
+		},
+		{
+			format:   FormatCSV,
+			expected: "name,item\ndemo,a\ndemo,b\tc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, NewPrinter(tt.format, &buf).Print(result))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteTable(&buf, []string{"GROUP ID", "ARTIFACT ID"}, [][]string{
+		{"org.projectlombok", "lombok"},
+		{"junit", "junit"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "GROUP ID           ARTIFACT ID\n"+
+		"------------------------------\n"+
+		"org.projectlombok  lombok\n"+
+		"junit              junit\n", buf.String())
+}
@@ -0,0 +1,55 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTable writes rows as left-aligned columns under an underlined header.
+func WriteTable(w io.Writer, header []string, rows [][]string) error {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	total += 2 * (len(widths) - 1)
+
+	if _, err := fmt.Fprintln(w, formatRow(widths, header)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, strings.Repeat("-", total)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, formatRow(widths, row)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatRow pads every cell but the last to its column width.
+func formatRow(widths []int, row []string) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		if i < len(row)-1 && i < len(widths) {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		} else {
+			cells[i] = cell
+		}
+	}
+	return strings.Join(cells, "  ")
+}
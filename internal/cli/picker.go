@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// Key codes understood by the picker
+const (
+	keyCtrlC     = 3
+	keyEnter     = 13
+	keyNewline   = 10
+	keyEscape    = 27
+	keyBackspace = 127
+	keyCtrlH     = 8
+)
+
+// pickerPageSize is the maximum number of candidates shown at once
+const pickerPageSize = 10
+
+// isTerminal reports whether both stdin and stderr are attached to a terminal.
+// The picker draws on stderr so that stdout stays clean for command results.
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// fuzzyMatch reports whether all characters of pattern appear in text in order.
+// Matching is case-insensitive. An empty pattern matches everything.
+func fuzzyMatch(pattern, text string) bool {
+	remaining := []rune(strings.ToLower(pattern))
+
+	for _, r := range strings.ToLower(text) {
+		if len(remaining) == 0 {
+			break
+		}
+		if remaining[0] == r {
+			remaining = remaining[1:]
+		}
+	}
+
+	return len(remaining) == 0
+}
+
+// filterCandidates returns the candidates whose key fuzzy-matches the pattern, in their original order.
+func filterCandidates[T any](candidates []T, key func(T) string, pattern string) []T {
+	filtered := make([]T, 0, len(candidates))
+	for _, candidate := range candidates {
+		if fuzzyMatch(pattern, key(candidate)) {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// pickArtifact shows an arrow-key driven fuzzy picker and returns the chosen artifact.
+func pickArtifact(results []*domain.ArtifactSearchResult) (*domain.ArtifactSearchResult, error) {
+	return pick(&picker[*domain.ArtifactSearchResult]{
+		noun:       "artifact",
+		candidates: results,
+		key:        (*domain.ArtifactSearchResult).Coordinates,
+		label: func(result *domain.ArtifactSearchResult) string {
+			return fmt.Sprintf("%s (%s)", result.Coordinates(), result.LatestVersion)
+		},
+	})
+}
+
+// pickDependency shows an arrow-key driven fuzzy picker and returns the chosen dependency.
+func pickDependency(dependencies []*domain.Dependency) (*domain.Dependency, error) {
+	return pick(&picker[*domain.Dependency]{
+		noun:       "dependency",
+		candidates: dependencies,
+		key:        (*domain.Dependency).ID,
+		label:      (*domain.Dependency).String,
+	})
+}
+
+// pick runs a picker on the terminal and returns the chosen candidate.
+// Typing filters the list, up/down move the cursor, enter selects, esc or ctrl-c aborts.
+func pick[T any](p *picker[T]) (T, error) {
+	var zero T
+	fd := int(os.Stdin.Fd())
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return zero, fmt.Errorf("failed to enable raw terminal mode: %w", err)
+	}
+	defer term.Restore(fd, oldState) //nolint:errcheck
+
+	p.filtered = p.candidates
+	p.out = os.Stderr
+
+	return p.run(bufio.NewReader(os.Stdin))
+}
+
+// picker holds the state of an interactive selection.
+type picker[T any] struct {
+	// noun names the kind of candidate in prompts, e.g. "artifact"
+	noun string
+
+	candidates []T
+	filtered   []T
+
+	// key is the text matched against the filter; label is the text shown
+	key   func(T) string
+	label func(T) string
+
+	pattern string
+	cursor  int
+	out     io.Writer
+
+	// lines is the number of lines drawn by the last render
+	lines int
+}
+
+// run processes key presses until a selection is made or the picker is aborted.
+func (p *picker[T]) run(in *bufio.Reader) (T, error) {
+	var zero T
+	p.render()
+
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			p.clear()
+			return zero, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		switch b {
+		case keyCtrlC:
+			p.clear()
+			return zero, errSelectionCancelled
+		case keyEnter, keyNewline:
+			p.clear()
+			if len(p.filtered) == 0 {
+				return zero, fmt.Errorf("no %s matches %q", p.noun, p.pattern)
+			}
+			return p.filtered[p.cursor], nil
+		case keyBackspace, keyCtrlH:
+			if p.pattern != "" {
+				p.pattern = p.pattern[:len(p.pattern)-1]
+				p.refilter()
+			}
+		case keyEscape:
+			// Arrow keys arrive as ESC [ A / ESC [ B; a lone ESC aborts
+			if in.Buffered() == 0 {
+				p.clear()
+				return zero, errSelectionCancelled
+			}
+			next, _ := in.ReadByte()
+			if next != '[' {
+				continue
+			}
+			arrow, _ := in.ReadByte()
+			switch arrow {
+			case 'A':
+				p.move(-1)
+			case 'B':
+				p.move(1)
+			}
+		default:
+			if b < 128 && unicode.IsPrint(rune(b)) {
+				p.pattern += string(b)
+				p.refilter()
+			}
+		}
+
+		p.render()
+	}
+}
+
+// move moves the cursor by delta, wrapping around the filtered list.
+func (p *picker[T]) move(delta int) {
+	if len(p.filtered) == 0 {
+		return
+	}
+	p.cursor = (p.cursor + delta + len(p.filtered)) % len(p.filtered)
+}
+
+// refilter recomputes the filtered list after the pattern changed.
+func (p *picker[T]) refilter() {
+	p.filtered = filterCandidates(p.candidates, p.key, p.pattern)
+	p.cursor = 0
+}
+
+// clear erases everything drawn by the previous render.
+func (p *picker[T]) clear() {
+	for i := 0; i < p.lines; i++ {
+		fmt.Fprint(p.out, "\x1b[1A\x1b[2K")
+	}
+	fmt.Fprint(p.out, "\r\x1b[2K")
+	p.lines = 0
+}
+
+// render redraws the prompt and the visible part of the filtered list.
+// Raw mode disables output post-processing, so every line ends with \r\n.
+func (p *picker[T]) render() {
+	p.clear()
+
+	start := 0
+	if p.cursor >= pickerPageSize {
+		start = p.cursor - pickerPageSize + 1
+	}
+	end := start + pickerPageSize
+	if end > len(p.filtered) {
+		end = len(p.filtered)
+	}
+
+	for i := start; i < end; i++ {
+		marker := " "
+		if i == p.cursor {
+			marker = ">"
+		}
+		fmt.Fprintf(p.out, "%s %s\r\n", marker, p.label(p.filtered[i]))
+	}
+	if len(p.filtered) == 0 {
+		fmt.Fprint(p.out, "  no matches\r\n")
+		end = start + 1
+	}
+
+	p.lines = end - start
+	fmt.Fprintf(p.out, "Select %s (type to filter, ↑/↓ to move, enter to confirm): %s", p.noun, p.pattern)
+}
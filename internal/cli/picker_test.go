@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    bool
+	}{
+		{
+			name:    "empty pattern matches everything",
+			pattern: "",
+			text:    "org.projectlombok:lombok",
+			want:    true,
+		},
+		{
+			name:    "subsequence matches",
+			pattern: "plmb",
+			text:    "org.projectlombok:lombok",
+			want:    true,
+		},
+		{
+			name:    "case insensitive",
+			pattern: "LOMBOK",
+			text:    "org.projectlombok:lombok",
+			want:    true,
+		},
+		{
+			name:    "out of order does not match",
+			pattern: "kobmol",
+			text:    "lombok",
+			want:    false,
+		},
+		{
+			name:    "missing character does not match",
+			pattern: "lombokz",
+			text:    "org.projectlombok:lombok",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fuzzyMatch(tt.pattern, tt.text))
+		})
+	}
+}
+
+func TestFilterCandidates(t *testing.T) {
+	results := []*domain.ArtifactSearchResult{
+		domain.NewArtifactSearchResult("org.postgresql", "postgresql", "42.7.3", 100),
+		domain.NewArtifactSearchResult("org.testcontainers", "postgresql", "1.19.7", 90),
+		domain.NewArtifactSearchResult("io.r2dbc", "r2dbc-postgresql", "0.8.13", 80),
+	}
+
+	key := (*domain.ArtifactSearchResult).Coordinates
+
+	filtered := filterCandidates(results, key, "testcont")
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "org.testcontainers:postgresql", filtered[0].Coordinates())
+	assert.Len(t, filterCandidates(results, key, "postgres"), 3)
+}
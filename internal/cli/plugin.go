@@ -0,0 +1,477 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+)
+
+// pluginManaged flag selects <pluginManagement> for plugin add and remove
+var pluginManaged bool
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage the build plugins of the project",
+	Long: `Manage the plugins declared in <build><plugins> and <build><pluginManagement>.
+
+Plugins are looked up on Maven Central like dependencies. A bare artifactId
+(e.g. "maven-surefire-plugin") is first tried under org.apache.maven.plugins,
+the groupId Maven assumes for plugins that declare none.
+
+Editing a plugin only touches its <version>; <configuration>, <executions> and
+other children are kept as they are.`,
+}
+
+// pluginAddCmd represents the plugin add command
+var pluginAddCmd = &cobra.Command{
+	Use:   "add <query>",
+	Short: "Add a plugin or update its version",
+	Long: `Add a plugin to <build><plugins>, or to <build><pluginManagement> with --managed.
+
+Query is an artifactId, a groupId:artifactId, or groupId:artifactId:version.
+Without a version the latest stable version is looked up on Maven Central.
+If the plugin is already declared in that section, its version is updated.`,
+	Example: `  mvnx plugin add maven-surefire-plugin
+  mvnx plugin add org.codehaus.mojo:versions-maven-plugin:2.16.2
+  mvnx plugin add maven-enforcer-plugin --managed`,
+	Args: exactArgs(1),
+	RunE: runPluginAdd,
+}
+
+// pluginRemoveCmd represents the plugin remove command
+var pluginRemoveCmd = &cobra.Command{
+	Use:   "remove <plugin>...",
+	Short: "Remove plugins from the project",
+	Long: `Remove plugins from <build><plugins>, or from <build><pluginManagement> with --managed.
+
+Each argument is an artifactId, a groupId:artifactId, or a glob pattern such as
+"org.codehaus.mojo:*".`,
+	Args: usageArgs(cobra.MinimumNArgs(1)),
+	RunE: runPluginRemove,
+}
+
+// pluginListCmd represents the plugin list command
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins declared in the project",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runPluginList,
+}
+
+// pluginUpgradeCmd represents the plugin upgrade command
+var pluginUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [plugin...]",
+	Short: "Upgrade plugins to their latest stable version",
+	Long: `Upgrade plugins in both <build><plugins> and <build><pluginManagement> to the
+latest stable version on Maven Central.
+
+Without arguments every plugin is upgraded; otherwise only plugins matching an
+artifactId, a groupId:artifactId or a glob pattern. Plugins without a version,
+or whose version is set by a ${property}, are reported and left unchanged.`,
+	Example: `  mvnx plugin upgrade
+  mvnx plugin upgrade maven-compiler-plugin maven-surefire-plugin`,
+	RunE: runPluginUpgrade,
+}
+
+func init() {
+	pluginAddCmd.Flags().BoolVar(&pluginManaged, "managed", false, "add to <pluginManagement> instead of <plugins>")
+	pluginAddCmd.Flags().BoolVarP(&pickFirst, "yes", "y", false, "pick the top search result without prompting")
+	pluginAddCmd.Flags().BoolVar(&pickFirst, "first", false, "alias for --yes")
+	pluginAddCmd.Flags().IntVar(&pickIndex, "pick", 0, "pick the n-th search result (1-based) without prompting")
+	pluginAddCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt; fail if the query is ambiguous")
+
+	pluginRemoveCmd.Flags().BoolVar(&pluginManaged, "managed", false, "remove from <pluginManagement> instead of <plugins>")
+
+	pluginCmd.AddCommand(pluginAddCmd)
+	pluginCmd.AddCommand(pluginRemoveCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginUpgradeCmd)
+}
+
+func runPluginAdd(cmd *cobra.Command, args []string) error {
+	query := args[0]
+
+	if pickFirst && pickIndex != 0 {
+		return usageErrorf("--yes/--first and --pick cannot be used together")
+	}
+	if pickIndex < 0 {
+		return usageErrorf("invalid --pick value: %d (must be 1 or greater)", pickIndex)
+	}
+
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewPluginService(maven.NewResolver(), pomRepo, newLocker())
+
+	// Search for plugins
+	logf("Searching for: %s\n", query)
+
+	searchResult, err := service.Search(query)
+	if err != nil {
+		return err
+	}
+
+	selectedArtifact, err := selectArtifact(query, searchResult)
+	if err != nil {
+		return err
+	}
+
+	plugin, err := domain.NewPlugin(selectedArtifact.GroupID, selectedArtifact.ArtifactID, selectedArtifact.LatestVersion)
+	if err != nil {
+		return err
+	}
+	plugin.Managed = pluginManaged
+
+	// Lock the project and load pom.xml only now, so the lock is not held
+	// during the search or while the user picks an artifact
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	if err := service.Add(plugin); err != nil {
+		return err
+	}
+	change.Record("plugin add " + plugin.String())
+
+	unified, err := change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(&pluginAddResult{
+		Pom:    project.PomLocation,
+		Plugin: newPluginView(plugin),
+		DryRun: dryRun,
+		Diff:   unified,
+	})
+}
+
+func runPluginRemove(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewPluginService(maven.NewResolver(), pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	// Resolve every argument before touching the pom.xml
+	var plugins []*domain.Plugin
+	for _, target := range args {
+		matched, err := service.Match(target, pluginManaged)
+		if err != nil {
+			return err
+		}
+		plugins = append(plugins, matched...)
+	}
+
+	if err := service.Remove(plugins); err != nil {
+		return err
+	}
+
+	result := &pluginRemoveResult{
+		Pom:     project.PomLocation,
+		Removed: make([]pluginView, len(plugins)),
+		DryRun:  dryRun,
+	}
+	coordinates := make([]string, len(plugins))
+	for i, plugin := range plugins {
+		result.Removed[i] = newPluginView(plugin)
+		coordinates[i] = plugin.Coordinates()
+	}
+	change.Record("plugin remove " + strings.Join(coordinates, ", "))
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Reading needs neither the resolver nor the project lock
+	service := app.NewPluginService(nil, xml.NewPomRepository(), nil)
+	if err := service.ReadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+
+	plugins, err := service.List()
+	if err != nil {
+		return err
+	}
+
+	result := &pluginListResult{
+		Pom:     project.PomLocation,
+		Plugins: make([]pluginView, len(plugins)),
+	}
+	for i, plugin := range plugins {
+		result.Plugins[i] = newPluginView(plugin)
+	}
+
+	return printer.Print(result)
+}
+
+func runPluginUpgrade(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewPluginService(maven.NewResolver(), pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	upgrades, err := service.Upgrade(args)
+	if err != nil {
+		return err
+	}
+
+	result := &pluginUpgradeResult{
+		Pom:      project.PomLocation,
+		Upgrades: make([]pluginUpgradeView, len(upgrades)),
+		DryRun:   dryRun,
+	}
+	var upgraded []string
+	for i, u := range upgrades {
+		result.Upgrades[i] = pluginUpgradeView{
+			pluginView: newPluginView(u.Plugin),
+			From:       u.From,
+			To:         u.To,
+			Status:     u.Status,
+			Reason:     u.Reason,
+		}
+		if u.Status == app.PluginUpgraded {
+			upgraded = append(upgraded, u.Plugin.Coordinates()+":"+u.To)
+		}
+	}
+	if len(upgraded) > 0 {
+		change.Record("plugin upgrade " + strings.Join(upgraded, ", "))
+	}
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// pluginView is the stable machine-readable representation of a plugin.
+// Version is omitted for plugins whose version is managed elsewhere.
+type pluginView struct {
+	GroupID    string `json:"groupId" yaml:"groupId"`
+	ArtifactID string `json:"artifactId" yaml:"artifactId"`
+	Version    string `json:"version,omitempty" yaml:"version,omitempty"`
+	Managed    bool   `json:"managed" yaml:"managed"`
+
+	// id is the text form of the coordinates, see domain.Plugin.String
+	id string
+}
+
+// newPluginView converts a plugin to its view.
+func newPluginView(p *domain.Plugin) pluginView {
+	return pluginView{
+		GroupID:    p.GroupID,
+		ArtifactID: p.ArtifactID,
+		Version:    p.Version,
+		Managed:    p.Managed,
+		id:         p.String(),
+	}
+}
+
+// pluginAddResult is the output of the plugin add command.
+type pluginAddResult struct {
+	Pom    string     `json:"pom" yaml:"pom"`
+	Plugin pluginView `json:"plugin" yaml:"plugin"`
+	DryRun bool       `json:"dryRun" yaml:"dryRun"`
+	Diff   string     `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints a confirmation line followed by the diff, if any.
+func (r *pluginAddResult) WriteText(w io.Writer) error {
+	verb := "✓ Added plugin"
+	if r.DryRun {
+		verb = "Would add plugin"
+	}
+	if _, err := fmt.Fprintf(w, "%s %s\n", verb, r.Plugin.id); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns the added plugin as a single row.
+func (r *pluginAddResult) TSV() ([]string, [][]string) {
+	p := r.Plugin
+	return []string{"groupId", "artifactId", "version", "managed", "pom"},
+		[][]string{{p.GroupID, p.ArtifactID, p.Version, fmt.Sprint(p.Managed), r.Pom}}
+}
+
+// pluginRemoveResult is the output of the plugin remove command.
+type pluginRemoveResult struct {
+	Pom     string       `json:"pom" yaml:"pom"`
+	Removed []pluginView `json:"removed" yaml:"removed"`
+	DryRun  bool         `json:"dryRun" yaml:"dryRun"`
+	Diff    string       `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints a confirmation line per plugin followed by the diff, if any.
+func (r *pluginRemoveResult) WriteText(w io.Writer) error {
+	verb := "✓ Removed plugin"
+	if r.DryRun {
+		verb = "Would remove plugin"
+	}
+	for _, p := range r.Removed {
+		if _, err := fmt.Fprintf(w, "%s %s\n", verb, p.id); err != nil {
+			return err
+		}
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per removed plugin.
+func (r *pluginRemoveResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Removed))
+	for i, p := range r.Removed {
+		rows[i] = []string{p.GroupID, p.ArtifactID, p.Version, fmt.Sprint(p.Managed), r.Pom}
+	}
+	return []string{"groupId", "artifactId", "version", "managed", "pom"}, rows
+}
+
+// pluginListResult is the output of the plugin list command.
+type pluginListResult struct {
+	Pom     string       `json:"pom" yaml:"pom"`
+	Plugins []pluginView `json:"plugins" yaml:"plugins"`
+}
+
+// WriteText prints the plugins as an aligned table.
+func (r *pluginListResult) WriteText(w io.Writer) error {
+	if len(r.Plugins) == 0 {
+		_, err := fmt.Fprintln(w, "No plugins found")
+		return err
+	}
+
+	rows := make([][]string, len(r.Plugins))
+	for i, p := range r.Plugins {
+		rows[i] = []string{p.GroupID + ":" + p.ArtifactID, pluginKind(p.Managed), orDash(p.Version)}
+	}
+	return output.WriteTable(w, []string{"PLUGIN", "KIND", "VERSION"}, rows)
+}
+
+// TSV returns one row per plugin.
+func (r *pluginListResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Plugins))
+	for i, p := range r.Plugins {
+		rows[i] = []string{p.GroupID, p.ArtifactID, p.Version, fmt.Sprint(p.Managed)}
+	}
+	return []string{"groupId", "artifactId", "version", "managed"}, rows
+}
+
+// pluginUpgradeView is the stable machine-readable representation of a plugin upgrade.
+type pluginUpgradeView struct {
+	pluginView `yaml:",inline"`
+	From       string `json:"from,omitempty" yaml:"from,omitempty"`
+	To         string `json:"to,omitempty" yaml:"to,omitempty"`
+	Status     string `json:"status" yaml:"status"`
+	Reason     string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// pluginUpgradeResult is the output of the plugin upgrade command.
+type pluginUpgradeResult struct {
+	Pom      string              `json:"pom" yaml:"pom"`
+	Upgrades []pluginUpgradeView `json:"upgrades" yaml:"upgrades"`
+	DryRun   bool                `json:"dryRun" yaml:"dryRun"`
+	Diff     string              `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints the upgrades as an aligned table followed by the diff, if any.
+func (r *pluginUpgradeResult) WriteText(w io.Writer) error {
+	if len(r.Upgrades) == 0 {
+		_, err := fmt.Fprintln(w, "No plugins found")
+		return err
+	}
+
+	rows := make([][]string, len(r.Upgrades))
+	for i, u := range r.Upgrades {
+		status := u.Status
+		if u.Status == app.PluginUpgraded && r.DryRun {
+			status = "would upgrade"
+		}
+		if u.Reason != "" {
+			status += " (" + u.Reason + ")"
+		}
+		rows[i] = []string{u.GroupID + ":" + u.ArtifactID, pluginKind(u.Managed), orDash(u.From), orDash(u.To), status}
+	}
+	if err := output.WriteTable(w, []string{"PLUGIN", "KIND", "FROM", "TO", "STATUS"}, rows); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per plugin.
+func (r *pluginUpgradeResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Upgrades))
+	for i, u := range r.Upgrades {
+		rows[i] = []string{u.GroupID, u.ArtifactID, fmt.Sprint(u.Managed), u.From, u.To, u.Status, u.Reason}
+	}
+	return []string{"groupId", "artifactId", "managed", "from", "to", "status", "reason"}, rows
+}
+
+// pluginKind labels a plugin in text tables.
+func pluginKind(managed bool) string {
+	if managed {
+		return "managed"
+	}
+	return "build"
+}
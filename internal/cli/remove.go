@@ -2,57 +2,175 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/app"
-	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/xml"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
 )
 
+// profile flag for remove command
+var removeProfile string
+
 // removeCmd represents the remove command
 var removeCmd = &cobra.Command{
-	Use:   "remove <artifactId>",
-	Short: "Remove a dependency from the project",
-	Long:  `Remove a dependency from the project's pom.xml by its artifactId.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runRemove,
+	Use:   "remove <dependency>...",
+	Short: "Remove dependencies from the project",
+	Long: `Remove dependencies from the project's pom.xml.
+
+Each argument is an artifactId (e.g. "lombok"), a groupId:artifactId
+(e.g. "com.fasterxml.jackson.core:jackson-core"), or a glob pattern matching
+several dependencies (e.g. "org.junit.*:*"). Dependencies declared with a type
+or classifier are selected with groupId:artifactId[:type[:classifier]]:version,
+where any part may be a pattern (e.g. "io.netty:netty-transport-native-epoll:jar:linux-x86_64:*").
+
+When an argument without wildcards matches several dependencies, such as an
+artifactId that exists under several groupIds, mvnx asks which one to remove.
+Without a terminal the command fails and lists the candidates instead.
+
+--profile removes dependencies from the <dependencies> of a <profile> instead of
+those of the project itself. The profile is kept even when it becomes empty.`,
+	Args: usageArgs(cobra.MinimumNArgs(1)),
+	RunE: runRemove,
 }
 
-func runRemove(cmd *cobra.Command, args []string) error {
-	artifactID := args[0]
+func init() {
+	removeCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt; fail if an artifactId is ambiguous")
+	removeCmd.Flags().StringVar(&removeProfile, "profile", "", "remove dependencies from this profile")
+}
 
+func runRemove(cmd *cobra.Command, args []string) error {
 	// Find project
-	cwd, err := os.Getwd()
+	project, err := findProject()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
-	projectFinder := app.NewProjectFinder()
-	project, err := projectFinder.FindProject(cwd)
-	if err != nil {
-		return fmt.Errorf("no Maven project found")
-	}
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewRemoveDependencyService(pomRepo, newLocker())
 
-	if verbose {
-		fmt.Printf("Found pom.xml at: %s\n", project.PomLocation)
+	// Resolve every argument before touching the pom.xml, without the lock,
+	// so it is not held while the user picks a dependency
+	if err := service.ReadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
 	}
+	type selection struct {
+		match        *app.DependencyMatch
+		dependencies []*domain.Dependency
+	}
+	var selections []selection
+	for _, target := range args {
+		match, err := service.Match(target, removeProfile)
+		if err != nil {
+			return err
+		}
 
-	// Create service
-	pomRepo := xml.NewPomRepository()
-	service := app.NewRemoveDependencyService(pomRepo)
+		selected, err := selectDependencies(match)
+		if err != nil {
+			return err
+		}
+		selections = append(selections, selection{match: match, dependencies: selected})
+	}
 
-	// Load pom.xml
+	// Lock the project, reload pom.xml and check the selection still holds
 	if err := service.LoadPom(project.PomLocation); err != nil {
 		return fmt.Errorf("failed to load pom.xml: %w", err)
 	}
+	defer closeService(service)
 
-	// Remove dependency
-	if err := service.Remove(artifactID); err != nil {
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("✓ Removed %s\n", artifactID)
+	var dependencies []*domain.Dependency
+	for _, sel := range selections {
+		selected, err := service.Recheck(sel.match, removeProfile, sel.dependencies)
+		if err != nil {
+			return err
+		}
+		dependencies = append(dependencies, selected...)
+	}
 
-	return nil
+	// Remove dependencies
+	dependencies, err = service.Remove(dependencies)
+	if err != nil {
+		return err
+	}
+
+	result := &removeResult{
+		Pom:     project.PomLocation,
+		Removed: make([]dependencyView, len(dependencies)),
+		DryRun:  dryRun,
+	}
+	coordinates := make([]string, len(dependencies))
+	for i, dep := range dependencies {
+		result.Removed[i] = newDependencyView(dep)
+		coordinates[i] = dep.ID()
+	}
+	change.Record("remove " + strings.Join(coordinates, ", ") + profileSuffix(" from", removeProfile))
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// selectDependencies returns the dependencies a matched argument refers to.
+// An ambiguous artifactId is resolved interactively, or rejected when no terminal is available.
+func selectDependencies(match *app.DependencyMatch) ([]*domain.Dependency, error) {
+	if !match.NeedsSelection {
+		return match.Dependencies, nil
+	}
+
+	if nonInteractive || !isTerminal() {
+		candidates := make([]string, len(match.Dependencies))
+		for i, dep := range match.Dependencies {
+			candidates[i] = dep.ID()
+		}
+		return nil, fmt.Errorf("%w\nrerun with one of the candidates above",
+			&domain.AmbiguousError{Query: match.Target, Candidates: candidates})
+	}
+
+	dep, err := pickDependency(match.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	return []*domain.Dependency{dep}, nil
+}
+
+// removeResult is the output of the remove command.
+type removeResult struct {
+	Pom     string           `json:"pom" yaml:"pom"`
+	Removed []dependencyView `json:"removed" yaml:"removed"`
+	DryRun  bool             `json:"dryRun" yaml:"dryRun"`
+	Diff    string           `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints a confirmation line per dependency followed by the diff, if any.
+func (r *removeResult) WriteText(w io.Writer) error {
+	verb := "✓ Removed"
+	if r.DryRun {
+		verb = "Would remove"
+	}
+	for _, d := range r.Removed {
+		if _, err := fmt.Fprintf(w, "%s %s%s\n", verb, d.id, profileSuffix(" from", d.Profile)); err != nil {
+			return err
+		}
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per removed dependency.
+func (r *removeResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Removed))
+	for i, d := range r.Removed {
+		rows[i] = []string{d.GroupID, d.ArtifactID, d.Version, d.Scope, r.Pom}
+	}
+	return []string{"groupId", "artifactId", "version", "scope", "pom"}, rows
 }
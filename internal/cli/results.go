@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// artifactView is the stable machine-readable representation of a search result.
+type artifactView struct {
+	GroupID       string `json:"groupId" yaml:"groupId"`
+	ArtifactID    string `json:"artifactId" yaml:"artifactId"`
+	LatestVersion string `json:"latestVersion" yaml:"latestVersion"`
+}
+
+// newArtifactView converts a search result to its view.
+func newArtifactView(a *domain.ArtifactSearchResult) artifactView {
+	return artifactView{
+		GroupID:       a.GroupID,
+		ArtifactID:    a.ArtifactID,
+		LatestVersion: a.LatestVersion,
+	}
+}
+
+// dependencyView is the stable machine-readable representation of a dependency.
+// Version is omitted for dependencies whose version is managed by a parent or BOM;
+// type, classifier, optional, systemPath and profile are omitted when they have their default values.
+type dependencyView struct {
+	GroupID    string `json:"groupId" yaml:"groupId"`
+	ArtifactID string `json:"artifactId" yaml:"artifactId"`
+	Version    string `json:"version,omitempty" yaml:"version,omitempty"`
+	Scope      string `json:"scope" yaml:"scope"`
+	Type       string `json:"type,omitempty" yaml:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty" yaml:"classifier,omitempty"`
+	Optional   bool   `json:"optional,omitempty" yaml:"optional,omitempty"`
+	SystemPath string `json:"systemPath,omitempty" yaml:"systemPath,omitempty"`
+	Profile    string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// id is the text form of the coordinates, see domain.Dependency.VersionedID
+	id string
+}
+
+// newDependencyView converts a dependency to its view.
+func newDependencyView(d *domain.Dependency) dependencyView {
+	view := dependencyView{
+		GroupID:    d.GroupID,
+		ArtifactID: d.ArtifactID,
+		Version:    d.Version,
+		Scope:      d.Scope,
+		Classifier: d.Classifier,
+		Optional:   d.Optional,
+		SystemPath: d.SystemPath,
+		Profile:    d.Profile,
+		id:         d.VersionedID(),
+	}
+	if d.EffectiveType() != domain.DefaultDependencyType {
+		view.Type = d.Type
+	}
+	return view
+}
+
+// profileView is the stable machine-readable representation of a profile.
+// Activation conditions are written in the syntax of domain.ParseProfileActivation.
+type profileView struct {
+	ID         string   `json:"id" yaml:"id"`
+	Activation []string `json:"activation" yaml:"activation"`
+}
+
+// newProfileView converts a profile to its view.
+func newProfileView(p *domain.Profile) *profileView {
+	view := &profileView{ID: p.ID, Activation: []string{}}
+	for _, condition := range p.Activation {
+		view.Activation = append(view.Activation, condition.String())
+	}
+	return view
+}
+
+// profileSuffix describes the profile of a dependency in a text confirmation,
+// e.g. " to profile it", or returns "" for the project's own dependencies.
+func profileSuffix(preposition, profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return preposition + " profile " + profile
+}
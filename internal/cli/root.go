@@ -1,13 +1,32 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
 )
 
 var (
 	// Verbose flag for detailed output
 	verbose bool
 
+	// dryRun flag keeps pom.xml changes in memory and prints them as a diff
+	dryRun bool
+
+	// showDiff flag prints a diff of the pom.xml changes after writing them
+	showDiff bool
+
+	// outputFormat flag selects how command results are rendered
+	outputFormat string
+
+	// printer renders command results in the selected format
+	printer = output.NewPrinter(output.FormatText, os.Stdout)
+
 	// Version information
 	version = "dev"
 	commit  = "none"
@@ -28,6 +47,17 @@ var rootCmd = &cobra.Command{
 	Long: `mvnx is a CLI tool that enhances the Maven developer experience.
 It provides a modern, intelligent layer on top of standard Maven projects.`,
 	Version: version,
+	// Errors are reported by ReportError so they honor --output and map to exit codes
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return &usageError{message: err.Error()}
+		}
+		printer = output.NewPrinter(format, os.Stdout)
+		return nil
+	},
 }
 
 // Execute runs the root command
@@ -37,6 +67,13 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show the pom.xml changes as a diff without writing them")
+	rootCmd.PersistentFlags().BoolVar(&showDiff, "diff", false, "show a diff of the pom.xml changes after writing them")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(output.FormatText), "output format (text, json, yaml, tsv, csv)")
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{message: err.Error()}
+	})
 
 	// Customize version template
 	rootCmd.SetVersionTemplate(`{{printf "mvnx version %s\n" .Version}}{{printf "commit: %s\n" (index .Annotations "commit")}}{{printf "built: %s\n" (index .Annotations "date")}}`)
@@ -47,7 +84,61 @@ func init() {
 
 	// Add subcommands
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(newModuleCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(excludeCmd)
+	rootCmd.AddCommand(pluginCmd)
+	rootCmd.AddCommand(javaCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(dedupeCmd)
+	rootCmd.AddCommand(convergeCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(licensesCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+}
+
+// logf prints verbose progress messages to stderr, keeping stdout reserved for results.
+func logf(format string, args ...any) {
+	if verbose {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// findProject locates the Maven project containing the working directory.
+func findProject() (*domain.Project, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectFinder := app.NewProjectFinder()
+	project, err := projectFinder.FindProject(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("%w; run 'mvnx init' to create one", err)
+	}
+
+	logf("Found pom.xml at: %s\n", project.PomLocation)
+
+	return project, nil
+}
+
+// usageArgs wraps a cobra argument validator so its failures are reported as usage errors.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return &usageError{message: err.Error()}
+		}
+		return nil
+	}
+}
+
+// exactArgs is cobra.ExactArgs with failures reported as usage errors.
+func exactArgs(n int) cobra.PositionalArgs {
+	return usageArgs(cobra.ExactArgs(n))
 }
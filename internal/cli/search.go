@@ -2,11 +2,12 @@ package cli
 
 import (
 	"fmt"
-	"strings"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
 	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
 )
 
@@ -15,10 +16,36 @@ var searchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search for artifacts in Maven Central",
 	Long:  `Search Maven Central for artifacts matching the query. Shows top 5 results.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  exactArgs(1),
 	RunE:  runSearch,
 }
 
+// searchResult is the output of the search command.
+type searchResult struct {
+	Query   string         `json:"query" yaml:"query"`
+	Results []artifactView `json:"results" yaml:"results"`
+}
+
+// WriteText prints the results as an aligned table.
+func (r *searchResult) WriteText(w io.Writer) error {
+	if len(r.Results) == 0 {
+		_, err := fmt.Fprintln(w, "No artifacts found")
+		return err
+	}
+
+	_, rows := r.TSV()
+	return output.WriteTable(w, []string{"GROUP ID", "ARTIFACT ID", "LATEST VERSION"}, rows)
+}
+
+// TSV returns one row per artifact.
+func (r *searchResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Results))
+	for i, result := range r.Results {
+		rows[i] = []string{result.GroupID, result.ArtifactID, result.LatestVersion}
+	}
+	return []string{"groupId", "artifactId", "latestVersion"}, rows
+}
+
 func runSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
@@ -26,9 +53,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	resolver := maven.NewResolver()
 	service := app.NewSearchArtifactsService(resolver)
 
-	if verbose {
-		fmt.Printf("Searching Maven Central for: %s\n\n", query)
-	}
+	logf("Searching Maven Central for: %s\n\n", query)
 
 	// Search
 	results, err := service.Search(query)
@@ -37,34 +62,13 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display results
-	if len(results) == 0 {
-		fmt.Println("No artifacts found")
-		return nil
+	result := &searchResult{
+		Query:   query,
+		Results: make([]artifactView, len(results)),
 	}
-
-	// Calculate column widths for better formatting
-	maxGroupLen := 0
-	maxArtifactLen := 0
-	for _, result := range results {
-		if len(result.GroupID) > maxGroupLen {
-			maxGroupLen = len(result.GroupID)
-		}
-		if len(result.ArtifactID) > maxArtifactLen {
-			maxArtifactLen = len(result.ArtifactID)
-		}
-	}
-
-	// Print header
-	fmt.Printf("%-*s  %-*s  %s\n", maxGroupLen, "GROUP ID", maxArtifactLen, "ARTIFACT ID", "LATEST VERSION")
-	fmt.Println(strings.Repeat("-", maxGroupLen+maxArtifactLen+30))
-
-	// Print results
-	for _, result := range results {
-		fmt.Printf("%-*s  %-*s  %s\n",
-			maxGroupLen, result.GroupID,
-			maxArtifactLen, result.ArtifactID,
-			result.LatestVersion)
+	for i, artifact := range results {
+		result.Results[i] = newArtifactView(artifact)
 	}
 
-	return nil
+	return printer.Print(result)
 }
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/app"
+	"github.com/elitonkfogaca/mvnx-cli/internal/cli/output"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/maven"
+)
+
+// profile flag for upgrade command
+var upgradeProfile string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade [dependency...]",
+	Short: "Upgrade dependencies to their latest stable version",
+	Long: `Upgrade the dependencies declared in the project's <dependencies> to the latest
+stable version on Maven Central.
+
+Without arguments every dependency is upgraded; otherwise only dependencies
+matching an artifactId, a groupId:artifactId or a glob pattern. Dependencies
+without a version, or whose version is set by a ${property} or a range, are
+reported and left unchanged.
+
+--profile upgrades the dependencies declared in a <profile> instead of those of
+the project itself.`,
+	Example: `  mvnx upgrade
+  mvnx upgrade guava 'org.junit.*:*'
+  mvnx upgrade --profile it`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeProfile, "profile", "", "upgrade the dependencies of this profile")
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	// Find project
+	project, err := findProject()
+	if err != nil {
+		return err
+	}
+
+	// Create service
+	pomRepo := newPomRepository()
+	service := app.NewUpgradeDependencyService(maven.NewResolver(), pomRepo, newLocker())
+
+	// Lock the project and load pom.xml
+	if err := service.LoadPom(project.PomLocation); err != nil {
+		return fmt.Errorf("failed to load pom.xml: %w", err)
+	}
+	defer closeService(service)
+
+	change, err := beginPomChange(project.PomLocation)
+	if err != nil {
+		return err
+	}
+
+	upgrades, err := service.Upgrade(args, upgradeProfile)
+	if err != nil {
+		return err
+	}
+
+	result := &upgradeResult{
+		Pom:      project.PomLocation,
+		Upgrades: make([]dependencyUpgradeView, len(upgrades)),
+		DryRun:   dryRun,
+	}
+	var upgraded []string
+	for i, u := range upgrades {
+		result.Upgrades[i] = dependencyUpgradeView{
+			dependencyView: newDependencyView(u.Dependency),
+			From:           u.From,
+			To:             u.To,
+			Status:         u.Status,
+			Reason:         u.Reason,
+		}
+		if u.Status == app.DependencyUpgraded {
+			upgraded = append(upgraded, u.Dependency.Coordinates()+":"+u.To)
+		}
+	}
+	if len(upgraded) > 0 {
+		change.Record("upgrade " + strings.Join(upgraded, ", ") + profileSuffix(" in", upgradeProfile))
+	}
+
+	result.Diff, err = change.Diff(pomRepo)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(result)
+}
+
+// dependencyUpgradeView is the stable machine-readable representation of a dependency upgrade.
+type dependencyUpgradeView struct {
+	dependencyView `yaml:",inline"`
+	From           string `json:"from,omitempty" yaml:"from,omitempty"`
+	To             string `json:"to,omitempty" yaml:"to,omitempty"`
+	Status         string `json:"status" yaml:"status"`
+	Reason         string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// upgradeResult is the output of the upgrade command.
+type upgradeResult struct {
+	Pom      string                  `json:"pom" yaml:"pom"`
+	Upgrades []dependencyUpgradeView `json:"upgrades" yaml:"upgrades"`
+	DryRun   bool                    `json:"dryRun" yaml:"dryRun"`
+	Diff     string                  `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// WriteText prints the upgrades as an aligned table followed by the diff, if any.
+func (r *upgradeResult) WriteText(w io.Writer) error {
+	if len(r.Upgrades) == 0 {
+		_, err := fmt.Fprintln(w, "No dependencies found")
+		return err
+	}
+
+	rows := make([][]string, len(r.Upgrades))
+	for i, u := range r.Upgrades {
+		status := u.Status
+		if u.Status == app.DependencyUpgraded && r.DryRun {
+			status = "would upgrade"
+		}
+		if u.Reason != "" {
+			status += " (" + u.Reason + ")"
+		}
+		rows[i] = []string{u.GroupID + ":" + u.ArtifactID, orDash(u.From), orDash(u.To), status}
+	}
+	if err := output.WriteTable(w, []string{"DEPENDENCY", "FROM", "TO", "STATUS"}, rows); err != nil {
+		return err
+	}
+	return writeDiff(w, r.Diff)
+}
+
+// TSV returns one row per dependency.
+func (r *upgradeResult) TSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.Upgrades))
+	for i, u := range r.Upgrades {
+		rows[i] = []string{u.GroupID, u.ArtifactID, u.Profile, u.From, u.To, u.Status, u.Reason}
+	}
+	return []string{"groupId", "artifactId", "profile", "from", "to", "status", "reason"}, rows
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// AdvisorySeverity ranks security advisories, as GitHub advisories do.
+type AdvisorySeverity string
+
+// Advisory severities
+const (
+	AdvisoryLow      AdvisorySeverity = "low"
+	AdvisoryModerate AdvisorySeverity = "moderate"
+	AdvisoryHigh     AdvisorySeverity = "high"
+	AdvisoryCritical AdvisorySeverity = "critical"
+
+	// AdvisoryUnknown is an advisory without a severity rating
+	AdvisoryUnknown AdvisorySeverity = "unknown"
+)
+
+// AdvisorySeverities lists the severities from the least to the most severe.
+var AdvisorySeverities = []AdvisorySeverity{AdvisoryUnknown, AdvisoryLow, AdvisoryModerate, AdvisoryHigh, AdvisoryCritical}
+
+// ParseAdvisorySeverity parses a severity case-insensitively; "medium" is moderate.
+// Anything else is unknown.
+func ParseAdvisorySeverity(s string) AdvisorySeverity {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "medium" {
+		return AdvisoryModerate
+	}
+	if severity := AdvisorySeverity(s); slices.Contains(AdvisorySeverities, severity) {
+		return severity
+	}
+	return AdvisoryUnknown
+}
+
+// AtLeast reports whether s is as severe as min, or more.
+func (s AdvisorySeverity) AtLeast(min AdvisorySeverity) bool {
+	return slices.Index(AdvisorySeverities, s) >= slices.Index(AdvisorySeverities, min)
+}
+
+// AdvisoryEvent is an event of an OSV affected range: the version where the
+// vulnerability is introduced, fixed, or last known to be present.
+type AdvisoryEvent struct {
+	Introduced   string
+	Fixed        string
+	LastAffected string
+}
+
+// version returns the version of the event, whatever its kind.
+func (e AdvisoryEvent) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	default:
+		return e.LastAffected
+	}
+}
+
+// Advisory is a security advisory affecting a Maven artifact, in the terms of
+// the OSV schema: the versions affected are given by ranges of events, and by
+// an explicit list of versions.
+type Advisory struct {
+	// ID is the identifier of the advisory, e.g. "GHSA-jfh8-c2jp-5v3q"
+	ID string
+
+	// Aliases are other identifiers of the vulnerability, such as CVE IDs
+	Aliases  []string
+	Summary  string
+	Severity AdvisorySeverity
+
+	// Package is the affected artifact as groupId:artifactId
+	Package string
+
+	// Ranges holds the events of each affected range
+	Ranges   [][]AdvisoryEvent
+	Versions []string
+}
+
+// Affects reports whether the version is affected, comparing versions the way
+// Maven does. Within a range, a version is affected from an introduced event
+// until the next fixed event, or through the next last-affected one.
+func (a *Advisory) Affects(version string) bool {
+	if slices.ContainsFunc(a.Versions, func(v string) bool { return CompareVersions(v, version) == 0 }) {
+		return true
+	}
+
+	for _, events := range a.Ranges {
+		affected := false
+		for _, event := range sortedEvents(events) {
+			switch {
+			case event.Introduced != "":
+				if event.Introduced == "0" || CompareVersions(version, event.Introduced) >= 0 {
+					affected = true
+				}
+			case event.Fixed != "":
+				if CompareVersions(version, event.Fixed) >= 0 {
+					affected = false
+				}
+			case event.LastAffected != "":
+				if CompareVersions(version, event.LastAffected) > 0 {
+					affected = false
+				}
+			}
+		}
+		if affected {
+			return true
+		}
+	}
+	return false
+}
+
+// FirstFixed returns the lowest version fixing the advisory that is higher than
+// version, or "" when no fix is known.
+func (a *Advisory) FirstFixed(version string) string {
+	var first string
+	for _, events := range a.Ranges {
+		for _, event := range events {
+			if event.Fixed == "" || CompareVersions(event.Fixed, version) <= 0 {
+				continue
+			}
+			if first == "" || CompareVersions(event.Fixed, first) < 0 {
+				first = event.Fixed
+			}
+		}
+	}
+	return first
+}
+
+// sortedEvents returns the events in version order; "0" introduces the range
+// before any version.
+func sortedEvents(events []AdvisoryEvent) []AdvisoryEvent {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b AdvisoryEvent) int {
+		switch {
+		case a.Introduced == "0" && b.Introduced == "0":
+			return 0
+		case a.Introduced == "0":
+			return -1
+		case b.Introduced == "0":
+			return 1
+		}
+		return CompareVersions(a.version(), b.version())
+	})
+	return sorted
+}
+
+// AdvisoryDatabase looks up the security advisories of Maven artifacts.
+type AdvisoryDatabase interface {
+	// Advisories returns the advisories affecting some version of groupId:artifactId.
+	// Returns a *NotFoundError if no database has been imported.
+	Advisories(groupID, artifactID string) ([]*Advisory, error)
+}
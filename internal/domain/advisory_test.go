@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisory_Affects(t *testing.T) {
+	advisory := &Advisory{
+		Ranges: [][]AdvisoryEvent{
+			{{Introduced: "0"}, {Fixed: "2.9.10.1"}},
+			{{Introduced: "2.10.0"}, {LastAffected: "2.10.5"}},
+			{{Fixed: "2.12.7.1"}, {Introduced: "2.12.0-rc1"}},
+		},
+		Versions: []string{"3.0.0"},
+	}
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "2.9.10", want: true},
+		{version: "2.9.10-1", want: true},
+		{version: "2.9.10.1", want: false},
+		{version: "2.10.0-rc1", want: false},
+		{version: "2.10.5", want: true},
+		{version: "2.10.5.1", want: false},
+		{version: "2.12.0", want: true},
+		{version: "2.12.7", want: true},
+		{version: "2.12.7.1", want: false},
+		{version: "3.0.0", want: true},
+		{version: "3.0", want: true},
+		{version: "3.0.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, advisory.Affects(tt.version))
+		})
+	}
+
+	assert.Equal(t, "2.9.10.1", advisory.FirstFixed("2.9.8"))
+	assert.Equal(t, "2.12.7.1", advisory.FirstFixed("2.10.5"))
+	assert.Equal(t, "", advisory.FirstFixed("3.0.0"))
+}
+
+func TestParseAdvisorySeverity(t *testing.T) {
+	assert.Equal(t, AdvisoryModerate, ParseAdvisorySeverity("MODERATE"))
+	assert.Equal(t, AdvisoryModerate, ParseAdvisorySeverity("medium"))
+	assert.Equal(t, AdvisoryCritical, ParseAdvisorySeverity("Critical"))
+	assert.Equal(t, AdvisoryUnknown, ParseAdvisorySeverity(""))
+	assert.True(t, AdvisoryHigh.AtLeast(AdvisoryModerate))
+	assert.False(t, AdvisoryLow.AtLeast(AdvisoryModerate))
+}
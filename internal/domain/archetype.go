@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Archetype identifies a Maven archetype, as listed in an archetype catalog.
+type Archetype struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+
+	// Repository is the URL of the repository serving the archetype jar;
+	// empty for Maven Central
+	Repository string
+
+	Description string
+}
+
+// ParseArchetypeCoordinates parses groupId:artifactId[:version]. Without a version,
+// the newest version listed in the archetype catalog is used.
+func ParseArchetypeCoordinates(coordinates string) (*Archetype, error) {
+	parts := strings.Split(coordinates, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, &ValidationError{
+			Field:   "archetype",
+			Message: fmt.Sprintf("invalid archetype %q (expected groupId:artifactId[:version])", coordinates),
+		}
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, &ValidationError{
+				Field:   "archetype",
+				Message: fmt.Sprintf("invalid archetype %q (expected groupId:artifactId[:version])", coordinates),
+			}
+		}
+	}
+
+	archetype := &Archetype{GroupID: parts[0], ArtifactID: parts[1]}
+	if len(parts) == 3 {
+		archetype.Version = parts[2]
+	}
+	return archetype, nil
+}
+
+// String returns the coordinates of the archetype.
+func (a *Archetype) String() string {
+	if a.Version == "" {
+		return a.GroupID + ":" + a.ArtifactID
+	}
+	return a.GroupID + ":" + a.ArtifactID + ":" + a.Version
+}
+
+// ArchetypeRepository finds archetypes in a catalog and fetches their jars.
+type ArchetypeRepository interface {
+	// Find completes the archetype from the catalog. An archetype without a version
+	// gets the newest version listed; returns a *NotFoundError if none is listed.
+	Find(archetype *Archetype) (*Archetype, error)
+
+	// Fetch returns the contents of the archetype jar.
+	Fetch(archetype *Archetype) ([]byte, error)
+}
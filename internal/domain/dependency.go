@@ -1,25 +1,60 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDependencyType is the type Maven assumes when a dependency declares none.
+const DefaultDependencyType = "jar"
+
+// Scopes lists the dependency scopes Maven supports.
+var Scopes = []string{"compile", "provided", "runtime", "test", "system", "import"}
 
 // Dependency represents a Maven dependency with its coordinates and scope.
+// A dependency is identified by groupId, artifactId, type and classifier; see Key.
 type Dependency struct {
 	GroupID    string
 	ArtifactID string
 	Version    string
-	Scope      string // compile, test, provided, runtime
+	Scope      string // one of Scopes
+
+	// Type is the artifact type, e.g. "test-jar" or "pom"; empty means jar
+	Type string
+
+	// Classifier distinguishes artifacts built from the same project, e.g. "linux-x86_64"
+	Classifier string
+
+	// Optional marks dependencies that are not passed on to dependents
+	Optional bool
+
+	// SystemPath is the path of the jar of a system scope dependency
+	SystemPath string
+
+	// Exclusions lists the transitive dependencies left out of the build
+	Exclusions []Exclusion
+
+	// Profile is the id of the <profile> declaring the dependency; empty for the
+	// <dependencies> of the project itself
+	Profile string
+
+	// Managed is set for entries of <dependencyManagement>
+	Managed bool
+
+	// Position is where the declaration starts in the pom.xml; zero when unknown
+	Position Position
 }
 
 // NewDependency creates a new Dependency with validation.
 func NewDependency(groupID, artifactID, version, scope string) (*Dependency, error) {
 	if groupID == "" {
-		return nil, fmt.Errorf("groupID cannot be empty")
+		return nil, &ValidationError{Field: "groupId", Message: "groupID cannot be empty"}
 	}
 	if artifactID == "" {
-		return nil, fmt.Errorf("artifactID cannot be empty")
+		return nil, &ValidationError{Field: "artifactId", Message: "artifactID cannot be empty"}
 	}
 	if version == "" {
-		return nil, fmt.Errorf("version cannot be empty")
+		return nil, &ValidationError{Field: "version", Message: "version cannot be empty"}
 	}
 
 	// Default scope to compile if not specified
@@ -27,15 +62,8 @@ func NewDependency(groupID, artifactID, version, scope string) (*Dependency, err
 		scope = "compile"
 	}
 
-	// Validate scope
-	validScopes := map[string]bool{
-		"compile":  true,
-		"test":     true,
-		"provided": true,
-		"runtime":  true,
-	}
-	if !validScopes[scope] {
-		return nil, fmt.Errorf("invalid scope: %s (valid: compile, test, provided, runtime)", scope)
+	if err := ValidateScope(scope); err != nil {
+		return nil, err
 	}
 
 	return &Dependency{
@@ -46,15 +74,216 @@ func NewDependency(groupID, artifactID, version, scope string) (*Dependency, err
 	}, nil
 }
 
+// ValidateScope checks that scope is one of Scopes.
+func ValidateScope(scope string) error {
+	for _, s := range Scopes {
+		if s == scope {
+			return nil
+		}
+	}
+
+	return &ValidationError{
+		Field:   "scope",
+		Message: fmt.Sprintf("invalid scope: %s (valid: %s)", scope, strings.Join(Scopes, ", ")),
+	}
+}
+
+// Validate checks the constraints Maven puts on a declaration: coordinates are
+// present, system scope comes with a systemPath (and only it does), and import
+// scope is only used for pom dependencies in <dependencyManagement>.
+// managed tells whether the dependency is declared in <dependencyManagement>.
+func (d *Dependency) Validate(managed bool) error {
+	if d.GroupID == "" {
+		return &ValidationError{Field: "groupId", Message: d.Coordinates() + ": missing groupId"}
+	}
+	if d.ArtifactID == "" {
+		return &ValidationError{Field: "artifactId", Message: d.Coordinates() + ": missing artifactId"}
+	}
+
+	scope := d.Scope
+	if scope == "" {
+		scope = "compile"
+	}
+	if err := ValidateScope(scope); err != nil {
+		return err
+	}
+
+	switch {
+	case scope == "system" && d.SystemPath == "":
+		return &ValidationError{Field: "systemPath", Message: d.Coordinates() + ": system scope requires a systemPath"}
+	case scope != "system" && d.SystemPath != "":
+		return &ValidationError{Field: "systemPath", Message: d.Coordinates() + ": systemPath is only allowed with system scope"}
+	case scope == "import" && !managed:
+		return &ValidationError{Field: "scope", Message: d.Coordinates() + ": import scope is only allowed in <dependencyManagement>"}
+	case scope == "import" && d.EffectiveType() != "pom":
+		return &ValidationError{Field: "type", Message: d.Coordinates() + ": import scope requires type pom"}
+	}
+
+	return nil
+}
+
+// IsImport reports whether the dependency imports a BOM into <dependencyManagement>.
+func (d *Dependency) IsImport() bool {
+	return d.Scope == "import" && d.EffectiveType() == "pom"
+}
+
+// ParseCoordinates parses Maven coordinates in one of the forms
+//
+//	groupId:artifactId
+//	groupId:artifactId:version
+//	groupId:artifactId:type:version
+//	groupId:artifactId:type:classifier:version
+//
+// The version is empty in the first form. The returned dependency has compile scope.
+func ParseCoordinates(s string) (*Dependency, error) {
+	parts := strings.Split(s, ":")
+	for _, part := range parts {
+		if part == "" {
+			parts = nil
+			break
+		}
+	}
+
+	dep := &Dependency{Scope: "compile"}
+	switch len(parts) {
+	case 2:
+		dep.GroupID, dep.ArtifactID = parts[0], parts[1]
+	case 3:
+		dep.GroupID, dep.ArtifactID, dep.Version = parts[0], parts[1], parts[2]
+	case 4:
+		dep.GroupID, dep.ArtifactID, dep.Type, dep.Version = parts[0], parts[1], parts[2], parts[3]
+	case 5:
+		dep.GroupID, dep.ArtifactID, dep.Type, dep.Classifier, dep.Version = parts[0], parts[1], parts[2], parts[3], parts[4]
+	default:
+		return nil, &ValidationError{
+			Field:   "coordinates",
+			Message: fmt.Sprintf("invalid coordinates %q (expected groupId:artifactId[:type[:classifier]]:version)", s),
+		}
+	}
+
+	return dep, nil
+}
+
 // String returns a formatted string representation of the dependency.
 func (d *Dependency) String() string {
-	if d.Scope == "compile" || d.Scope == "" {
-		return fmt.Sprintf("%s:%s:%s", d.GroupID, d.ArtifactID, d.Version)
+	var details []string
+	if d.Scope != "compile" && d.Scope != "" {
+		details = append(details, "scope: "+d.Scope)
+	}
+	if d.Optional {
+		details = append(details, "optional")
 	}
-	return fmt.Sprintf("%s:%s:%s (scope: %s)", d.GroupID, d.ArtifactID, d.Version, d.Scope)
+
+	s := d.VersionedID()
+	if len(details) > 0 {
+		s += " (" + strings.Join(details, ", ") + ")"
+	}
+	return s
 }
 
 // Coordinates returns the Maven coordinates without version.
 func (d *Dependency) Coordinates() string {
 	return fmt.Sprintf("%s:%s", d.GroupID, d.ArtifactID)
 }
+
+// ID returns the coordinates a user would type to refer to the dependency:
+// groupId:artifactId for plain jars, and groupId:artifactId:type[:classifier]:version,
+// in the syntax of ParseCoordinates, when it has a type or classifier.
+func (d *Dependency) ID() string {
+	if !d.hasArtifactQualifier() {
+		return d.Coordinates()
+	}
+
+	version := d.Version
+	if version == "" {
+		version = "*"
+	}
+	if d.Classifier == "" {
+		return fmt.Sprintf("%s:%s:%s:%s", d.GroupID, d.ArtifactID, d.EffectiveType(), version)
+	}
+	return fmt.Sprintf("%s:%s:%s:%s:%s", d.GroupID, d.ArtifactID, d.EffectiveType(), d.Classifier, version)
+}
+
+// VersionedID returns the ID followed by the version, e.g. "org.projectlombok:lombok:1.18.30".
+// Since ID already includes the version for dependencies with a type or classifier,
+// it only differs from ID for plain jars.
+func (d *Dependency) VersionedID() string {
+	if d.hasArtifactQualifier() || d.Version == "" {
+		return d.ID()
+	}
+	return d.Coordinates() + ":" + d.Version
+}
+
+// Key identifies the dependency within a pom.xml the way Maven does:
+// groupId:artifactId:type, followed by :classifier when there is one.
+// Two declarations with the same key are the same dependency.
+func (d *Dependency) Key() string {
+	key := d.Coordinates() + ":" + d.EffectiveType()
+	if d.Classifier != "" {
+		key += ":" + d.Classifier
+	}
+	return key
+}
+
+// EffectiveType returns the type of the dependency, defaulting to jar.
+func (d *Dependency) EffectiveType() string {
+	if d.Type == "" {
+		return DefaultDependencyType
+	}
+	return d.Type
+}
+
+// SameArtifact reports whether both dependencies have the same key.
+func (d *Dependency) SameArtifact(other *Dependency) bool {
+	return d.Key() == other.Key()
+}
+
+// hasArtifactQualifier reports whether the dependency has a type other than jar or a classifier.
+func (d *Dependency) hasArtifactQualifier() bool {
+	return d.EffectiveType() != DefaultDependencyType || d.Classifier != ""
+}
+
+// HasExclusion reports whether the dependency already declares the exclusion.
+func (d *Dependency) HasExclusion(exclusion Exclusion) bool {
+	for _, e := range d.Exclusions {
+		if e == exclusion {
+			return true
+		}
+	}
+	return false
+}
+
+// Exclusion is a transitive dependency excluded from a dependency.
+// Either part may be "*", which Maven treats as a wildcard.
+type Exclusion struct {
+	GroupID    string
+	ArtifactID string
+}
+
+// ParseExclusion parses "groupId:artifactId". Maven only supports "*" as a whole
+// groupId or artifactId, so partial patterns such as "log4j-*" are rejected.
+func ParseExclusion(s string) (Exclusion, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Exclusion{}, &ValidationError{
+			Field:   "exclusion",
+			Message: fmt.Sprintf("invalid exclusion %q (expected groupId:artifactId)", s),
+		}
+	}
+
+	for _, part := range parts {
+		if part != "*" && strings.ContainsAny(part, "*?[") {
+			return Exclusion{}, &ValidationError{
+				Field:   "exclusion",
+				Message: fmt.Sprintf("invalid exclusion %q: Maven only supports * as a whole groupId or artifactId", s),
+			}
+		}
+	}
+
+	return Exclusion{GroupID: parts[0], ArtifactID: parts[1]}, nil
+}
+
+// String returns the exclusion as groupId:artifactId.
+func (e Exclusion) String() string {
+	return e.GroupID + ":" + e.ArtifactID
+}
@@ -82,7 +82,8 @@ func TestNewDependency(t *testing.T) {
 			dep, err := NewDependency(tt.groupID, tt.artifactID, tt.version, tt.scope)
 
 			if tt.wantErr {
-				assert.Error(t, err)
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
 				assert.Nil(t, dep)
 			} else {
 				assert.NoError(t, err)
@@ -122,6 +123,29 @@ func TestDependency_String(t *testing.T) {
 			},
 			expected: "junit:junit:4.13.2 (scope: test)",
 		},
+		{
+			name: "classifier",
+			dependency: &Dependency{
+				GroupID:    "io.netty",
+				ArtifactID: "netty-transport-native-epoll",
+				Version:    "4.1.100.Final",
+				Scope:      "runtime",
+				Classifier: "linux-x86_64",
+			},
+			expected: "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final (scope: runtime)",
+		},
+		{
+			name: "type and optional",
+			dependency: &Dependency{
+				GroupID:    "org.example",
+				ArtifactID: "my-lib",
+				Version:    "1.0.0",
+				Scope:      "test",
+				Type:       "test-jar",
+				Optional:   true,
+			},
+			expected: "org.example:my-lib:test-jar:1.0.0 (scope: test, optional)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,3 +166,120 @@ func TestDependency_Coordinates(t *testing.T) {
 
 	assert.Equal(t, "org.example:my-lib", dep.Coordinates())
 }
+
+func TestDependency_Key(t *testing.T) {
+	jar := &Dependency{GroupID: "org.example", ArtifactID: "my-lib", Version: "1.0.0"}
+	explicitJar := &Dependency{GroupID: "org.example", ArtifactID: "my-lib", Version: "2.0.0", Type: "jar"}
+	testJar := &Dependency{GroupID: "org.example", ArtifactID: "my-lib", Type: "test-jar"}
+	sources := &Dependency{GroupID: "org.example", ArtifactID: "my-lib", Classifier: "sources"}
+
+	assert.Equal(t, "org.example:my-lib:jar", jar.Key())
+	assert.Equal(t, "org.example:my-lib:test-jar", testJar.Key())
+	assert.Equal(t, "org.example:my-lib:jar:sources", sources.Key())
+
+	assert.True(t, jar.SameArtifact(explicitJar))
+	assert.False(t, jar.SameArtifact(testJar))
+	assert.False(t, jar.SameArtifact(sources))
+
+	assert.Equal(t, "org.example:my-lib", jar.ID())
+	assert.Equal(t, "org.example:my-lib:test-jar:*", testJar.ID())
+	assert.Equal(t, "org.example:my-lib:jar:sources:*", sources.ID())
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *Dependency
+		wantErr bool
+	}{
+		{input: "org.projectlombok:lombok", want: &Dependency{GroupID: "org.projectlombok", ArtifactID: "lombok", Scope: "compile"}},
+		{input: "org.projectlombok:lombok:1.18.30", want: &Dependency{GroupID: "org.projectlombok", ArtifactID: "lombok", Version: "1.18.30", Scope: "compile"}},
+		{input: "org.example:my-lib:test-jar:1.0.0", want: &Dependency{GroupID: "org.example", ArtifactID: "my-lib", Type: "test-jar", Version: "1.0.0", Scope: "compile"}},
+		{
+			input: "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final",
+			want: &Dependency{
+				GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll",
+				Type: "jar", Classifier: "linux-x86_64", Version: "4.1.100.Final", Scope: "compile",
+			},
+		},
+		{input: "lombok", wantErr: true},
+		{input: "org.projectlombok::1.18.30", wantErr: true},
+		{input: "a:b:c:d:e:f", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCoordinates(tt.input)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExclusion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Exclusion
+		wantErr bool
+	}{
+		{input: "commons-logging:commons-logging", want: Exclusion{GroupID: "commons-logging", ArtifactID: "commons-logging"}},
+		{input: "org.apache.logging.log4j:*", want: Exclusion{GroupID: "org.apache.logging.log4j", ArtifactID: "*"}},
+		{input: "*:*", want: Exclusion{GroupID: "*", ArtifactID: "*"}},
+		{input: "commons-logging", wantErr: true},
+		{input: "org.apache.logging.log4j:log4j-*", wantErr: true},
+		{input: ":log4j", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseExclusion(tt.input)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
+
+func TestDependency_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dep     *Dependency
+		managed bool
+		wantErr bool
+	}{
+		{name: "compile", dep: &Dependency{GroupID: "g", ArtifactID: "a", Scope: "compile"}},
+		{name: "default scope", dep: &Dependency{GroupID: "g", ArtifactID: "a"}},
+		{name: "system with path", dep: &Dependency{GroupID: "g", ArtifactID: "a", Scope: "system", SystemPath: "${basedir}/lib/a.jar"}},
+		{name: "system without path", dep: &Dependency{GroupID: "g", ArtifactID: "a", Scope: "system"}, wantErr: true},
+		{name: "path without system", dep: &Dependency{GroupID: "g", ArtifactID: "a", Scope: "compile", SystemPath: "lib/a.jar"}, wantErr: true},
+		{name: "import bom", dep: &Dependency{GroupID: "g", ArtifactID: "bom", Scope: "import", Type: "pom"}, managed: true},
+		{name: "import outside management", dep: &Dependency{GroupID: "g", ArtifactID: "bom", Scope: "import", Type: "pom"}, wantErr: true},
+		{name: "import jar", dep: &Dependency{GroupID: "g", ArtifactID: "bom", Scope: "import"}, managed: true, wantErr: true},
+		{name: "unknown scope", dep: &Dependency{GroupID: "g", ArtifactID: "a", Scope: "runtim"}, wantErr: true},
+		{name: "missing groupId", dep: &Dependency{ArtifactID: "a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.dep.Validate(tt.managed)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
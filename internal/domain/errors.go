@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotFoundError reports that a requested artifact or dependency does not exist.
+type NotFoundError struct {
+	// Kind describes what was looked up, e.g. "artifact" or "dependency"
+	Kind string
+
+	// Name identifies the missing item
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %s", e.Kind, e.Name)
+}
+
+// ProjectNotFoundError reports that no pom.xml exists in a directory or any of its parents.
+type ProjectNotFoundError struct {
+	// Path is the directory where the search started
+	Path string
+}
+
+func (e *ProjectNotFoundError) Error() string {
+	return fmt.Sprintf("no Maven project found: no pom.xml in %s or its parent directories", e.Path)
+}
+
+// AmbiguousError reports that a query matches several candidates and none was chosen.
+type AmbiguousError struct {
+	// Query is the user input that matched several candidates
+	Query string
+
+	// Candidates lists the matching items in display order
+	Candidates []string
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "query %q matches %d candidates:", e.Query, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		fmt.Fprintf(&b, "\n  [%d] %s", i+1, candidate)
+	}
+
+	return b.String()
+}
+
+// NoStableVersionError reports that an artifact exists but has no stable release.
+type NoStableVersionError struct {
+	// Coordinates is the groupId:artifactId (or query) that was resolved
+	Coordinates string
+
+	// Latest is the newest, unstable version, if known
+	Latest string
+}
+
+func (e *NoStableVersionError) Error() string {
+	if e.Latest == "" {
+		return fmt.Sprintf("no stable version found for %s", e.Coordinates)
+	}
+	return fmt.Sprintf("no stable version found for %s (latest: %s)", e.Coordinates, e.Latest)
+}
+
+// PomParseError reports a pom.xml that cannot be read or is not well-formed XML.
+type PomParseError struct {
+	// Path is the location of the pom.xml
+	Path string
+
+	// Err is the underlying read or syntax error
+	Err error
+}
+
+func (e *PomParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Path, e.Err)
+}
+
+func (e *PomParseError) Unwrap() error {
+	return e.Err
+}
+
+// NetworkError reports a failure talking to a remote repository.
+type NetworkError struct {
+	// URL is the address that was requested
+	URL string
+
+	// StatusCode is the HTTP status, or 0 if no response was received
+	StatusCode int
+
+	// Err is the underlying transport error, if any
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("request to %s failed with status %d", e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("request to %s failed: %v", e.URL, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError reports that the state on disk conflicts with the requested operation.
+type ConflictError struct {
+	// Path is the file or directory in conflict
+	Path string
+
+	// Reason explains the conflict
+	Reason string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+// ValidationError reports invalid user input, such as an unknown scope.
+type ValidationError struct {
+	// Field names the invalid input
+	Field string
+
+	// Message describes the problem
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// LicensePolicyError reports that the license policy of the project refuses an artifact.
+type LicensePolicyError struct {
+	// Artifact is the groupId:artifactId:version refused
+	Artifact string
+
+	// Verdict is LicenseDenied, or LicenseUnknown when the policy denies unknown licenses
+	Verdict LicenseVerdict
+
+	// Licenses are the licenses of the artifact, see License.String; empty when
+	// it declares none or they could not be read
+	Licenses []string
+}
+
+func (e *LicensePolicyError) Error() string {
+	switch {
+	case e.Verdict == LicenseDenied:
+		return fmt.Sprintf("the license policy denies %s (%s)", e.Artifact, strings.Join(e.Licenses, ", "))
+	case len(e.Licenses) == 0:
+		return fmt.Sprintf("the license policy refuses %s: its license is unknown", e.Artifact)
+	default:
+		return fmt.Sprintf("the license policy refuses %s: its license is unknown (%s)", e.Artifact, strings.Join(e.Licenses, ", "))
+	}
+}
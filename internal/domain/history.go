@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// HistoryEntry records one change mvnx made to a pom.xml.
+type HistoryEntry struct {
+	// ID increases with every recorded change
+	ID int
+
+	// Time is when the change was made
+	Time time.Time
+
+	// Description summarizes the operation, e.g. "add org.projectlombok:lombok:1.18.30"
+	Description string
+
+	// Before and After are the pom.xml contents around the change
+	Before []byte
+	After  []byte
+}
+
+// HistoryStore persists the changes made to a project's pom.xml, oldest first.
+type HistoryStore interface {
+	// Append records a new entry and assigns its ID.
+	Append(entry *HistoryEntry) error
+
+	// List returns all entries, oldest first.
+	List() ([]*HistoryEntry, error)
+
+	// Remove deletes the entry with the given ID.
+	Remove(id int) error
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kinds of JavaDeclaration
+const (
+	// JavaProperty is a property of <properties>, e.g. maven.compiler.release or java.version
+	JavaProperty = "property"
+
+	// JavaCompilerPlugin is a <source>, <target> or <release> of maven-compiler-plugin
+	JavaCompilerPlugin = "compiler-plugin"
+
+	// JavaToolchain is the <jdk><version> requirement of maven-toolchains-plugin
+	JavaToolchain = "toolchain"
+)
+
+// classFileOffset maps class file major versions to Java releases: 52 is Java 8, 65 is Java 21
+const classFileOffset = 44
+
+// JavaProperties are the properties holding a Java release level. java.version is
+// not read by Maven itself, but by parents such as spring-boot-starter-parent.
+var JavaProperties = []string{
+	"maven.compiler.release", "maven.compiler.source", "maven.compiler.target",
+	"maven.compiler.testRelease", "maven.compiler.testSource", "maven.compiler.testTarget",
+	"java.version",
+}
+
+// javaLevelPattern matches the release level inside a value such as "1.8", "21" or "[17,)"
+var javaLevelPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// JavaDeclaration is a place in a pom.xml where the Java release level is set.
+type JavaDeclaration struct {
+	// Location identifies the element, e.g. "properties/maven.compiler.release" or
+	// "build/plugins/maven-compiler-plugin/configuration/release"
+	Location string
+
+	// Kind is JavaProperty, JavaCompilerPlugin or JavaToolchain
+	Kind string
+
+	// Value is the text as written, e.g. "17", "1.8", "${java.version}" or "[17,)"
+	Value string
+}
+
+// PropertyReference returns the name of the property the value consists of,
+// e.g. "java.version" for "${java.version}", or "" for any other value.
+func (d *JavaDeclaration) PropertyReference() string {
+	if strings.HasPrefix(d.Value, "${") && strings.HasSuffix(d.Value, "}") && strings.Count(d.Value, "${") == 1 {
+		return d.Value[2 : len(d.Value)-1]
+	}
+	return ""
+}
+
+// Release returns the release level of a literal value, or 0 if it has none,
+// e.g. for a property reference. Toolchain ranges give their lower bound.
+func (d *JavaDeclaration) Release() int {
+	if strings.Contains(d.Value, "${") {
+		return 0
+	}
+	release, err := ParseJavaRelease(javaLevelPattern.FindString(d.Value))
+	if err != nil {
+		return 0
+	}
+	return release
+}
+
+// WithRelease returns the value rewritten for another release level, in the
+// style of the current one: "1.8" stays in the "1.x" form for releases up to 8,
+// and a toolchain range such as "[17,)" keeps its brackets.
+func (d *JavaDeclaration) WithRelease(release int) string {
+	level := strconv.Itoa(release)
+	if release <= 8 && strings.HasPrefix(javaLevelPattern.FindString(d.Value), "1.") {
+		level = "1." + level
+	}
+
+	if d.Kind == JavaToolchain {
+		if match := javaLevelPattern.FindStringIndex(d.Value); match != nil {
+			return d.Value[:match[0]] + level + d.Value[match[1]:]
+		}
+	}
+	return level
+}
+
+// ParseJavaRelease parses a Java release level such as "21", or "1.8" for Java 8.
+func ParseJavaRelease(value string) (int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(value), "1.")
+	release, err := strconv.Atoi(trimmed)
+	if err != nil || release < 1 {
+		return 0, &ValidationError{
+			Field:   "java",
+			Message: fmt.Sprintf("invalid Java version: %s (expected a release such as 17 or 21)", value),
+		}
+	}
+	return release, nil
+}
+
+// JavaReleaseOfClassFile returns the Java release of a class file major version, e.g. 21 for 65.
+func JavaReleaseOfClassFile(major int) int {
+	return major - classFileOffset
+}
+
+// ClassFileInspector reads the Java release the classes of an artifact were compiled for.
+type ClassFileInspector interface {
+	// JavaRelease returns the highest Java release targeted by the classes in the
+	// jar of dep at the given version. Returns a *NotFoundError if the jar is not
+	// available locally.
+	JavaRelease(dep *Dependency, version string) (int, error)
+}
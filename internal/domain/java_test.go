@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJavaDeclaration(t *testing.T) {
+	tests := []struct {
+		name        string
+		decl        JavaDeclaration
+		wantRelease int
+		wantRef     string
+		with        int
+		wantValue   string
+	}{
+		{name: "release", decl: JavaDeclaration{Kind: JavaProperty, Value: "17"}, wantRelease: 17, with: 21, wantValue: "21"},
+		{name: "legacy form", decl: JavaDeclaration{Kind: JavaCompilerPlugin, Value: "1.8"}, wantRelease: 8, with: 11, wantValue: "11"},
+		{name: "legacy form kept", decl: JavaDeclaration{Kind: JavaCompilerPlugin, Value: "1.7"}, wantRelease: 7, with: 8, wantValue: "1.8"},
+		{name: "toolchain range", decl: JavaDeclaration{Kind: JavaToolchain, Value: "[17,)"}, wantRelease: 17, with: 21, wantValue: "[21,)"},
+		{name: "property reference", decl: JavaDeclaration{Kind: JavaProperty, Value: "${java.version}"}, wantRef: "java.version", with: 21, wantValue: "21"},
+		{name: "not a release", decl: JavaDeclaration{Kind: JavaProperty, Value: "latest"}, with: 21, wantValue: "21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRelease, tt.decl.Release())
+			assert.Equal(t, tt.wantRef, tt.decl.PropertyReference())
+			assert.Equal(t, tt.wantValue, tt.decl.WithRelease(tt.with))
+		})
+	}
+}
+
+func TestParseJavaRelease(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "21", want: 21},
+		{value: "1.8", want: 8},
+		{value: " 17 ", want: 17},
+		{value: "jdk17", wantErr: true},
+		{value: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseJavaRelease(tt.value)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
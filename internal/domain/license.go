@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// License is an entry of the <licenses> of a pom.xml.
+type License struct {
+	Name string
+	URL  string
+
+	// SPDX is the SPDX identifier of the license, e.g. "Apache-2.0"; empty when
+	// the name and URL are not recognized, see NormalizeLicense
+	SPDX string
+}
+
+// spdxIdentifiers lists the SPDX identifiers recognized as written, mostly the
+// licenses found on Maven Central.
+var spdxIdentifiers = []string{
+	"0BSD", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0", "BSD-2-Clause",
+	"BSD-3-Clause", "BSL-1.0", "CC-BY-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1", "EPL-1.0",
+	"EPL-2.0", "EUPL-1.2", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later",
+	"ISC", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only", "LGPL-3.0-or-later", "MIT",
+	"MIT-0", "MPL-1.1", "MPL-2.0", "Unlicense", "UPL-1.0", "WTFPL", "Zlib",
+}
+
+// licenseNames maps normalized license names, see normalizeLicenseName, to SPDX identifiers.
+var licenseNames = map[string]string{
+	"apache 2.0":                          "Apache-2.0",
+	"apache 2":                            "Apache-2.0",
+	"apache2":                             "Apache-2.0",
+	"asl 2.0":                             "Apache-2.0",
+	"asf 2.0":                             "Apache-2.0",
+	"al 2.0":                              "Apache-2.0",
+	"apache 1.1":                          "Apache-1.1",
+	"mit":                                 "MIT",
+	"expat":                               "MIT",
+	"bouncy castle":                       "MIT",
+	"mit no attribution":                  "MIT-0",
+	"bsd 2 clause":                        "BSD-2-Clause",
+	"simplified bsd":                      "BSD-2-Clause",
+	"freebsd":                             "BSD-2-Clause",
+	"bsd 3 clause":                        "BSD-3-Clause",
+	"new bsd":                             "BSD-3-Clause",
+	"revised bsd":                         "BSD-3-Clause",
+	"modified bsd":                        "BSD-3-Clause",
+	"eclipse distribution 1.0":            "BSD-3-Clause",
+	"edl 1.0":                             "BSD-3-Clause",
+	"eclipse public 1.0":                  "EPL-1.0",
+	"epl 1.0":                             "EPL-1.0",
+	"eclipse public 2.0":                  "EPL-2.0",
+	"epl 2.0":                             "EPL-2.0",
+	"common development distribution":     "CDDL-1.0",
+	"cddl 1.0":                            "CDDL-1.0",
+	"common development distribution 1.0": "CDDL-1.0",
+	"cddl 1.1":                            "CDDL-1.1",
+	"common development distribution 1.1": "CDDL-1.1",
+	"mozilla public 1.1":                  "MPL-1.1",
+	"mpl 1.1":                             "MPL-1.1",
+	"mozilla public 2.0":                  "MPL-2.0",
+	"mpl 2.0":                             "MPL-2.0",
+	"gnu lesser general public 2.1":       "LGPL-2.1-only",
+	"lgpl 2.1":                            "LGPL-2.1-only",
+	"gnu lesser general public 3":         "LGPL-3.0-only",
+	"gnu lesser general public 3.0":       "LGPL-3.0-only",
+	"lgpl 3.0":                            "LGPL-3.0-only",
+	"lgpl 3":                              "LGPL-3.0-only",
+	"gnu general public 2":                "GPL-2.0-only",
+	"gnu general public 2.0":              "GPL-2.0-only",
+	"gpl 2.0":                             "GPL-2.0-only",
+	"gplv2":                               "GPL-2.0-only",
+	"gnu general public 3":                "GPL-3.0-only",
+	"gnu general public 3.0":              "GPL-3.0-only",
+	"gpl 3.0":                             "GPL-3.0-only",
+	"gplv3":                               "GPL-3.0-only",
+	"gnu affero general public 3":         "AGPL-3.0-only",
+	"gnu affero general public 3.0":       "AGPL-3.0-only",
+	"gplv2 w cpe":                         "GPL-2.0-only WITH Classpath-exception-2.0",
+	"gpl 2.0 with classpath exception":    "GPL-2.0-only WITH Classpath-exception-2.0",
+	"gnu general public 2 with classpath exception": "GPL-2.0-only WITH Classpath-exception-2.0",
+	"boost 1.0":                           "BSL-1.0",
+	"isc":                                 "ISC",
+	"cc0":                                 "CC0-1.0",
+	"cc0 1.0":                             "CC0-1.0",
+	"cc0 1.0 universal":                   "CC0-1.0",
+	"creative commons zero 1.0 universal": "CC0-1.0",
+	"unlicense":                           "Unlicense",
+	"universal permissive 1.0":            "UPL-1.0",
+	"upl 1.0":                             "UPL-1.0",
+	"european union public 1.2":           "EUPL-1.2",
+	"zlib":                                "Zlib",
+	"wtfpl":                               "WTFPL",
+}
+
+// licenseURLs maps normalized license URLs, see normalizeLicenseURL, to SPDX identifiers.
+var licenseURLs = map[string]string{
+	"apache.org/licenses/license-2.0":                     "Apache-2.0",
+	"apache.org/licenses/license-1.1":                     "Apache-1.1",
+	"opensource.org/licenses/mit-license":                 "MIT",
+	"mit-license.org":                                     "MIT",
+	"opensource.org/licenses/bsd-license":                 "BSD-2-Clause",
+	"opensource.org/licenses/bsd-3-clause":                "BSD-3-Clause",
+	"eclipse.org/legal/epl-v10":                           "EPL-1.0",
+	"eclipse.org/legal/epl-2.0":                           "EPL-2.0",
+	"eclipse.org/legal/epl-v20":                           "EPL-2.0",
+	"eclipse.org/org/documents/edl-v10":                   "BSD-3-Clause",
+	"eclipse.org/org/documents/epl-v10":                   "EPL-1.0",
+	"mozilla.org/mpl/2.0":                                 "MPL-2.0",
+	"mozilla.org/mpl/mpl-1.1":                             "MPL-1.1",
+	"gnu.org/licenses/old-licenses/lgpl-2.1":              "LGPL-2.1-only",
+	"gnu.org/licenses/lgpl-2.1":                           "LGPL-2.1-only",
+	"gnu.org/licenses/lgpl":                               "LGPL-3.0-only",
+	"gnu.org/licenses/lgpl-3.0":                           "LGPL-3.0-only",
+	"gnu.org/licenses/old-licenses/gpl-2.0":               "GPL-2.0-only",
+	"gnu.org/licenses/gpl-2.0":                            "GPL-2.0-only",
+	"gnu.org/licenses/gpl":                                "GPL-3.0-only",
+	"gnu.org/licenses/gpl-3.0":                            "GPL-3.0-only",
+	"gnu.org/licenses/agpl-3.0":                           "AGPL-3.0-only",
+	"gnu.org/software/classpath/license":                  "GPL-2.0-only WITH Classpath-exception-2.0",
+	"openjdk.java.net/legal/gplv2+ce":                     "GPL-2.0-only WITH Classpath-exception-2.0",
+	"opensource.org/licenses/cddl1":                       "CDDL-1.0",
+	"glassfish.dev.java.net/public/cddlv1.0":              "CDDL-1.0",
+	"boost.org/license_1_0":                               "BSL-1.0",
+	"creativecommons.org/publicdomain/zero/1.0":           "CC0-1.0",
+	"creativecommons.org/publicdomain/zero/1.0/legalcode": "CC0-1.0",
+	"unlicense.org":                                       "Unlicense",
+	"bouncycastle.org/licence":                            "MIT",
+	"oss.oracle.com/licenses/upl":                         "UPL-1.0",
+}
+
+var (
+	// licenseNameSeparators matches what separates words in a license name
+	licenseNameSeparators = regexp.MustCompile(`[^a-z0-9.+]+`)
+
+	// licenseVersion matches a version glued to a "v", e.g. "v2" or "v2.0"
+	licenseVersion = regexp.MustCompile(`^v(\d+(\.\d+)*)$`)
+
+	// licenseURLIdentifier matches the URLs naming an SPDX identifier
+	licenseURLIdentifier = regexp.MustCompile(`^(spdx\.org/licenses|opensource\.org/licenses?)/([a-z0-9.+-]+)$`)
+)
+
+// noiseWords are left out of normalized license names.
+var noiseWords = map[string]bool{
+	"the": true, "license": true, "licence": true, "licenses": true, "version": true,
+	"software": true, "and": true, "v": true,
+}
+
+// NormalizeLicense returns the SPDX identifier of a license from its name, or
+// else from its URL, as found in pom.xml files; "" when neither is recognized.
+// Names are compared without case, punctuation and noise words such as "The"
+// or "Version", so "The Apache Software License, Version 2.0" is Apache-2.0.
+func NormalizeLicense(name, url string) string {
+	if id := spdxIdentifier(strings.TrimSpace(name)); id != "" {
+		return id
+	}
+	if id, ok := licenseNames[normalizeLicenseName(name)]; ok {
+		return id
+	}
+
+	normalized := normalizeLicenseURL(url)
+	if id, ok := licenseURLs[normalized]; ok {
+		return id
+	}
+	if match := licenseURLIdentifier.FindStringSubmatch(normalized); match != nil {
+		if id := spdxIdentifier(match[2]); id != "" {
+			return id
+		}
+		if id, ok := licenseNames[normalizeLicenseName(match[2])]; ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// spdxIdentifier returns the SPDX identifier equal to s without case, or "".
+func spdxIdentifier(s string) string {
+	for _, id := range spdxIdentifiers {
+		if strings.EqualFold(id, s) {
+			return id
+		}
+	}
+	return ""
+}
+
+// normalizeLicenseName lowercases a license name and keeps its significant
+// words, separated by single spaces, once each; "v2.0" becomes "2.0".
+func normalizeLicenseName(name string) string {
+	var words []string
+	for _, word := range strings.Fields(licenseNameSeparators.ReplaceAllString(strings.ToLower(name), " ")) {
+		word = strings.Trim(word, ".")
+		if match := licenseVersion.FindStringSubmatch(word); match != nil {
+			word = match[1]
+		}
+		// "The MIT License (MIT)" repeats the name
+		if word != "" && !noiseWords[word] && (len(words) == 0 || words[len(words)-1] != word) {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeLicenseURL lowercases a license URL and strips its scheme, "www.",
+// trailing slash and file extension.
+func normalizeLicenseURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	url = strings.TrimSuffix(url, "/")
+	for _, extension := range []string{".txt", ".html", ".htm", ".php", ".md"} {
+		url = strings.TrimSuffix(url, extension)
+	}
+	return url
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// LicenseVerdict is how a license policy judges the licenses of an artifact.
+type LicenseVerdict string
+
+const (
+	LicenseAllowed LicenseVerdict = "allowed"
+	LicenseDenied  LicenseVerdict = "denied"
+
+	// LicenseUnknown is for artifacts declaring no license, only licenses without
+	// an SPDX identifier, or licenses the policy lists neither as allowed nor denied
+	LicenseUnknown LicenseVerdict = "unknown"
+)
+
+// LicensePolicy declares which licenses a project accepts in its dependencies.
+type LicensePolicy struct {
+	// Allowed and Denied list SPDX identifiers. When Allowed is empty, every
+	// license that is not denied is allowed; otherwise the licenses in neither
+	// list are unknown.
+	Allowed []string
+	Denied  []string
+
+	// DenyUnknown refuses artifacts whose verdict is unknown instead of warning
+	DenyUnknown bool
+
+	// Exceptions accept artifacts regardless of their licenses
+	Exceptions []*LicenseException
+}
+
+// LicenseException accepts an artifact the policy would refuse or warn about.
+type LicenseException struct {
+	// Artifact is the groupId:artifactId of the artifact
+	Artifact string
+
+	// Licenses are the licenses of the artifact when the exception was made,
+	// as SPDX identifiers, or names when not recognized
+	Licenses []string
+
+	// Justification explains why the artifact is accepted
+	Justification string
+
+	// Added is when the exception was made
+	Added time.Time
+}
+
+// LicensePolicyStore reads the license policy of a project and records exceptions.
+type LicensePolicyStore interface {
+	// Load returns the license policy, or nil if the project has none.
+	Load() (*LicensePolicy, error)
+
+	// AddException records an exception in the policy.
+	AddException(exception *LicenseException) error
+}
+
+// String returns the SPDX identifier of the license, or else its name or URL.
+func (l *License) String() string {
+	switch {
+	case l.SPDX != "":
+		return l.SPDX
+	case l.Name != "":
+		return l.Name
+	default:
+		return l.URL
+	}
+}
+
+// Evaluate judges the licenses of an artifact. An artifact offering several
+// licenses may be used under any of them, so it is allowed if one of them is,
+// and denied only if all of them are.
+func (p *LicensePolicy) Evaluate(licenses []*License) LicenseVerdict {
+	if len(licenses) == 0 {
+		return LicenseUnknown
+	}
+
+	verdict := LicenseDenied
+	for _, license := range licenses {
+		switch {
+		case license.SPDX == "":
+			verdict = LicenseUnknown
+		case slices.Contains(p.Allowed, license.SPDX):
+			return LicenseAllowed
+		case slices.Contains(p.Denied, license.SPDX):
+		case len(p.Allowed) == 0:
+			return LicenseAllowed
+		default:
+			verdict = LicenseUnknown
+		}
+	}
+	return verdict
+}
+
+// Exception returns the exception accepting groupId:artifactId, or nil.
+func (p *LicensePolicy) Exception(groupID, artifactID string) *LicenseException {
+	for _, exception := range p.Exceptions {
+		if exception.Artifact == groupID+":"+artifactID {
+			return exception
+		}
+	}
+	return nil
+}
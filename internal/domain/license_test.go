@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLicense(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"The Apache Software License, Version 2.0", "", "Apache-2.0"},
+		{"Apache License, Version 2.0", "", "Apache-2.0"},
+		{"ASL 2.0", "", "Apache-2.0"},
+		{"apache-2.0", "", "Apache-2.0"},
+		{"MIT License", "", "MIT"},
+		{"The MIT License (MIT)", "", "MIT"},
+		{"BSD 3-Clause", "", "BSD-3-Clause"},
+		{"New BSD License", "", "BSD-3-Clause"},
+		{"Eclipse Distribution License - v 1.0", "", "BSD-3-Clause"},
+		{"Eclipse Public License - v 2.0", "", "EPL-2.0"},
+		{"Eclipse Public License v1.0", "", "EPL-1.0"},
+		{"GNU Lesser General Public License v2.1", "", "LGPL-2.1-only"},
+		{"GPLv2 w/ CPE", "", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"CDDL 1.1", "", "CDDL-1.1"},
+		{"Mozilla Public License, Version 2.0", "", "MPL-2.0"},
+		{"CC0", "", "CC0-1.0"},
+		{"Apache 2", "http://www.apache.org/licenses/LICENSE-2.0.txt", "Apache-2.0"},
+		{"", "https://www.apache.org/licenses/LICENSE-2.0", "Apache-2.0"},
+		{"", "https://opensource.org/licenses/MIT", "MIT"},
+		{"", "https://spdx.org/licenses/BSD-2-Clause.html", "BSD-2-Clause"},
+		{"", "http://www.eclipse.org/org/documents/edl-v10.php", "BSD-3-Clause"},
+		{"Bespoke", "http://www.gnu.org/licenses/old-licenses/lgpl-2.1.html", "LGPL-2.1-only"},
+		// Ambiguous or proprietary licenses are not guessed
+		{"BSD License", "", ""},
+		{"GPL", "", ""},
+		{"Proprietary", "https://example.com/license", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeLicense(tt.name, tt.url))
+		})
+	}
+}
+
+func TestLicensePolicy_Evaluate(t *testing.T) {
+	apache := &License{Name: "Apache License 2.0", SPDX: "Apache-2.0"}
+	gpl := &License{SPDX: "GPL-3.0-only"}
+	epl := &License{SPDX: "EPL-2.0"}
+	custom := &License{Name: "Custom"}
+
+	allowList := &LicensePolicy{Allowed: []string{"Apache-2.0"}, Denied: []string{"GPL-3.0-only"}}
+	denyList := &LicensePolicy{Denied: []string{"GPL-3.0-only"}}
+
+	tests := []struct {
+		name     string
+		policy   *LicensePolicy
+		licenses []*License
+		want     LicenseVerdict
+	}{
+		{"allowed", allowList, []*License{apache}, LicenseAllowed},
+		{"denied", allowList, []*License{gpl}, LicenseDenied},
+		{"dual licensed, one allowed", allowList, []*License{gpl, apache}, LicenseAllowed},
+		{"not listed", allowList, []*License{epl}, LicenseUnknown},
+		{"not recognized", allowList, []*License{custom}, LicenseUnknown},
+		{"none declared", allowList, nil, LicenseUnknown},
+		{"denied or not recognized", allowList, []*License{gpl, custom}, LicenseUnknown},
+		{"not denied without allow list", denyList, []*License{epl}, LicenseAllowed},
+		{"denied without allow list", denyList, []*License{gpl}, LicenseDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Evaluate(tt.licenses))
+		})
+	}
+}
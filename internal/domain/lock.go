@@ -0,0 +1,16 @@
+package domain
+
+// Lock is an advisory lock held on a project.
+type Lock interface {
+	// Unlock releases the lock.
+	Unlock() error
+}
+
+// Locker serializes pom.xml edits across processes.
+// The locks are advisory: they only coordinate processes that use them, such as
+// several mvnx invocations running in parallel on the same project.
+type Locker interface {
+	// Lock acquires the lock for the pom.xml at pomPath, waiting while another
+	// process holds it. Returns a *ConflictError if it cannot be acquired in time.
+	Lock(pomPath string) (Lock, error)
+}
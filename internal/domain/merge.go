@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// VersionStrategy chooses the version of merged duplicate declarations.
+type VersionStrategy string
+
+// Version strategies
+const (
+	// VersionHighest keeps the highest version declared
+	VersionHighest VersionStrategy = "highest"
+
+	// VersionLast keeps the version of the last declaration, the one Maven uses
+	VersionLast VersionStrategy = "last"
+
+	// VersionFirst keeps the version of the first declaration
+	VersionFirst VersionStrategy = "first"
+)
+
+// ScopeStrategy chooses the scope of merged duplicate declarations.
+type ScopeStrategy string
+
+// Scope strategies
+const (
+	// ScopeWidest keeps a scope that puts the dependency on every classpath any
+	// of the declarations puts it on, see WidestScope
+	ScopeWidest ScopeStrategy = "widest"
+
+	// ScopeLast keeps the scope of the last declaration, the one Maven uses
+	ScopeLast ScopeStrategy = "last"
+
+	// ScopeFirst keeps the scope of the first declaration
+	ScopeFirst ScopeStrategy = "first"
+)
+
+// ParseVersionStrategy parses "highest", "last" or "first".
+func ParseVersionStrategy(s string) (VersionStrategy, error) {
+	strategy := VersionStrategy(s)
+	if !slices.Contains([]VersionStrategy{VersionHighest, VersionLast, VersionFirst}, strategy) {
+		return "", &ValidationError{
+			Field:   "version",
+			Message: fmt.Sprintf("invalid version strategy: %s (valid: highest, last, first)", s),
+		}
+	}
+	return strategy, nil
+}
+
+// ParseScopeStrategy parses "widest", "last" or "first".
+func ParseScopeStrategy(s string) (ScopeStrategy, error) {
+	strategy := ScopeStrategy(s)
+	if !slices.Contains([]ScopeStrategy{ScopeWidest, ScopeLast, ScopeFirst}, strategy) {
+		return "", &ValidationError{
+			Field:   "scope",
+			Message: fmt.Sprintf("invalid scope strategy: %s (valid: widest, last, first)", s),
+		}
+	}
+	return strategy, nil
+}
+
+// scopeClasspaths lists the classpaths each mergeable scope puts a dependency on.
+var scopeClasspaths = map[string][]string{
+	"compile":  {"compile", "runtime", "test"},
+	"provided": {"compile", "test"},
+	"runtime":  {"runtime", "test"},
+	"test":     {"test"},
+}
+
+// WidestScope returns the narrowest scope putting a dependency on every classpath
+// one of scopes puts it on; e.g. provided and runtime widen to compile. An empty
+// scope is compile. System and import scopes only merge with themselves, since
+// they change what the declaration means.
+func WidestScope(scopes ...string) (string, error) {
+	var widest string
+	for _, scope := range scopes {
+		if scope == "" {
+			scope = "compile"
+		}
+		switch {
+		case widest == "" || widest == scope:
+			widest = scope
+		case scopeClasspaths[widest] == nil || scopeClasspaths[scope] == nil:
+			return "", &ValidationError{
+				Field:   "scope",
+				Message: fmt.Sprintf("cannot merge %s scope with %s scope", widest, scope),
+			}
+		case covers(widest, scope):
+		case covers(scope, widest):
+			widest = scope
+		default:
+			widest = "compile"
+		}
+	}
+	return widest, nil
+}
+
+// covers reports whether scope a puts a dependency on every classpath b does.
+func covers(a, b string) bool {
+	for _, classpath := range scopeClasspaths[b] {
+		if !slices.Contains(scopeClasspaths[a], classpath) {
+			return false
+		}
+	}
+	return true
+}
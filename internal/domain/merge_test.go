@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidestScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    string
+		wantErr bool
+	}{
+		{name: "same", scopes: []string{"test", "test"}, want: "test"},
+		{name: "compile covers test", scopes: []string{"test", "compile"}, want: "compile"},
+		{name: "runtime covers test", scopes: []string{"test", "runtime"}, want: "runtime"},
+		{name: "provided and runtime", scopes: []string{"provided", "runtime"}, want: "compile"},
+		{name: "empty is compile", scopes: []string{"", "provided"}, want: "compile"},
+		{name: "system", scopes: []string{"system", "system"}, want: "system"},
+		{name: "system and compile", scopes: []string{"compile", "system"}, wantErr: true},
+		{name: "import and compile", scopes: []string{"import", "compile"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WidestScope(tt.scopes...)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseVersionStrategy(t *testing.T) {
+	strategy, err := ParseVersionStrategy("highest")
+	assert.NoError(t, err)
+	assert.Equal(t, VersionHighest, strategy)
+
+	var validationErr *ValidationError
+	_, err = ParseVersionStrategy("newest")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = ParseScopeStrategy("narrowest")
+	assert.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "fmt"
+
+// DefaultParentRelativePath is where Maven looks for a parent pom.xml when
+// <relativePath> is not specified.
+const DefaultParentRelativePath = "../pom.xml"
+
+// Parent is the <parent> reference of a pom.xml.
+type Parent struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+
+	// RelativePath locates the parent on disk, relative to the child's directory.
+	// Empty when the parent must be looked up in a repository only.
+	RelativePath string
+}
+
+// String returns the parent coordinates.
+func (p *Parent) String() string {
+	return fmt.Sprintf("%s:%s:%s", p.GroupID, p.ArtifactID, p.Version)
+}
+
+// Property is an entry of the <properties> of a pom.xml.
+type Property struct {
+	Name  string
+	Value string
+
+	// Position is where the declaration starts in the pom.xml; zero when unknown
+	Position Position
+}
+
+// Position is a 1-based line and column in a file.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// IsZero reports whether the position is unknown.
+func (p Position) IsZero() bool {
+	return p.Line == 0
+}
+
+// String returns the position as line:column, or "" when it is unknown.
+func (p Position) String() string {
+	if p.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// VersionSource tells where the version of a declared dependency comes from.
+type VersionSource string
+
+const (
+	// VersionLiteral is a version written literally in the declaration
+	VersionLiteral VersionSource = "literal"
+
+	// VersionProperty is a ${property} reference in the declaration
+	VersionProperty VersionSource = "property"
+
+	// VersionManaged comes from the project's own <dependencyManagement>
+	VersionManaged VersionSource = "managed"
+
+	// VersionParent is inherited from the <dependencyManagement> of a parent pom
+	VersionParent VersionSource = "parent"
+
+	// VersionBOM comes from a BOM imported in <dependencyManagement>
+	VersionBOM VersionSource = "bom"
+
+	// VersionUnknown means the version is neither declared nor managed anywhere visible
+	VersionUnknown VersionSource = "unknown"
+)
+
+// DeclaredDependency is a dependency as declared in a pom.xml, with its effective version.
+type DeclaredDependency struct {
+	// Dependency holds the declaration; its Version is the raw text, e.g. "${jackson.version}"
+	Dependency *Dependency
+
+	// Managed is true for entries of <dependencyManagement>
+	Managed bool
+
+	// ResolvedVersion is the effective version, or empty when it cannot be determined
+	ResolvedVersion string
+
+	// VersionSource tells where ResolvedVersion comes from
+	VersionSource VersionSource
+
+	// VersionOrigin details the source: the property name, or the coordinates of the parent or BOM
+	VersionOrigin string
+
+	// Problem describes why the declaration is invalid, see Dependency.Validate; empty when it is valid
+	Problem string
+}
+
+// PomLocator finds the pom.xml of an artifact on the local disk, e.g. a parent or an imported BOM.
+type PomLocator interface {
+	// Locate returns the path of the pom.xml of groupId:artifactId:version.
+	// Returns a *NotFoundError if it is not available locally.
+	Locate(groupID, artifactID, version string) (string, error)
+}
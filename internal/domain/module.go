@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultPackaging is the packaging Maven assumes when a pom.xml declares none
+	DefaultPackaging = "jar"
+
+	// AggregatorPackaging is the packaging required of a project that lists <modules>
+	AggregatorPackaging = "pom"
+)
+
+// packagingPattern matches packaging names such as jar, war or maven-plugin
+var packagingPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ModuleSpec describes a module created by mvnx new-module.
+type ModuleSpec struct {
+	// Path is the module directory relative to the aggregator, slash-separated,
+	// as listed in <modules>, e.g. "core" or "services/orders"
+	Path string
+
+	ArtifactID string
+	Packaging  string
+
+	// Parent is the aggregator; its RelativePath leads from the module directory to its pom.xml
+	Parent *Parent
+}
+
+// NewModuleSpec creates a new ModuleSpec with validation.
+// The artifactId defaults to the last element of the path and the packaging to jar.
+// Parent is left for the caller to fill in from the aggregator.
+func NewModuleSpec(modulePath, artifactID, packaging string) (*ModuleSpec, error) {
+	modulePath = path.Clean(strings.ReplaceAll(modulePath, "\\", "/"))
+	if modulePath == "." || path.IsAbs(modulePath) || modulePath == ".." || strings.HasPrefix(modulePath, "../") {
+		return nil, &ValidationError{
+			Field:   "module",
+			Message: fmt.Sprintf("invalid module directory: %s (expected a directory inside the aggregator)", modulePath),
+		}
+	}
+
+	if artifactID == "" {
+		artifactID = path.Base(modulePath)
+	}
+	if packaging == "" {
+		packaging = DefaultPackaging
+	}
+
+	if !coordinatePattern.MatchString(artifactID) {
+		return nil, &ValidationError{Field: "artifactId", Message: fmt.Sprintf("invalid artifactId: %s", artifactID)}
+	}
+	if !packagingPattern.MatchString(packaging) {
+		return nil, &ValidationError{Field: "packaging", Message: fmt.Sprintf("invalid packaging: %s", packaging)}
+	}
+
+	return &ModuleSpec{
+		Path:       modulePath,
+		ArtifactID: artifactID,
+		Packaging:  packaging,
+	}, nil
+}
+
+// HasSources reports whether the module builds code, so it gets the standard
+// source directories; pom modules only aggregate or share configuration.
+func (m *ModuleSpec) HasSources() bool {
+	return m.Packaging != AggregatorPackaging
+}
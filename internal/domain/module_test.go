@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewModuleSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		artifactID     string
+		packaging      string
+		wantPath       string
+		wantArtifactID string
+		wantPackaging  string
+		wantErr        bool
+	}{
+		{name: "defaults", path: "core", wantPath: "core", wantArtifactID: "core", wantPackaging: "jar"},
+		{name: "nested", path: "services/orders/", wantPath: "services/orders", wantArtifactID: "orders", wantPackaging: "jar"},
+		{name: "explicit", path: "web", artifactID: "shop-web", packaging: "war", wantPath: "web", wantArtifactID: "shop-web", wantPackaging: "war"},
+		{name: "windows separators", path: `services\billing`, wantPath: "services/billing", wantArtifactID: "billing", wantPackaging: "jar"},
+		{name: "current directory", path: ".", wantErr: true},
+		{name: "outside the aggregator", path: "../sibling", wantErr: true},
+		{name: "absolute", path: "/tmp/module", wantErr: true},
+		{name: "invalid artifactId", path: "core", artifactID: "my core", wantErr: true},
+		{name: "invalid packaging", path: "core", packaging: "Jar!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := NewModuleSpec(tt.path, tt.artifactID, tt.packaging)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, spec.Path)
+			assert.Equal(t, tt.wantArtifactID, spec.ArtifactID)
+			assert.Equal(t, tt.wantPackaging, spec.Packaging)
+		})
+	}
+}
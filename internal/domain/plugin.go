@@ -0,0 +1,54 @@
+package domain
+
+import "fmt"
+
+// DefaultPluginGroupID is the groupId Maven assumes when a plugin declares none.
+const DefaultPluginGroupID = "org.apache.maven.plugins"
+
+// Plugin represents a build plugin declared in <build><plugins> or <build><pluginManagement>.
+// A plugin is identified by groupId and artifactId.
+type Plugin struct {
+	GroupID    string
+	ArtifactID string
+
+	// Version is the raw text, e.g. "${compiler.version}"; empty when omitted
+	Version string
+
+	// Managed is true for entries of <pluginManagement>
+	Managed bool
+
+	// Position is where the declaration starts in the pom.xml; zero when unknown
+	Position Position
+}
+
+// NewPlugin creates a new Plugin with validation. An empty groupId defaults to DefaultPluginGroupID.
+func NewPlugin(groupID, artifactID, version string) (*Plugin, error) {
+	if groupID == "" {
+		groupID = DefaultPluginGroupID
+	}
+	if artifactID == "" {
+		return nil, &ValidationError{Field: "artifactId", Message: "artifactID cannot be empty"}
+	}
+	if version == "" {
+		return nil, &ValidationError{Field: "version", Message: "version cannot be empty"}
+	}
+
+	return &Plugin{
+		GroupID:    groupID,
+		ArtifactID: artifactID,
+		Version:    version,
+	}, nil
+}
+
+// String returns a formatted string representation of the plugin.
+func (p *Plugin) String() string {
+	if p.Version == "" {
+		return p.Coordinates()
+	}
+	return fmt.Sprintf("%s:%s:%s", p.GroupID, p.ArtifactID, p.Version)
+}
+
+// Coordinates returns the Maven coordinates without version.
+func (p *Plugin) Coordinates() string {
+	return fmt.Sprintf("%s:%s", p.GroupID, p.ArtifactID)
+}
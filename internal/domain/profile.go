@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Activation kinds, named after the children of <activation>.
+const (
+	ActivationDefault  = "activeByDefault"
+	ActivationProperty = "property"
+	ActivationJDK      = "jdk"
+	ActivationOS       = "os"
+	ActivationFile     = "file"
+)
+
+// Profile represents a <profile> of a pom.xml.
+type Profile struct {
+	ID string
+
+	// Activation lists the conditions of <activation>; without any the profile is
+	// only active when selected explicitly, e.g. with -P
+	Activation []ProfileActivation
+}
+
+// ProfileActivation is one condition of a profile's <activation>.
+type ProfileActivation struct {
+	// Kind is one of ActivationDefault, ActivationProperty, ActivationJDK, ActivationOS or ActivationFile
+	Kind string
+
+	// Name is the property name, the OS family or the file path; "!" negates it
+	// for properties and files, e.g. "!skipTests"
+	Name string
+
+	// Value is the property value or the JDK version range, e.g. "[17,)"
+	Value string
+}
+
+// ParseProfileActivation parses an activation condition written as one of
+//
+//	default
+//	property:name[=value]   (!name activates when the property is not set)
+//	jdk:range               (e.g. jdk:17 or jdk:[17,))
+//	os:family               (e.g. os:windows)
+//	file:path               (!path activates when the file is missing)
+func ParseProfileActivation(s string) (ProfileActivation, error) {
+	if s == "default" {
+		return ProfileActivation{Kind: ActivationDefault, Value: "true"}, nil
+	}
+
+	kind, arg, _ := strings.Cut(s, ":")
+	if arg == "" {
+		return ProfileActivation{}, invalidActivation(s)
+	}
+
+	switch kind {
+	case ActivationProperty:
+		name, value, _ := strings.Cut(arg, "=")
+		if name == "" || name == "!" || (strings.HasPrefix(name, "!") && value != "") {
+			return ProfileActivation{}, invalidActivation(s)
+		}
+		return ProfileActivation{Kind: ActivationProperty, Name: name, Value: value}, nil
+	case ActivationJDK:
+		return ProfileActivation{Kind: ActivationJDK, Value: arg}, nil
+	case ActivationOS, ActivationFile:
+		if arg == "!" {
+			return ProfileActivation{}, invalidActivation(s)
+		}
+		return ProfileActivation{Kind: kind, Name: arg}, nil
+	default:
+		return ProfileActivation{}, invalidActivation(s)
+	}
+}
+
+// invalidActivation reports an activation condition ParseProfileActivation does not understand.
+func invalidActivation(s string) error {
+	return &ValidationError{
+		Field: "activation",
+		Message: fmt.Sprintf("invalid activation: %q (expected default, property:name[=value], "+
+			"jdk:range, os:family or file:[!]path)", s),
+	}
+}
+
+// String returns the activation in the syntax of ParseProfileActivation.
+func (a ProfileActivation) String() string {
+	switch a.Kind {
+	case ActivationDefault:
+		return "default"
+	case ActivationProperty:
+		if a.Value != "" {
+			return a.Kind + ":" + a.Name + "=" + a.Value
+		}
+		return a.Kind + ":" + a.Name
+	case ActivationJDK:
+		return a.Kind + ":" + a.Value
+	default:
+		return a.Kind + ":" + a.Name
+	}
+}
+
+// NewProfile creates a new Profile with validation.
+func NewProfile(id string, activation []ProfileActivation) (*Profile, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, &ValidationError{Field: "profile", Message: "profile id cannot be empty"}
+	}
+
+	seen := make(map[string]bool, len(activation))
+	for _, a := range activation {
+		if seen[a.Kind] {
+			return nil, &ValidationError{
+				Field:   "activation",
+				Message: fmt.Sprintf("profile %s can only have one %s activation", id, a.Kind),
+			}
+		}
+		seen[a.Kind] = true
+	}
+
+	return &Profile{ID: id, Activation: activation}, nil
+}
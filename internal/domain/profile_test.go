@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProfileActivation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ProfileActivation
+		wantErr bool
+	}{
+		{input: "default", want: ProfileActivation{Kind: ActivationDefault, Value: "true"}},
+		{input: "property:env=it", want: ProfileActivation{Kind: ActivationProperty, Name: "env", Value: "it"}},
+		{input: "property:ci", want: ProfileActivation{Kind: ActivationProperty, Name: "ci"}},
+		{input: "property:!skipIt", want: ProfileActivation{Kind: ActivationProperty, Name: "!skipIt"}},
+		{input: "property:env=!prod", want: ProfileActivation{Kind: ActivationProperty, Name: "env", Value: "!prod"}},
+		{input: "jdk:[17,)", want: ProfileActivation{Kind: ActivationJDK, Value: "[17,)"}},
+		{input: "os:windows", want: ProfileActivation{Kind: ActivationOS, Name: "windows"}},
+		{input: "file:!src/it", want: ProfileActivation{Kind: ActivationFile, Name: "!src/it"}},
+		{input: "property:", wantErr: true},
+		{input: "property:=it", wantErr: true},
+		{input: "property:!ci=true", wantErr: true},
+		{input: "file:!", wantErr: true},
+		{input: "maven:3.9", wantErr: true},
+		{input: "jdk", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseProfileActivation(tt.input)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	_, err := NewProfile(" ", nil)
+	assert.Error(t, err)
+
+	_, err = NewProfile("it", []ProfileActivation{
+		{Kind: ActivationProperty, Name: "env", Value: "it"},
+		{Kind: ActivationProperty, Name: "ci"},
+	})
+	assert.Error(t, err, "Maven supports a single property condition")
+
+	profile, err := NewProfile("it", []ProfileActivation{{Kind: ActivationJDK, Value: "21"}})
+	require.NoError(t, err)
+	assert.Equal(t, "it", profile.ID)
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Defaults used by mvnx init when no coordinates or Java version are given
+const (
+	DefaultProjectGroupID    = "com.example"
+	DefaultProjectArtifactID = "my-app"
+	DefaultProjectVersion    = "1.0-SNAPSHOT"
+	DefaultJavaVersion       = "17"
+)
+
+// coordinatePattern matches the characters Maven accepts in a groupId or artifactId
+var coordinatePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// javaKeywords are reserved words that cannot be used as package names
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "default": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true, "false": true,
+	"final": true, "finally": true, "float": true, "for": true, "goto": true, "if": true,
+	"implements": true, "import": true, "instanceof": true, "int": true, "interface": true,
+	"long": true, "native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true,
+	"strictfp": true, "super": true, "switch": true, "synchronized": true, "this": true,
+	"throw": true, "throws": true, "transient": true, "true": true, "try": true, "void": true,
+	"volatile": true, "while": true,
+}
+
+// ProjectSpec describes a project created by mvnx init.
+// Project templates are rendered with it, so its fields and methods are part of
+// the template format.
+type ProjectSpec struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+
+	// JavaVersion is the Java release level, e.g. "21"
+	JavaVersion string
+
+	// Package is the base package of the generated sources, e.g. "com.example.myapp"
+	Package string
+}
+
+// NewProjectSpec creates a new ProjectSpec with validation.
+// Empty values are replaced by the defaults; the package defaults to the groupId
+// followed by the artifactId, e.g. "com.example.myapp" for com.example:my-app.
+func NewProjectSpec(groupID, artifactID, javaVersion, pkg string) (*ProjectSpec, error) {
+	if groupID == "" {
+		groupID = DefaultProjectGroupID
+	}
+	if artifactID == "" {
+		artifactID = DefaultProjectArtifactID
+	}
+	if javaVersion == "" {
+		javaVersion = DefaultJavaVersion
+	}
+
+	if !coordinatePattern.MatchString(groupID) {
+		return nil, &ValidationError{Field: "groupId", Message: fmt.Sprintf("invalid groupId: %s", groupID)}
+	}
+	if !coordinatePattern.MatchString(artifactID) {
+		return nil, &ValidationError{Field: "artifactId", Message: fmt.Sprintf("invalid artifactId: %s", artifactID)}
+	}
+
+	// "1.8" is the traditional spelling of Java 8
+	release, err := ParseJavaRelease(javaVersion)
+	if err != nil || release < 8 {
+		return nil, &ValidationError{
+			Field:   "java",
+			Message: fmt.Sprintf("invalid Java version: %s (expected a release such as 17 or 21)", javaVersion),
+		}
+	}
+	javaVersion = strconv.Itoa(release)
+
+	if pkg == "" {
+		pkg = defaultPackage(groupID, artifactID)
+	} else if !isValidPackage(pkg) {
+		return nil, &ValidationError{Field: "package", Message: fmt.Sprintf("invalid Java package: %s", pkg)}
+	}
+
+	return &ProjectSpec{
+		GroupID:     groupID,
+		ArtifactID:  artifactID,
+		Version:     DefaultProjectVersion,
+		JavaVersion: javaVersion,
+		Package:     pkg,
+	}, nil
+}
+
+// PackagePath returns the package as a slash-separated directory path, e.g. "com/example/myapp".
+func (s *ProjectSpec) PackagePath() string {
+	return strings.ReplaceAll(s.Package, ".", "/")
+}
+
+// defaultPackage derives a package name from the coordinates, dropping the characters
+// Java does not accept in identifiers.
+func defaultPackage(groupID, artifactID string) string {
+	var segments []string
+	for _, segment := range strings.Split(groupID+"."+artifactID, ".") {
+		segment = strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, segment)
+
+		if segment == "" {
+			continue
+		}
+		if unicode.IsDigit(rune(segment[0])) || javaKeywords[segment] {
+			segment = "_" + segment
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// isValidPackage reports whether pkg is a valid Java package name.
+func isValidPackage(pkg string) bool {
+	for _, segment := range strings.Split(pkg, ".") {
+		if segment == "" || javaKeywords[segment] {
+			return false
+		}
+		for i, r := range segment {
+			valid := unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))
+			if !valid {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProjectSpec(t *testing.T) {
+	tests := []struct {
+		name                                string
+		groupID, artifactID, java, pkg      string
+		wantPackage, wantJava, wantArtifact string
+		wantErr                             bool
+	}{
+		{name: "defaults", wantPackage: "com.example.myapp", wantJava: "17", wantArtifact: "my-app"},
+		{name: "derived package", groupID: "io.acme-corp", artifactID: "2fa-service", wantPackage: "io.acmecorp._2faservice", wantJava: "17", wantArtifact: "2fa-service"},
+		{name: "keyword segment", groupID: "org.example", artifactID: "native", wantPackage: "org.example._native", wantJava: "17", wantArtifact: "native"},
+		{name: "explicit package", pkg: "com.acme.orders", java: "21", wantPackage: "com.acme.orders", wantJava: "21", wantArtifact: "my-app"},
+		{name: "java 1.8", java: "1.8", wantPackage: "com.example.myapp", wantJava: "8", wantArtifact: "my-app"},
+		{name: "invalid java", java: "seventeen", wantErr: true},
+		{name: "java too old", java: "7", wantErr: true},
+		{name: "invalid package", pkg: "com.acme.new", wantErr: true},
+		{name: "invalid artifactId", artifactID: "my app", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := NewProjectSpec(tt.groupID, tt.artifactID, tt.java, tt.pkg)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPackage, spec.Package)
+			assert.Equal(t, tt.wantJava, spec.JavaVersion)
+			assert.Equal(t, tt.wantArtifact, spec.ArtifactID)
+			assert.Equal(t, DefaultProjectVersion, spec.Version)
+		})
+	}
+}
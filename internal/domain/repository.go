@@ -6,18 +6,115 @@ type PomRepository interface {
 	Load(path string) error
 
 	// AddDependency adds or updates a dependency in the pom.xml.
-	// If the dependency already exists (same groupId:artifactId), it updates the version.
+	// If the dependency already exists (same Key), it updates the version, scope and optional flag.
+	// Dependencies with a Profile go to the <dependencies> of that profile, which must exist,
+	// and Managed ones to <dependencyManagement>, which is created if needed.
+	// The other dependency methods select the section the same way.
 	AddDependency(dep *Dependency) error
 
-	// RemoveDependency removes a dependency by artifactId.
-	RemoveDependency(artifactID string) error
+	// RemoveDependency removes the dependency with the same Key as dep.
+	RemoveDependency(dep *Dependency) error
 
-	// HasDependency checks if a dependency exists by groupId and artifactId.
-	HasDependency(groupID, artifactID string) bool
+	// AddExclusion adds an exclusion to the dependency with the same Key as dep.
+	AddExclusion(dep *Dependency, exclusion Exclusion) error
+
+	// RemoveExclusion removes an exclusion from the dependency with the same Key as dep.
+	RemoveExclusion(dep *Dependency, exclusion Exclusion) error
+
+	// MergeDependencies rewrites the first declaration of the artifact of merged in
+	// its section with the version, scope, optional flag, systemPath and exclusions
+	// of merged, and removes the other declarations. Returns how many were removed,
+	// or a *NotFoundError if the artifact is not declared.
+	MergeDependencies(merged *Dependency) (int, error)
+
+	// HasDependency checks if a dependency with the same Key as dep exists.
+	HasDependency(dep *Dependency) bool
 
 	// Save writes the pom.xml back to disk, preserving formatting.
+	// It refuses to overwrite a file that changed on disk since Load.
 	Save() error
 
+	// Render returns the pom.xml contents exactly as Save would write them.
+	Render() ([]byte, error)
+
 	// GetDependencies returns all dependencies in the pom.xml.
+	// Versions are returned as written, e.g. "${jackson.version}", and are empty
+	// for dependencies whose version is managed by a parent or BOM.
 	GetDependencies() ([]*Dependency, error)
+
+	// GetManagedDependencies returns the entries of <dependencyManagement>, including
+	// imported BOMs, with Managed set.
+	GetManagedDependencies() ([]*Dependency, error)
+
+	// GetProfiles returns the <profiles> of the pom.xml with their activation.
+	GetProfiles() ([]*Profile, error)
+
+	// AddProfile adds a profile with its activation to <profiles>, creating the
+	// section if needed. Returns a *ConflictError if the profile already exists.
+	AddProfile(profile *Profile) error
+
+	// GetProfileDependencies returns the <dependencies> of the profile with the given id,
+	// with Profile set. Returns a *NotFoundError if the profile does not exist.
+	GetProfileDependencies(id string) ([]*Dependency, error)
+
+	// GetPlugins returns the plugins of <build><plugins> followed by those of
+	// <build><pluginManagement>, with Managed set on the latter.
+	GetPlugins() ([]*Plugin, error)
+
+	// AddPlugin adds a plugin to <build><plugins>, or to <build><pluginManagement> if
+	// plugin.Managed is set, creating the sections as needed. If the plugin already
+	// exists there (same groupId:artifactId), only its version is updated.
+	AddPlugin(plugin *Plugin) error
+
+	// RemovePlugin removes the plugin with the same groupId:artifactId from the
+	// section selected by plugin.Managed.
+	RemovePlugin(plugin *Plugin) error
+
+	// GetModules returns the entries of <modules> as written, e.g. "core" or "services/orders".
+	GetModules() ([]string, error)
+
+	// AddModule adds a <module> to <modules>, creating the section if needed.
+	// A module that is already listed is left as is.
+	AddModule(module string) error
+
+	// GetPackaging returns the <packaging> of the project, DefaultPackaging if omitted.
+	GetPackaging() (string, error)
+
+	// SetPackaging sets the <packaging> of the project, adding the element if needed.
+	SetPackaging(packaging string) error
+
+	// GetJavaDeclarations returns the places where the Java release level is set: the
+	// properties in JavaProperties, the <source>, <target> and <release> of
+	// maven-compiler-plugin and the JDK version of maven-toolchains-plugin.
+	GetJavaDeclarations() ([]*JavaDeclaration, error)
+
+	// SetJavaDeclaration sets the value of the declaration at location, see JavaDeclaration.Location.
+	SetJavaDeclaration(location, value string) error
+
+	// SetProperty sets a property in <properties>, creating the element and the section if needed.
+	SetProperty(name, value string) error
+
+	// RemoveProperty removes a property from <properties>, and the section once it is empty.
+	// Returns a *NotFoundError if the property is not declared.
+	RemoveProperty(name string) error
+
+	// GetPropertyDeclarations returns the entries of <properties> in document order.
+	// Unlike GetProperties, it does not include the project.* model properties.
+	GetPropertyDeclarations() ([]*Property, error)
+
+	// Format reorders the <project> elements as the POM reference lists them, sorts
+	// the dependencies of the project, its <dependencyManagement> and its profiles
+	// by keys (none keeps their order) and normalizes the indentation. Comments
+	// move with the element they describe. Call Save to write the result.
+	Format(keys []DependencySortKey) error
+
+	// GetParent returns the <parent> of the pom.xml, or nil if it has none.
+	GetParent() (*Parent, error)
+
+	// GetLicenses returns the <licenses> of the pom.xml, with their SPDX
+	// identifiers; empty if it declares none.
+	GetLicenses() ([]*License, error)
+
+	// GetProperties returns the <properties> of the pom.xml and the project.* model properties.
+	GetProperties() (map[string]string, error)
 }
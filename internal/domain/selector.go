@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DependencySelector identifies dependencies of a project by their coordinates.
+// Every part may be a glob pattern (*, ? and [...]), e.g. "org.junit.*:*".
+// Empty parts match anything.
+type DependencySelector struct {
+	// GroupID is empty when only an artifactId was given, matching any group
+	GroupID string
+
+	ArtifactID string
+
+	// Type, Classifier and Version are empty unless given in the
+	// groupId:artifactId[:type[:classifier]]:version form
+	Type       string
+	Classifier string
+	Version    string
+}
+
+// ParseDependencySelector parses "artifactId", "groupId:artifactId", or the longer
+// forms accepted by ParseCoordinates, e.g. "io.netty:netty-transport-native-epoll:jar:linux-x86_64:*".
+// In the longer forms, type, classifier and version may be left empty to match any.
+func ParseDependencySelector(s string) (*DependencySelector, error) {
+	parts := strings.Split(s, ":")
+
+	var selector *DependencySelector
+	switch len(parts) {
+	case 1:
+		selector = &DependencySelector{ArtifactID: parts[0]}
+	case 2, 3, 4, 5:
+		if parts[0] == "" {
+			return nil, &ValidationError{Field: "groupId", Message: fmt.Sprintf("missing groupId in %q", s)}
+		}
+		selector = &DependencySelector{GroupID: parts[0], ArtifactID: parts[1]}
+		switch len(parts) {
+		case 3:
+			selector.Version = parts[2]
+		case 4:
+			selector.Type, selector.Version = parts[2], parts[3]
+		case 5:
+			selector.Type, selector.Classifier, selector.Version = parts[2], parts[3], parts[4]
+		}
+	default:
+		return nil, &ValidationError{
+			Field:   "dependency",
+			Message: fmt.Sprintf("invalid dependency %q (expected artifactId or groupId:artifactId[:type[:classifier]][:version])", s),
+		}
+	}
+
+	if selector.ArtifactID == "" {
+		return nil, &ValidationError{Field: "artifactId", Message: fmt.Sprintf("missing artifactId in %q", s)}
+	}
+
+	for _, pattern := range selector.parts() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &ValidationError{Field: "dependency", Message: fmt.Sprintf("invalid pattern %q", s)}
+		}
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether the dependency is selected.
+func (s *DependencySelector) Matches(dep *Dependency) bool {
+	if !s.MatchesCoordinates(dep.GroupID, dep.ArtifactID) {
+		return false
+	}
+	return matchOptional(s.Type, dep.EffectiveType()) &&
+		matchOptional(s.Classifier, dep.Classifier) &&
+		matchOptional(s.Version, dep.Version)
+}
+
+// MatchesCoordinates reports whether groupId:artifactId is selected.
+func (s *DependencySelector) MatchesCoordinates(groupID, artifactID string) bool {
+	if s.GroupID != "" && !matchGlob(s.GroupID, groupID) {
+		return false
+	}
+	return matchGlob(s.ArtifactID, artifactID)
+}
+
+// IsPattern reports whether the selector contains wildcards.
+func (s *DependencySelector) IsPattern() bool {
+	return strings.ContainsAny(strings.Join(s.parts(), ""), "*?[")
+}
+
+// String returns the selector in the form it was parsed from.
+func (s *DependencySelector) String() string {
+	switch {
+	case s.GroupID == "":
+		return s.ArtifactID
+	case s.Classifier != "":
+		return strings.Join([]string{s.GroupID, s.ArtifactID, s.Type, s.Classifier, s.Version}, ":")
+	case s.Type != "":
+		return strings.Join([]string{s.GroupID, s.ArtifactID, s.Type, s.Version}, ":")
+	case s.Version != "":
+		return strings.Join([]string{s.GroupID, s.ArtifactID, s.Version}, ":")
+	default:
+		return s.GroupID + ":" + s.ArtifactID
+	}
+}
+
+// parts returns the patterns of the selector.
+func (s *DependencySelector) parts() []string {
+	return []string{s.GroupID, s.ArtifactID, s.Type, s.Classifier, s.Version}
+}
+
+// matchOptional reports whether name matches the glob pattern; an empty pattern matches anything.
+func matchOptional(pattern, name string) bool {
+	return pattern == "" || matchGlob(pattern, name)
+}
+
+// matchGlob reports whether name matches the glob pattern.
+// Patterns are validated by ParseDependencySelector, so errors cannot occur.
+func matchGlob(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDependencySelector(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *DependencySelector
+		wantErr bool
+	}{
+		{input: "lombok", want: &DependencySelector{ArtifactID: "lombok"}},
+		{input: "org.projectlombok:lombok", want: &DependencySelector{GroupID: "org.projectlombok", ArtifactID: "lombok"}},
+		{input: "org.junit.*:*", want: &DependencySelector{GroupID: "org.junit.*", ArtifactID: "*"}},
+		{input: "", wantErr: true},
+		{input: ":lombok", wantErr: true},
+		{input: "org.projectlombok:", wantErr: true},
+		{input: "org.projectlombok:lombok:1.18.30", want: &DependencySelector{GroupID: "org.projectlombok", ArtifactID: "lombok", Version: "1.18.30"}},
+		{input: "org.junit.jupiter:junit-jupiter:test-jar:*", want: &DependencySelector{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter", Type: "test-jar", Version: "*"}},
+		{input: "io.netty:netty-transport-native-epoll::linux-x86_64:", want: &DependencySelector{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Classifier: "linux-x86_64"}},
+		{input: "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.100.Final:extra", wantErr: true},
+		{input: "org.junit.[:*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDependencySelector(tt.input)
+			if tt.wantErr {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.input, got.String())
+		})
+	}
+}
+
+func TestDependencySelector_Matches(t *testing.T) {
+	jacksonCore := &Dependency{GroupID: "com.fasterxml.jackson.core", ArtifactID: "jackson-core"}
+	shadedCore := &Dependency{GroupID: "org.example.shaded", ArtifactID: "jackson-core"}
+	junitApi := &Dependency{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter-api"}
+	epoll := &Dependency{GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Version: "4.1.100.Final", Classifier: "linux-x86_64"}
+
+	tests := []struct {
+		selector string
+		want     []*Dependency
+	}{
+		{selector: "jackson-core", want: []*Dependency{jacksonCore, shadedCore}},
+		{selector: "com.fasterxml.jackson.core:jackson-core", want: []*Dependency{jacksonCore}},
+		{selector: "org.junit.*:*", want: []*Dependency{junitApi}},
+		{selector: "*:jackson-*", want: []*Dependency{jacksonCore, shadedCore}},
+		{selector: "junit", want: nil},
+		{selector: "io.netty:netty-transport-native-epoll", want: []*Dependency{epoll}},
+		{selector: "io.netty:netty-transport-native-epoll:jar:linux-x86_64:*", want: []*Dependency{epoll}},
+		{selector: "io.netty:netty-transport-native-epoll:jar:linux-aarch_64:*", want: nil},
+		{selector: "io.netty:*:4.1.100.Final", want: []*Dependency{epoll}},
+		{selector: "*:*:test-jar:*", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseDependencySelector(tt.selector)
+			require.NoError(t, err)
+
+			var got []*Dependency
+			for _, dep := range []*Dependency{jacksonCore, shadedCore, junitApi, epoll} {
+				if selector.Matches(dep) {
+					got = append(got, dep)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DependencySortKey is a field dependencies are sorted by.
+type DependencySortKey string
+
+// Dependency sort keys
+const (
+	SortByScope      DependencySortKey = "scope"
+	SortByGroupID    DependencySortKey = "groupId"
+	SortByArtifactID DependencySortKey = "artifactId"
+)
+
+// DefaultDependencySortKeys sorts dependencies by scope, then by coordinates.
+var DefaultDependencySortKeys = []DependencySortKey{SortByScope, SortByGroupID, SortByArtifactID}
+
+// ParseDependencySortKeys parses a comma-separated list of sort keys, e.g.
+// "scope,groupId,artifactId". "none" returns no keys, leaving dependencies in place.
+func ParseDependencySortKeys(s string) ([]DependencySortKey, error) {
+	if s == "none" {
+		return nil, nil
+	}
+
+	valid := []DependencySortKey{SortByScope, SortByGroupID, SortByArtifactID}
+	var keys []DependencySortKey
+	for _, part := range strings.Split(s, ",") {
+		key := DependencySortKey(strings.TrimSpace(part))
+		if !slices.Contains(valid, key) {
+			return nil, &ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("invalid sort key: %s (valid: scope, groupId, artifactId, or none)", key),
+			}
+		}
+		if slices.Contains(keys, key) {
+			return nil, &ValidationError{Field: "sort", Message: fmt.Sprintf("duplicate sort key: %s", key)}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// CompareDependencies orders two dependencies by keys, returning a negative number
+// when a comes first, a positive one when b does, and 0 when the keys tie.
+// Scopes are ordered as in Scopes. BOM imports come before everything else and
+// always tie with each other, since the first import of a managed artifact wins.
+func CompareDependencies(a, b *Dependency, keys []DependencySortKey) int {
+	switch {
+	case a.IsImport() && b.IsImport():
+		return 0
+	case a.IsImport():
+		return -1
+	case b.IsImport():
+		return 1
+	}
+
+	for _, key := range keys {
+		var c int
+		switch key {
+		case SortByScope:
+			c = scopeRank(a.Scope) - scopeRank(b.Scope)
+		case SortByGroupID:
+			c = strings.Compare(a.GroupID, b.GroupID)
+		case SortByArtifactID:
+			c = strings.Compare(a.ArtifactID, b.ArtifactID)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// scopeRank returns the position of scope in Scopes; an empty scope is compile
+// and unknown scopes come last.
+func scopeRank(scope string) int {
+	if scope == "" {
+		return 0
+	}
+	if i := slices.Index(Scopes, scope); i >= 0 {
+		return i
+	}
+	return len(Scopes)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDependencySortKeys(t *testing.T) {
+	keys, err := ParseDependencySortKeys("scope, groupId,artifactId")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDependencySortKeys, keys)
+
+	keys, err = ParseDependencySortKeys("none")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	var validationErr *ValidationError
+	_, err = ParseDependencySortKeys("version")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = ParseDependencySortKeys("groupId,groupId")
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCompareDependencies(t *testing.T) {
+	guava := &Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Scope: "compile"}
+	junit := &Dependency{GroupID: "org.junit.jupiter", ArtifactID: "junit-jupiter", Scope: "test"}
+	assertj := &Dependency{GroupID: "org.assertj", ArtifactID: "assertj-core", Scope: "test"}
+	lombok := &Dependency{GroupID: "org.projectlombok", ArtifactID: "lombok", Scope: "provided"}
+	bom := &Dependency{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-dependencies", Type: "pom", Scope: "import"}
+	otherBom := &Dependency{GroupID: "io.netty", ArtifactID: "netty-bom", Type: "pom", Scope: "import"}
+
+	tests := []struct {
+		name string
+		a, b *Dependency
+		keys []DependencySortKey
+		want int
+	}{
+		{name: "scope first", a: lombok, b: assertj, keys: DefaultDependencySortKeys, want: -1},
+		{name: "then groupId", a: junit, b: assertj, keys: DefaultDependencySortKeys, want: 1},
+		{name: "groupId only", a: guava, b: lombok, keys: []DependencySortKey{SortByGroupID}, want: -1},
+		{name: "artifactId only", a: junit, b: lombok, keys: []DependencySortKey{SortByArtifactID}, want: -1},
+		{name: "no keys", a: junit, b: guava, want: 0},
+		{name: "imports first", a: guava, b: bom, keys: DefaultDependencySortKeys, want: 1},
+		{name: "imports keep their order", a: bom, b: otherBom, keys: DefaultDependencySortKeys, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareDependencies(tt.a, tt.b, tt.keys)
+			switch {
+			case tt.want < 0:
+				assert.Negative(t, got)
+			case tt.want > 0:
+				assert.Positive(t, got)
+			default:
+				assert.Zero(t, got)
+			}
+		})
+	}
+}
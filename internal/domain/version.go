@@ -0,0 +1,250 @@
+package domain
+
+import (
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// qualifiers lists the well-known version qualifiers in the order Maven sorts them.
+// The empty qualifier is a release; unknown qualifiers sort after all of them,
+// alphabetically.
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// qualifierAliases maps qualifiers to the well-known one they stand for.
+var qualifierAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// versionItem is a component of a version: a number, a qualifier, or a list of
+// items started by '-' or by a transition between digits and letters.
+type versionItem struct {
+	kind      versionItemKind
+	number    *big.Int
+	qualifier string
+	list      []versionItem
+}
+
+// versionItemKind tells which field of a versionItem is set.
+type versionItemKind int
+
+const (
+	numberItem versionItemKind = iota
+	qualifierItem
+	listItem
+)
+
+// CompareVersions compares two Maven versions, returning -1, 0 or 1, with the
+// ordering of Maven's ComparableVersion: numeric components compare as numbers,
+// "1.0" equals "1.0.0", and pre-release qualifiers sort before the release, e.g.
+// 1.0-alpha < 1.0-rc1 < 1.0-SNAPSHOT < 1.0 < 1.0-sp1. Components after '-' form a
+// sublist that sorts before a numeric component, so 1-1 < 1.1.
+func CompareVersions(a, b string) int {
+	return compareItems(parseVersion(a), parseVersion(b))
+}
+
+// IsPreRelease reports whether a version is an alpha, beta, milestone or release
+// candidate, e.g. "2.0.0-M1", "1.0.0-beta.2" or "6.0.0.CR1". Snapshots are not
+// pre-releases in this sense; see IsSnapshot.
+func IsPreRelease(version string) bool {
+	return parseVersion(version).hasQualifier(func(qualifier string) bool {
+		i := slices.Index(qualifiers, qualifier)
+		return i >= 0 && i < slices.Index(qualifiers, "snapshot")
+	})
+}
+
+// hasQualifier reports whether the item, or one of its items, is a qualifier matching f.
+func (v *versionItem) hasQualifier(f func(qualifier string) bool) bool {
+	switch v.kind {
+	case qualifierItem:
+		return f(v.qualifier)
+	case listItem:
+		for i := range v.list {
+			if v.list[i].hasQualifier(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsSnapshot reports whether a version is a development snapshot, e.g. "1.2.0-SNAPSHOT".
+func IsSnapshot(version string) bool {
+	return strings.HasSuffix(strings.ToUpper(strings.TrimSpace(version)), "SNAPSHOT")
+}
+
+// parseVersion parses a version the way ComparableVersion does: '.' separates
+// items, while '-' and transitions between digits and letters start a sublist.
+// Trailing items that do not change the ordering, such as ".0" or "-final", are
+// dropped from each list.
+func parseVersion(version string) *versionItem {
+	root := &versionItem{kind: listItem}
+	list := root
+	stack := []*versionItem{root}
+
+	// appendList starts a sublist of the current list, made current
+	appendList := func() {
+		list.list = append(list.list, versionItem{kind: listItem})
+		list = &list.list[len(list.list)-1]
+		stack = append(stack, list)
+	}
+
+	runes := []rune(strings.ToLower(strings.TrimSpace(version)))
+	isDigit, start := false, 0
+	for i, r := range runes {
+		switch {
+		case r == '.' || r == '-':
+			if i == start {
+				list.list = append(list.list, versionItem{kind: numberItem, number: new(big.Int)})
+			} else {
+				list.list = append(list.list, newVersionItem(isDigit, false, string(runes[start:i])))
+			}
+			start = i + 1
+			if r == '-' {
+				appendList()
+			}
+		case unicode.IsDigit(r):
+			if !isDigit && i > start {
+				list.list = append(list.list, newVersionItem(false, true, string(runes[start:i])))
+				start = i
+				appendList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.list = append(list.list, newVersionItem(true, false, string(runes[start:i])))
+				start = i
+				appendList()
+			}
+			isDigit = false
+		}
+	}
+	if len(runes) > start {
+		list.list = append(list.list, newVersionItem(isDigit, false, string(runes[start:])))
+	}
+
+	// Sublists are normalized before the lists containing them
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return root
+}
+
+// newVersionItem creates a numeric or qualifier item. A single letter followed
+// by a digit is short for a qualifier, e.g. "m1" for milestone 1.
+func newVersionItem(numeric, followedByDigit bool, token string) versionItem {
+	if numeric {
+		number, ok := new(big.Int).SetString(token, 10)
+		if ok {
+			return versionItem{kind: numberItem, number: number}
+		}
+	}
+	if followedByDigit && len(token) == 1 {
+		switch token {
+		case "a":
+			token = "alpha"
+		case "b":
+			token = "beta"
+		case "m":
+			token = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[token]; ok {
+		token = alias
+	}
+	return versionItem{kind: qualifierItem, qualifier: token}
+}
+
+// normalize drops the trailing null items of a list, looking past sublists.
+func (v *versionItem) normalize() {
+	for i := len(v.list) - 1; i >= 0; i-- {
+		item := v.list[i]
+		if item.isNull() {
+			v.list = append(v.list[:i], v.list[i+1:]...)
+		} else if item.kind != listItem {
+			break
+		}
+	}
+}
+
+// isNull reports whether an item is equivalent to a missing one.
+func (v versionItem) isNull() bool {
+	switch v.kind {
+	case numberItem:
+		return v.number.Sign() == 0
+	case qualifierItem:
+		return v.qualifier == ""
+	default:
+		return len(v.list) == 0
+	}
+}
+
+// compareItems compares two version items; nil stands for a missing item.
+func compareItems(l, r *versionItem) int {
+	if l == nil {
+		if r == nil {
+			return 0
+		}
+		return -compareItems(r, nil)
+	}
+
+	switch l.kind {
+	case numberItem:
+		switch {
+		case r == nil:
+			return l.number.Sign()
+		case r.kind == numberItem:
+			return l.number.Cmp(r.number)
+		default:
+			return 1
+		}
+	case qualifierItem:
+		switch {
+		case r == nil:
+			return compareQualifiers(l.qualifier, "")
+		case r.kind == qualifierItem:
+			return compareQualifiers(l.qualifier, r.qualifier)
+		default:
+			return -1
+		}
+	}
+
+	switch {
+	case r == nil:
+		if len(l.list) == 0 {
+			return 0
+		}
+		return compareItems(&l.list[0], nil)
+	case r.kind == numberItem:
+		return -1
+	case r.kind == qualifierItem:
+		return 1
+	}
+	for i := 0; i < len(l.list) || i < len(r.list); i++ {
+		var left, right *versionItem
+		if i < len(l.list) {
+			left = &l.list[i]
+		}
+		if i < len(r.list) {
+			right = &r.list[i]
+		}
+		if c := compareItems(left, right); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareQualifiers compares two qualifiers by their position in qualifiers;
+// unknown ones come last, alphabetically.
+func compareQualifiers(l, r string) int {
+	return strings.Compare(comparableQualifier(l), comparableQualifier(r))
+}
+
+// comparableQualifier returns a string ordering qualifiers as Maven does.
+func comparableQualifier(qualifier string) string {
+	if i := slices.Index(qualifiers, qualifier); i >= 0 {
+		return strconv.Itoa(i)
+	}
+	return strconv.Itoa(len(qualifiers)) + "-" + qualifier
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0", b: "1.0.0", want: 0},
+		{a: "1.0.Final", b: "1.0", want: 0},
+		{a: "1.2", b: "1.10", want: -1},
+		{a: "3.11.0", b: "3.9.6", want: 1},
+		{a: "1.0-alpha", b: "1.0-beta", want: -1},
+		{a: "1.0-rc1", b: "1.0", want: -1},
+		{a: "1.0-SNAPSHOT", b: "1.0-rc2", want: 1},
+		{a: "1.0-SNAPSHOT", b: "1.0", want: -1},
+		{a: "1.0-sp1", b: "1.0", want: 1},
+		{a: "4.0.0-beta-1", b: "3.13.0", want: 1},
+		{a: "1.0.1", b: "1.0-rc1", want: 1},
+		{a: "33.0-jre", b: "32.1.3-jre", want: 1},
+		{a: "2.0.13", b: "2.0.13", want: 0},
+		{a: "1-1", b: "1.1", want: -1},
+		{a: "2.9.10-1", b: "2.9.10.1", want: -1},
+		{a: "2.9.10.1", b: "2.9.10", want: 1},
+		{a: "1.0.0.CR1", b: "1.0.0", want: -1},
+		{a: "2.0.0.M1", b: "2.0.0-alpha", want: 1},
+		{a: "20240101120000", b: "9", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a))
+		})
+	}
+}
+
+func TestIsPreRelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "2.0.0-M1", want: true},
+		{version: "1.0.0-beta.2", want: true},
+		{version: "6.0.0.CR1", want: true},
+		{version: "5.11.0-RC1", want: true},
+		{version: "1.0-alpha-3", want: true},
+		{version: "1.0.0-SNAPSHOT", want: false},
+		{version: "33.2.1-jre", want: false},
+		{version: "6.5.2.Final", want: false},
+		{version: "1.0-sp1", want: false},
+		{version: "2.17.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPreRelease(tt.version))
+		})
+	}
+}
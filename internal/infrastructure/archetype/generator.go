@@ -0,0 +1,261 @@
+// Package archetype generates projects from Maven archetype jars without a JVM.
+//
+// It reads META-INF/maven/archetype-metadata.xml, selects the files of
+// archetype-resources with its filesets, and renders filtered files with the
+// Velocity subset described in velocity.go.
+package archetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+// placeholderPattern matches __property__ placeholders in file and directory names
+var placeholderPattern = regexp.MustCompile(`__([A-Za-z][A-Za-z0-9_.-]*?)__`)
+
+// Generate renders the project described by an archetype jar.
+// properties holds groupId, artifactId, version and package, plus values for the
+// archetype's required properties; defaults from the descriptor fill in the rest.
+// Files are returned sorted by path.
+func Generate(jar []byte, properties map[string]string) ([]fs.ProjectFile, error) {
+	archive, err := zip.NewReader(bytes.NewReader(jar), int64(len(jar)))
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "archetype", Message: fmt.Sprintf("invalid archetype jar: %v", err)}
+	}
+
+	contents := make(map[string][]byte)
+	executable := make(map[string]bool)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from archetype: %w", file.Name, err)
+		}
+		contents[file.Name] = data
+		executable[file.Name] = file.Mode().Perm()&0111 != 0
+	}
+
+	descriptor, ok := contents[metadataPath]
+	if !ok {
+		return nil, &domain.ValidationError{
+			Field:   "archetype",
+			Message: "archetype has no " + metadataPath + " (archetypes in the old archetype.xml format are not supported)",
+		}
+	}
+	md, err := parseMetadata(descriptor)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "archetype", Message: fmt.Sprintf("invalid %s: %v", metadataPath, err)}
+	}
+
+	vars, err := resolveProperties(md, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{contents: contents, executable: executable, used: make(map[string]bool)}
+	if err := g.generate("", "", md.FileSets, md.Modules, vars); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(g.files, func(i, j int) bool { return g.files[i].Path < g.files[j].Path })
+	return g.files, nil
+}
+
+// resolveProperties combines the given properties with the defaults of the
+// descriptor and the properties archetypes expect to be set implicitly.
+func resolveProperties(md *metadata, properties map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(properties)+4)
+	for k, v := range properties {
+		vars[k] = v
+	}
+	if _, ok := vars["rootArtifactId"]; !ok {
+		vars["rootArtifactId"] = vars["artifactId"]
+	}
+	vars["packageInPathFormat"] = strings.ReplaceAll(vars["package"], ".", "/")
+
+	var missing []string
+	for _, prop := range md.RequiredProperties {
+		value, ok := vars[prop.Key]
+		if !ok {
+			if prop.DefaultValue == nil {
+				missing = append(missing, prop.Key)
+				continue
+			}
+			// Defaults may refer to other properties, e.g. ${groupId}
+			rendered, err := renderVelocity("defaultValue of "+prop.Key, *prop.DefaultValue, vars)
+			if err != nil {
+				return nil, &domain.ValidationError{Field: "archetype", Message: err.Error()}
+			}
+			value = rendered
+			vars[prop.Key] = value
+		}
+
+		if prop.ValidationRegex != "" {
+			re, err := regexp.Compile("^(?:" + prop.ValidationRegex + ")$")
+			if err == nil && !re.MatchString(value) {
+				return nil, &domain.ValidationError{
+					Field:   prop.Key,
+					Message: fmt.Sprintf("invalid value for %s: %q (must match %s)", prop.Key, value, prop.ValidationRegex),
+				}
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &domain.ValidationError{
+			Field:   "property",
+			Message: fmt.Sprintf("the archetype requires %s; set them with --property key=value", strings.Join(missing, ", ")),
+		}
+	}
+
+	return vars, nil
+}
+
+// generator collects the files of a project and its modules.
+type generator struct {
+	contents map[string][]byte
+	files    []fs.ProjectFile
+
+	// executable holds the archetype files with an exec bit, such as mvnw
+	executable map[string]bool
+
+	// used holds the archetype files already generated, so a file selected by
+	// several filesets, or a module pom.xml under a parent fileset, is rendered once
+	used map[string]bool
+}
+
+// generate renders the pom.xml, filesets and modules found under the archetype
+// directory src into the project directory dst.
+func (g *generator) generate(src, dst string, fileSets []fileSet, modules []module, vars map[string]string) error {
+	base := path.Join(resourcesDir, src)
+
+	// The pom.xml of each (sub)project is always filtered
+	pomName := path.Join(base, "pom.xml")
+	if pom, ok := g.contents[pomName]; ok {
+		g.used[pomName] = true
+		if err := g.add(path.Join(dst, "pom.xml"), pom, true, 0, vars); err != nil {
+			return err
+		}
+	}
+
+	// Module poms are generated with their module, not by a fileset of the parent
+	for _, m := range modules {
+		g.used[path.Join(base, moduleDir(m), "pom.xml")] = true
+	}
+
+	for _, set := range fileSets {
+		dir := path.Join(base, set.Directory)
+		for _, name := range sortedNames(g.contents) {
+			if g.used[name] || !strings.HasPrefix(name, dir+"/") {
+				continue
+			}
+			rel := strings.TrimPrefix(name, dir+"/")
+			if !set.matches(rel) {
+				continue
+			}
+
+			target := path.Join(dst, set.Directory, rel)
+			if set.Packaged {
+				target = path.Join(dst, set.Directory, vars["packageInPathFormat"], rel)
+			}
+			g.used[name] = true
+			var mode os.FileMode
+			if g.executable[name] {
+				mode = 0755
+			}
+			if err := g.add(target, g.contents[name], set.Filtered, mode, vars); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, m := range modules {
+		moduleVars := make(map[string]string, len(vars))
+		for k, v := range vars {
+			moduleVars[k] = v
+		}
+		// Module ids are written like "${rootArtifactId}-api" or "__rootArtifactId__-api"
+		id, err := renderVelocity("module id", substitutePlaceholders(m.ID, vars), vars)
+		if err != nil {
+			return &domain.ValidationError{Field: "archetype", Message: err.Error()}
+		}
+		moduleVars["artifactId"] = id
+
+		dir := moduleDir(m)
+		if err := g.generate(path.Join(src, dir), path.Join(dst, substitutePlaceholders(dir, vars)), m.FileSets, m.Modules, moduleVars); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moduleDir returns the directory of a module inside archetype-resources.
+func moduleDir(m module) string {
+	if m.Dir != "" {
+		return m.Dir
+	}
+	return m.ID
+}
+
+// add renders a file, if filtered, and adds it to the project with the given mode.
+func (g *generator) add(target string, content []byte, filtered bool, mode os.FileMode, vars map[string]string) error {
+	if filtered {
+		rendered, err := renderVelocity(target, string(content), vars)
+		if err != nil {
+			return &domain.ValidationError{Field: "archetype", Message: fmt.Sprintf("invalid archetype template: %v", err)}
+		}
+		content = []byte(rendered)
+	}
+
+	target = path.Clean(substitutePlaceholders(target, vars))
+	if path.IsAbs(target) || target == ".." || strings.HasPrefix(target, "../") {
+		return &domain.ValidationError{Field: "archetype", Message: fmt.Sprintf("archetype file %s is outside the project", target)}
+	}
+
+	g.files = append(g.files, fs.ProjectFile{Path: target, Content: content, Mode: mode})
+	return nil
+}
+
+// substitutePlaceholders replaces __property__ placeholders in a path.
+// Unknown properties are left as they are.
+func substitutePlaceholders(name string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(name, func(match string) string {
+		if value, ok := vars[match[2:len(match)-2]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// sortedNames returns the keys of contents in order, so output is deterministic.
+func sortedNames(contents map[string][]byte) []string {
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readZipFile returns the contents of a file in a zip archive.
+func readZipFile(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
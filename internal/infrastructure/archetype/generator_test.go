@@ -0,0 +1,156 @@
+package archetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const testMetadata = `<archetype-descriptor name="service">
+  <requiredProperties>
+    <requiredProperty key="serviceName">
+      <defaultValue>${artifactId}-service</defaultValue>
+    </requiredProperty>
+    <requiredProperty key="port">
+      <validationRegex>[0-9]+</validationRegex>
+    </requiredProperty>
+  </requiredProperties>
+  <fileSets>
+    <fileSet filtered="true" packaged="true">
+      <directory>src/main/java</directory>
+      <includes>
+        <include>**/*.java</include>
+      </includes>
+    </fileSet>
+    <fileSet filtered="false">
+      <directory>src/main/resources</directory>
+      <excludes>
+        <exclude>**/*.bak</exclude>
+      </excludes>
+    </fileSet>
+  </fileSets>
+  <modules>
+    <module id="${rootArtifactId}-api" dir="__rootArtifactId__-api" name="API">
+      <fileSets>
+        <fileSet filtered="true" packaged="true">
+          <directory>src/main/java</directory>
+        </fileSet>
+      </fileSets>
+    </module>
+  </modules>
+</archetype-descriptor>
+`
+
+// newTestJar builds an archetype jar with the given files.
+func newTestJar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	jar := newTestJar(t, map[string]string{
+		"META-INF/maven/archetype-metadata.xml":                             testMetadata,
+		"archetype-resources/pom.xml":                                       "<artifactId>${artifactId}</artifactId><port>${port}</port>",
+		"archetype-resources/src/main/java/App.java":                        "#set( $symbol_dollar = '$' )\npackage ${package};\nclass App { String name = \"${serviceName}\"; String v = \"${symbol_dollar}{x}\"; }\n",
+		"archetype-resources/src/main/resources/application.properties":     "name=${serviceName}\n",
+		"archetype-resources/src/main/resources/old.bak":                    "ignored",
+		"archetype-resources/README.md":                                     "not in a fileset",
+		"archetype-resources/__rootArtifactId__-api/pom.xml":                "<artifactId>${artifactId}</artifactId>",
+		"archetype-resources/__rootArtifactId__-api/src/main/java/Api.java": "package ${package};\n",
+	})
+
+	files, err := Generate(jar, map[string]string{
+		"groupId": "com.acme", "artifactId": "orders", "version": "1.0-SNAPSHOT", "package": "com.acme.orders", "port": "8080",
+	})
+	require.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+	assert.Equal(t, map[string]string{
+		"pom.xml":                                "<artifactId>orders</artifactId><port>8080</port>",
+		"src/main/java/com/acme/orders/App.java": "package com.acme.orders;\nclass App { String name = \"orders-service\"; String v = \"${x}\"; }\n",
+		// Unfiltered files are copied as they are
+		"src/main/resources/application.properties":         "name=${serviceName}\n",
+		"orders-api/pom.xml":                                "<artifactId>orders-api</artifactId>",
+		"orders-api/src/main/java/com/acme/orders/Api.java": "package com.acme.orders;\n",
+	}, contents)
+}
+
+func TestGenerate_RequiredProperties(t *testing.T) {
+	jar := newTestJar(t, map[string]string{
+		"META-INF/maven/archetype-metadata.xml": testMetadata,
+		"archetype-resources/pom.xml":           "<project/>",
+	})
+	base := map[string]string{"groupId": "com.acme", "artifactId": "orders", "package": "com.acme"}
+
+	var validationErr *domain.ValidationError
+
+	// port has no default
+	_, err := Generate(jar, base)
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Message, "port")
+
+	base["port"] = "http"
+	_, err = Generate(jar, base)
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestGenerate_RejectsLegacyArchetypes(t *testing.T) {
+	jar := newTestJar(t, map[string]string{"META-INF/maven/archetype.xml": "<archetype/>"})
+
+	_, err := Generate(jar, nil)
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestGenerate_KeepsExecutableFiles(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, mode := range map[string]os.FileMode{
+		"META-INF/maven/archetype-metadata.xml": 0644,
+		"archetype-resources/pom.xml":           0644,
+		"archetype-resources/mvnw":              0755,
+		"archetype-resources/mvnw.cmd":          0644,
+	} {
+		header := &zip.FileHeader{Name: name}
+		header.SetMode(mode)
+		f, err := w.CreateHeader(header)
+		require.NoError(t, err)
+		content := "<artifactId>${artifactId}</artifactId>"
+		if name == "META-INF/maven/archetype-metadata.xml" {
+			content = `<archetype-descriptor name="wrapper"><fileSets><fileSet><directory></directory><includes><include>mvnw*</include></includes></fileSet></fileSets></archetype-descriptor>`
+		}
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	files, err := Generate(buf.Bytes(), map[string]string{
+		"groupId": "com.acme", "artifactId": "orders", "version": "1.0-SNAPSHOT", "package": "com.acme.orders",
+	})
+	require.NoError(t, err)
+
+	modes := make(map[string]os.FileMode)
+	for _, f := range files {
+		modes[f.Path] = f.FileMode()
+	}
+	assert.Equal(t, map[string]os.FileMode{"mvnw": 0755, "mvnw.cmd": 0644, "pom.xml": 0644}, modes)
+}
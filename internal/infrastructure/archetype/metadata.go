@@ -0,0 +1,116 @@
+package archetype
+
+import (
+	"encoding/xml"
+	"path"
+	"strings"
+)
+
+// metadataPath is the location of the descriptor inside an archetype jar
+const metadataPath = "META-INF/maven/archetype-metadata.xml"
+
+// resourcesDir holds the files of the generated project inside an archetype jar
+const resourcesDir = "archetype-resources"
+
+// metadata is the archetype-metadata.xml descriptor.
+type metadata struct {
+	Name               string             `xml:"name,attr"`
+	RequiredProperties []requiredProperty `xml:"requiredProperties>requiredProperty"`
+	FileSets           []fileSet          `xml:"fileSets>fileSet"`
+	Modules            []module           `xml:"modules>module"`
+}
+
+// requiredProperty is a property the archetype needs, with an optional default.
+type requiredProperty struct {
+	Key             string  `xml:"key,attr"`
+	DefaultValue    *string `xml:"defaultValue"`
+	ValidationRegex string  `xml:"validationRegex"`
+}
+
+// fileSet selects files of a directory of the archetype.
+type fileSet struct {
+	// Filtered files are rendered as Velocity templates; others are copied
+	Filtered bool `xml:"filtered,attr"`
+
+	// Packaged files are moved under the package directories, e.g. src/main/java/com/example
+	Packaged bool `xml:"packaged,attr"`
+
+	Directory string   `xml:"directory"`
+	Includes  []string `xml:"includes>include"`
+	Excludes  []string `xml:"excludes>exclude"`
+}
+
+// module is a child module of a multi-module archetype.
+type module struct {
+	ID       string    `xml:"id,attr"`
+	Dir      string    `xml:"dir,attr"`
+	Name     string    `xml:"name,attr"`
+	FileSets []fileSet `xml:"fileSets>fileSet"`
+	Modules  []module  `xml:"modules>module"`
+}
+
+// parseMetadata parses an archetype-metadata.xml descriptor.
+func parseMetadata(data []byte) (*metadata, error) {
+	var md metadata
+	if err := xml.Unmarshal(data, &md); err != nil {
+		return nil, err
+	}
+	return &md, nil
+}
+
+// matches reports whether a path relative to the fileset directory is selected.
+// Without includes every file is selected.
+func (fs fileSet) matches(name string) bool {
+	included := len(fs.Includes) == 0
+	for _, pattern := range fs.Includes {
+		if matchPattern(pattern, name) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, pattern := range fs.Excludes {
+		if matchPattern(pattern, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPattern matches a slash-separated path against an Ant-style pattern,
+// where "**" matches any number of directories and "*" and "?" match within a name.
+func matchPattern(pattern, name string) bool {
+	pattern = strings.TrimPrefix(strings.ReplaceAll(pattern, "\\", "/"), "/")
+	// "dir/" is shorthand for "dir/**"
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments against pattern segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
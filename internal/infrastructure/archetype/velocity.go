@@ -0,0 +1,660 @@
+package archetype
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// This file implements the subset of the Velocity template language used by
+// archetype resources:
+//
+//   - references: $name, ${name}, $!name and $!{name}; undefined references are
+//     printed as written, like Velocity does
+//   - #set( $name = "value" ), with double-quoted strings interpolated and
+//     single-quoted strings taken literally
+//   - #if / #elseif / #else / #end with $ref, !, ==, !=, &&, || and parentheses
+//   - ## line comments, #* block comments *# and #[[ unparsed content ]]#
+//
+// Directives alone on their line consume the line, so they leave no blank lines behind.
+
+// node is an element of a parsed template.
+type node interface{}
+
+// textNode is literal output.
+type textNode string
+
+// refNode is a $reference.
+type refNode struct {
+	name string
+
+	// literal is the reference as written, printed when the name is undefined
+	literal string
+
+	// silent references ($!name) print nothing when undefined
+	silent bool
+}
+
+// setNode is a #set directive.
+type setNode struct {
+	name string
+	expr []token
+}
+
+// ifNode is an #if directive with its #elseif and #else branches.
+type ifNode struct {
+	branches []ifBranch
+	elseBody []node
+}
+
+// ifBranch is a condition and the nodes rendered when it holds.
+type ifBranch struct {
+	cond []token
+	body []node
+}
+
+// renderVelocity renders a template with the given variables.
+// #set directives may add variables; the map passed in is not modified.
+func renderVelocity(name, source string, vars map[string]string) (string, error) {
+	p := &parser{name: name, src: source}
+	nodes, end, err := p.parseNodes()
+	if err != nil {
+		return "", err
+	}
+	if end != "" {
+		return "", p.errorf("#%s without #if", end)
+	}
+
+	ctx := make(map[string]any, len(vars))
+	for k, v := range vars {
+		ctx[k] = v
+	}
+
+	var out strings.Builder
+	if err := renderNodes(&out, nodes, ctx); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// renderNodes writes nodes to out, evaluating directives against ctx.
+func renderNodes(out *strings.Builder, nodes []node, ctx map[string]any) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			out.WriteString(string(n))
+		case refNode:
+			if value, ok := ctx[n.name]; ok {
+				out.WriteString(stringify(value))
+			} else if !n.silent {
+				out.WriteString(n.literal)
+			}
+		case setNode:
+			value, err := evalExpr(n.expr, ctx)
+			if err != nil {
+				return err
+			}
+			ctx[n.name] = value
+		case ifNode:
+			body := n.elseBody
+			for _, branch := range n.branches {
+				value, err := evalExpr(branch.cond, ctx)
+				if err != nil {
+					return err
+				}
+				if truthy(value) {
+					body = branch.body
+					break
+				}
+			}
+			if err := renderNodes(out, body, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parser turns template source into nodes.
+type parser struct {
+	name string
+	src  string
+	pos  int
+
+	// closeArgs holds the condition of the #elseif that ended parseNodes
+	closeArgs []token
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("%s:%d: %s", p.name, line, fmt.Sprintf(format, args...))
+}
+
+// parseNodes parses until the end of the input or an #elseif, #else or #end,
+// whose name is returned so the enclosing #if can continue.
+func (p *parser) parseNodes() ([]node, string, error) {
+	var nodes []node
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		rest := p.src[p.pos:]
+
+		switch {
+		case c == '\\' && (strings.HasPrefix(rest, `\$`) || strings.HasPrefix(rest, `\#`)):
+			// An escaped reference or directive is printed without the backslash
+			text.WriteByte(rest[1])
+			p.pos += 2
+
+		case strings.HasPrefix(rest, "##"):
+			// A line comment runs up to and including the newline
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				p.pos += i + 1
+			} else {
+				p.pos = len(p.src)
+			}
+
+		case strings.HasPrefix(rest, "#*"):
+			i := strings.Index(rest[2:], "*#")
+			if i < 0 {
+				return nil, "", p.errorf("unterminated #* comment")
+			}
+			p.pos += 2 + i + 2
+
+		case strings.HasPrefix(rest, "#[["):
+			i := strings.Index(rest[3:], "]]#")
+			if i < 0 {
+				return nil, "", p.errorf("unterminated #[[ block")
+			}
+			text.WriteString(rest[3 : 3+i])
+			p.pos += 3 + i + 3
+
+		case c == '#':
+			name, ok := directiveName(rest)
+			if !ok {
+				text.WriteByte(c)
+				p.pos++
+				continue
+			}
+
+			lineStart := strings.LastIndexByte(p.src[:p.pos], '\n') + 1
+			alone := strings.TrimSpace(p.src[lineStart:p.pos]) == ""
+			p.pos += len(directiveToken(rest))
+
+			var args []token
+			if name == "set" || name == "if" || name == "elseif" {
+				var err error
+				if args, err = p.parseArgs(); err != nil {
+					return nil, "", err
+				}
+			}
+
+			// A directive alone on its line takes the indentation and the newline with it
+			if alone && p.restOfLineIsBlank() {
+				pending := strings.TrimRight(text.String(), " \t")
+				text.Reset()
+				text.WriteString(pending)
+				p.skipLineEnd()
+			}
+			flush()
+
+			switch name {
+			case "set":
+				if len(args) < 3 || args[0].kind != tokenRef || args[1].kind != tokenAssign {
+					return nil, "", p.errorf("invalid #set, expected #set( $name = value )")
+				}
+				nodes = append(nodes, setNode{name: args[0].text, expr: args[2:]})
+			case "if":
+				n, err := p.parseIf(args)
+				if err != nil {
+					return nil, "", err
+				}
+				nodes = append(nodes, n)
+			default:
+				// #elseif, #else and #end close the current block
+				p.closeArgs = args
+				return nodes, name, nil
+			}
+
+		case c == '$':
+			ref, n := parseReference(rest)
+			if n == 0 {
+				text.WriteByte(c)
+				p.pos++
+				continue
+			}
+			flush()
+			nodes = append(nodes, ref)
+			p.pos += n
+
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	flush()
+	return nodes, "", nil
+}
+
+// parseIf parses the branches of an #if directive up to its #end.
+func (p *parser) parseIf(cond []token) (node, error) {
+	var n ifNode
+
+	for {
+		body, end, err := p.parseNodes()
+		if err != nil {
+			return nil, err
+		}
+
+		switch end {
+		case "":
+			return nil, p.errorf("#if without #end")
+		case "elseif":
+			n.branches = append(n.branches, ifBranch{cond: cond, body: body})
+			cond = p.closeArgs
+		case "else":
+			n.branches = append(n.branches, ifBranch{cond: cond, body: body})
+			cond = nil
+		case "end":
+			if cond != nil {
+				n.branches = append(n.branches, ifBranch{cond: cond, body: body})
+			} else {
+				n.elseBody = body
+			}
+			return n, nil
+		}
+	}
+}
+
+// parseArgs parses the parenthesized arguments of a directive into tokens.
+func (p *parser) parseArgs() ([]token, error) {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '(' {
+		return nil, p.errorf("expected ( after directive")
+	}
+
+	depth := 0
+	start := p.pos
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '"', '\'':
+			end := strings.IndexByte(p.src[p.pos+1:], c)
+			if end < 0 {
+				return nil, p.errorf("unterminated string")
+			}
+			p.pos += end + 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				tokens, err := tokenize(p.src[start+1 : p.pos-1])
+				if err != nil {
+					return nil, p.errorf("%v", err)
+				}
+				return tokens, nil
+			}
+		}
+		p.pos++
+	}
+	return nil, p.errorf("unterminated directive arguments")
+}
+
+// restOfLineIsBlank reports whether only blanks follow the current position on its line.
+func (p *parser) restOfLineIsBlank() bool {
+	rest := p.src[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimSpace(rest) == ""
+}
+
+// skipLineEnd consumes the blanks and the newline that end the current line.
+func (p *parser) skipLineEnd() {
+	i := p.pos
+	for i < len(p.src) && (p.src[i] == ' ' || p.src[i] == '\t' || p.src[i] == '\r') {
+		i++
+	}
+	if i < len(p.src) && p.src[i] == '\n' {
+		i++
+	}
+	p.pos = i
+}
+
+// directiveNames are the directives the renderer understands
+var directiveNames = []string{"elseif", "else", "end", "set", "if"}
+
+// directiveName returns the name of the directive at the start of s, which begins with '#'.
+func directiveName(s string) (string, bool) {
+	token := directiveToken(s)
+	if token == "" {
+		return "", false
+	}
+	return strings.Trim(token, "#{}"), true
+}
+
+// directiveToken returns the directive at the start of s, e.g. "#if" or "#{end}",
+// or "" if s does not start with a known directive.
+func directiveToken(s string) string {
+	for _, name := range directiveNames {
+		if braced := "#{" + name + "}"; strings.HasPrefix(s, braced) {
+			return braced
+		}
+		plain := "#" + name
+		if !strings.HasPrefix(s, plain) {
+			continue
+		}
+		// "#endif" or "#settings" are not directives
+		if next := s[len(plain):]; next != "" && isIdentifierChar(rune(next[0])) {
+			continue
+		}
+		return plain
+	}
+	return ""
+}
+
+// parseReference parses a reference at the start of s, which begins with '$'.
+// It returns the number of bytes consumed, or 0 if s does not start with a reference.
+func parseReference(s string) (refNode, int) {
+	i := 1
+	silent := false
+	if strings.HasPrefix(s[i:], "!") {
+		silent = true
+		i++
+	}
+
+	braced := strings.HasPrefix(s[i:], "{")
+	if braced {
+		i++
+	}
+
+	start := i
+	if i >= len(s) || !isIdentifierStart(rune(s[i])) {
+		return refNode{}, 0
+	}
+	for i < len(s) && isIdentifierChar(rune(s[i])) {
+		i++
+	}
+	name := s[start:i]
+
+	if braced {
+		if i >= len(s) || s[i] != '}' {
+			return refNode{}, 0
+		}
+		i++
+	}
+
+	return refNode{name: name, literal: s[:i], silent: silent}, i
+}
+
+func isIdentifierStart(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
+
+func isIdentifierChar(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-')
+}
+
+// tokenKind classifies expression tokens.
+type tokenKind int
+
+const (
+	tokenRef tokenKind = iota
+	tokenString
+	tokenLiteral
+	tokenBool
+	tokenOp
+	tokenAssign
+	tokenOpen
+	tokenClose
+)
+
+// token is an element of a directive expression.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits directive arguments into tokens.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '$':
+			ref, n := parseReference(rest)
+			if n == 0 {
+				return nil, fmt.Errorf("invalid reference in %q", s)
+			}
+			tokens = append(tokens, token{kind: tokenRef, text: ref.name})
+			i += n
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(rest[1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in %q", s)
+			}
+			kind := tokenLiteral
+			if c == '"' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, text: rest[1 : 1+end]})
+			i += end + 2
+		case strings.HasPrefix(rest, "==") || strings.HasPrefix(rest, "!=") ||
+			strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||"):
+			tokens = append(tokens, token{kind: tokenOp, text: rest[:2]})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, token{kind: tokenOp, text: "!"})
+			i++
+		case c == '=':
+			tokens = append(tokens, token{kind: tokenAssign, text: "="})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		default:
+			j := i
+			for j < len(s) && (isIdentifierChar(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("unexpected %q in %q", c, s)
+			}
+			word := s[i:j]
+			switch word {
+			case "true", "false":
+				tokens = append(tokens, token{kind: tokenBool, text: word})
+			case "and":
+				tokens = append(tokens, token{kind: tokenOp, text: "&&"})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOp, text: "||"})
+			case "not":
+				tokens = append(tokens, token{kind: tokenOp, text: "!"})
+			case "eq":
+				tokens = append(tokens, token{kind: tokenOp, text: "=="})
+			case "ne":
+				tokens = append(tokens, token{kind: tokenOp, text: "!="})
+			default:
+				// Numbers and other bare words evaluate to themselves
+				tokens = append(tokens, token{kind: tokenLiteral, text: word})
+			}
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// evalExpr evaluates an expression. Values are strings, booleans, or nil for
+// undefined references.
+func evalExpr(tokens []token, ctx map[string]any) (any, error) {
+	e := &evaluator{tokens: tokens, ctx: ctx}
+	value, err := e.or()
+	if err != nil {
+		return nil, err
+	}
+	if e.pos != len(tokens) {
+		return nil, fmt.Errorf("unexpected %q in expression", tokens[e.pos].text)
+	}
+	return value, nil
+}
+
+// evaluator is a recursive descent evaluator over expression tokens.
+type evaluator struct {
+	tokens []token
+	pos    int
+	ctx    map[string]any
+}
+
+func (e *evaluator) peekOp(op string) bool {
+	return e.pos < len(e.tokens) && e.tokens[e.pos].kind == tokenOp && e.tokens[e.pos].text == op
+}
+
+func (e *evaluator) or() (any, error) {
+	left, err := e.and()
+	if err != nil {
+		return nil, err
+	}
+	for e.peekOp("||") {
+		e.pos++
+		right, err := e.and()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) || truthy(right)
+	}
+	return left, nil
+}
+
+func (e *evaluator) and() (any, error) {
+	left, err := e.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for e.peekOp("&&") {
+		e.pos++
+		right, err := e.comparison()
+		if err != nil {
+			return nil, err
+		}
+		left = truthy(left) && truthy(right)
+	}
+	return left, nil
+}
+
+func (e *evaluator) comparison() (any, error) {
+	left, err := e.unary()
+	if err != nil {
+		return nil, err
+	}
+	for e.peekOp("==") || e.peekOp("!=") {
+		op := e.tokens[e.pos].text
+		e.pos++
+		right, err := e.unary()
+		if err != nil {
+			return nil, err
+		}
+		equal := left != nil && right != nil && stringify(left) == stringify(right)
+		left = equal == (op == "==")
+	}
+	return left, nil
+}
+
+func (e *evaluator) unary() (any, error) {
+	if e.peekOp("!") {
+		e.pos++
+		value, err := e.unary()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(value), nil
+	}
+	return e.primary()
+}
+
+func (e *evaluator) primary() (any, error) {
+	if e.pos >= len(e.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	t := e.tokens[e.pos]
+	e.pos++
+
+	switch t.kind {
+	case tokenRef:
+		return e.ctx[t.text], nil
+	case tokenString:
+		// Double-quoted strings are templates themselves
+		p := &parser{name: "string", src: t.text}
+		nodes, _, err := p.parseNodes()
+		if err != nil {
+			return nil, err
+		}
+		var out strings.Builder
+		if err := renderNodes(&out, nodes, e.ctx); err != nil {
+			return nil, err
+		}
+		return out.String(), nil
+	case tokenLiteral:
+		return t.text, nil
+	case tokenBool:
+		return t.text == "true", nil
+	case tokenOpen:
+		value, err := e.or()
+		if err != nil {
+			return nil, err
+		}
+		if e.pos >= len(e.tokens) || e.tokens[e.pos].kind != tokenClose {
+			return nil, fmt.Errorf("missing ) in expression")
+		}
+		e.pos++
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q in expression", t.text)
+	}
+}
+
+// truthy follows Velocity: undefined is false, booleans are themselves,
+// and any other value, even an empty string, is true.
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// stringify returns the text form of a value.
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprint(v)
+	}
+}
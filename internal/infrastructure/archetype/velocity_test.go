@@ -0,0 +1,68 @@
+package archetype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderVelocity(t *testing.T) {
+	vars := map[string]string{"package": "com.acme", "artifactId": "orders", "useLombok": "true"}
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "references", source: "package ${package};\n// $artifactId\n", want: "package com.acme;\n// orders\n"},
+		{name: "undefined reference", source: "<version>${project.version}</version> $missing", want: "<version>${project.version}</version> $missing"},
+		{name: "silent reference", source: "[$!missing][$!{artifactId}]", want: "[][orders]"},
+		{name: "hyphen is part of the name", source: "$artifactId-core ${artifactId}-core", want: "$artifactId-core orders-core"},
+		{name: "escaped", source: `\${package} \#if`, want: "${package} #if"},
+		{
+			name:   "symbol definitions",
+			source: "#set( $symbol_pound = '#' )\n#set( $symbol_dollar = '$' )\n${symbol_pound}!/bin/sh\necho ${symbol_dollar}HOME\n",
+			want:   "#!/bin/sh\necho $HOME\n",
+		},
+		{name: "set interpolates double quotes", source: "#set($name = \"${artifactId}-api\")\n$name", want: "orders-api"},
+		{
+			name:   "if with else",
+			source: "a\n  #if( $useLombok == \"true\" )\n  lombok\n  #else\n  plain\n  #end\nb\n",
+			want:   "a\n  lombok\nb\n",
+		},
+		{
+			name:   "elseif and operators",
+			source: "#if( !$useLombok )none#elseif( $artifactId == 'orders' && $package != 'x' )yes#{else}no#end",
+			want:   "yes",
+		},
+		{name: "undefined is false", source: "#if($missing)x#{else}y#end", want: "y"},
+		{name: "comments", source: "a ## note\n#* block\n*#b", want: "a b"},
+		{name: "unparsed block", source: "#[[${package} #if]]#", want: "${package} #if"},
+		{name: "not a directive", source: "#include <stdio.h>\n#ifdef X\n", want: "#include <stdio.h>\n#ifdef X\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderVelocity(tt.name, tt.source, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderVelocity_Errors(t *testing.T) {
+	for _, source := range []string{"#if($a)x", "x#end", "#set($a)", "#* open"} {
+		_, err := renderVelocity("test", source, nil)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	assert.True(t, matchPattern("**/*.java", "App.java"))
+	assert.True(t, matchPattern("**/*.java", "service/App.java"))
+	assert.False(t, matchPattern("**/*.java", "app.properties"))
+	assert.True(t, matchPattern("static/", "static/css/site.css"))
+	assert.True(t, matchPattern("*.md", "README.md"))
+	assert.False(t, matchPattern("*.md", "docs/README.md"))
+}
@@ -0,0 +1,269 @@
+// Package diff computes line-based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around each change
+const DefaultContext = 3
+
+// Op is the kind of a line in a diff.
+type Op int
+
+const (
+	// Equal marks a line present in both inputs
+	Equal Op = iota
+
+	// Delete marks a line only present in the old input
+	Delete
+
+	// Insert marks a line only present in the new input
+	Insert
+)
+
+// Line is a single line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk is a group of changes with surrounding context.
+type Hunk struct {
+	// OldStart and NewStart are 1-based line numbers; 0 for an empty range
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// Header returns the "@@ -l,s +l,s @@" line of the hunk.
+func (h *Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+// hunkRange formats a range the way GNU diff does: the length is omitted when it is 1.
+func hunkRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// Hunks computes the hunks needed to turn oldText into newText.
+// Returns nil when both texts are equal.
+func Hunks(oldText, newText string, context int) []*Hunk {
+	if oldText == newText {
+		return nil
+	}
+
+	lines := compare(splitLines(oldText), splitLines(newText))
+
+	// Find the line ranges to show: every change plus context, merging ranges that touch
+	type span struct{ start, end int }
+	var spans []span
+	for i, line := range lines {
+		if line.Op == Equal {
+			continue
+		}
+		start, end := i-context, i+context+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if len(spans) > 0 && start <= spans[len(spans)-1].end {
+			spans[len(spans)-1].end = end
+		} else {
+			spans = append(spans, span{start, end})
+		}
+	}
+
+	hunks := make([]*Hunk, 0, len(spans))
+	oldLine, newLine, pos := 1, 1, 0
+
+	for _, sp := range spans {
+		for ; pos < sp.start; pos++ {
+			oldLine, newLine = advance(lines[pos].Op, oldLine, newLine)
+		}
+
+		h := &Hunk{OldStart: oldLine, NewStart: newLine}
+		for ; pos < sp.end; pos++ {
+			line := lines[pos]
+			h.Lines = append(h.Lines, line)
+			if line.Op != Insert {
+				h.OldLines++
+			}
+			if line.Op != Delete {
+				h.NewLines++
+			}
+			oldLine, newLine = advance(line.Op, oldLine, newLine)
+		}
+
+		// GNU diff reports an empty range as starting at the line before it
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+
+		hunks = append(hunks, h)
+	}
+
+	return hunks
+}
+
+// advance moves the old and new line counters past a line with the given op.
+func advance(op Op, oldLine, newLine int) (int, int) {
+	switch op {
+	case Delete:
+		return oldLine + 1, newLine
+	case Insert:
+		return oldLine, newLine + 1
+	default:
+		return oldLine + 1, newLine + 1
+	}
+}
+
+// Unified renders a unified diff between oldText and newText.
+// Returns an empty string when both texts are equal.
+func Unified(oldName, newName, oldText, newText string) string {
+	hunks := Hunks(oldText, newText, DefaultContext)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "--- %s\n", oldName)
+	fmt.Fprintf(&b, "+++ %s\n", newName)
+
+	for _, h := range hunks {
+		b.WriteString(h.Header())
+		b.WriteString("\n")
+
+		for _, line := range h.Lines {
+			switch line.Op {
+			case Delete:
+				b.WriteString("-")
+			case Insert:
+				b.WriteString("+")
+			default:
+				b.WriteString(" ")
+			}
+
+			if strings.HasSuffix(line.Text, "\n") {
+				b.WriteString(line.Text)
+			} else {
+				b.WriteString(line.Text)
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// splitLines splits text into lines, keeping line terminators.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// compare returns the shortest edit script turning a into b (Myers' algorithm).
+func compare(a, b []string) []Line {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		// Round d only reads diagonals -d..d of the previous round, so keep just those
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks the Myers trace back from the end to build the edit script.
+func backtrack(a, b []string, trace [][]int, depth int) []Line {
+	x, y := len(a), len(b)
+	var reversed []Line
+
+	for d := depth; d > 0; d-- {
+		// trace[d] holds diagonals -d..d, so diagonal k is at index k+d
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[prevK+d]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Op: Equal, Text: a[x]})
+		}
+
+		if x == prevX {
+			y--
+			reversed = append(reversed, Line{Op: Insert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Line{Op: Delete, Text: a[x]})
+		}
+	}
+
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Line{Op: Equal, Text: a[x]})
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
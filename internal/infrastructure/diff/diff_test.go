@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified_Equal(t *testing.T) {
+	assert.Equal(t, "", Unified("a", "b", "same\n", "same\n"))
+}
+
+func TestUnified_Insertion(t *testing.T) {
+	oldText := "<dependencies>\n</dependencies>\n"
+	newText := "<dependencies>\n  <dependency/>\n</dependencies>\n"
+
+	expected := "--- a/pom.xml\n" +
+		"+++ b/pom.xml\n" +
+		"@@ -1,2 +1,3 @@\n" +
+		" <dependencies>\n" +
+		"+  <dependency/>\n" +
+		" </dependencies>\n"
+
+	assert.Equal(t, expected, Unified("a/pom.xml", "b/pom.xml", oldText, newText))
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	expected := "--- /dev/null\n" +
+		"+++ b/pom.xml\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+<project>\n" +
+		"+</project>\n"
+
+	assert.Equal(t, expected, Unified("/dev/null", "b/pom.xml", "", "<project>\n</project>\n"))
+}
+
+func TestUnified_NoNewlineAtEnd(t *testing.T) {
+	expected := "--- a\n" +
+		"+++ b\n" +
+		"@@ -1 +1 @@\n" +
+		"-old\n" +
+		"\\ No newline at end of file\n" +
+		"+new\n" +
+		"\\ No newline at end of file\n"
+
+	assert.Equal(t, expected, Unified("a", "b", "old", "new"))
+}
+
+func TestHunks_SeparatesDistantChanges(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 20; i++ {
+		line := strings.Repeat("x", i+1) + "\n"
+		oldLines = append(oldLines, line)
+		newLines = append(newLines, line)
+	}
+	newLines[1] = "changed\n"
+	newLines[18] = "changed\n"
+
+	hunks := Hunks(strings.Join(oldLines, ""), strings.Join(newLines, ""), DefaultContext)
+
+	assert.Len(t, hunks, 2)
+	assert.Equal(t, "@@ -1,5 +1,5 @@", hunks[0].Header())
+	assert.Equal(t, "@@ -16,5 +16,5 @@", hunks[1].Header())
+}
+
+func TestHunks_MergesCloseChanges(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	newText := "A\nb\nc\nd\ne\nf\ng\nH\n"
+
+	hunks := Hunks(oldText, newText, DefaultContext)
+
+	assert.Len(t, hunks, 1)
+	assert.Equal(t, "@@ -1,8 +1,8 @@", hunks[0].Header())
+}
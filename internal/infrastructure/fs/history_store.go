@@ -0,0 +1,159 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const (
+	// HistoryDir is the journal location, relative to the project directory
+	HistoryDir = ".mvnx/history"
+
+	// DefaultHistoryLimit is the number of entries kept before the oldest are pruned
+	DefaultHistoryLimit = 50
+)
+
+// HistoryStore implements domain.HistoryStore with one JSON file per entry
+// under <project>/.mvnx/history.
+type HistoryStore struct {
+	dir   string
+	limit int
+}
+
+// NewHistoryStore creates a HistoryStore for the project in projectPath.
+func NewHistoryStore(projectPath string) *HistoryStore {
+	return &HistoryStore{
+		dir:   filepath.Join(projectPath, filepath.FromSlash(HistoryDir)),
+		limit: DefaultHistoryLimit,
+	}
+}
+
+// historyRecord is the on-disk representation of a history entry.
+type historyRecord struct {
+	ID          int       `json:"id"`
+	Time        time.Time `json:"time"`
+	Description string    `json:"description"`
+	Before      string    `json:"before"`
+	After       string    `json:"after"`
+}
+
+// Append records a new entry, assigns its ID and prunes entries beyond the limit.
+func (h *HistoryStore) Append(entry *domain.HistoryEntry) error {
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	ids, err := h.ids()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = 1
+	if len(ids) > 0 {
+		entry.ID = ids[len(ids)-1] + 1
+	}
+
+	data, err := json.MarshalIndent(historyRecord{
+		ID:          entry.ID,
+		Time:        entry.Time,
+		Description: entry.Description,
+		Before:      string(entry.Before),
+		After:       string(entry.After),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	if err := WriteFileAtomic(h.path(entry.ID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+
+	ids = append(ids, entry.ID)
+	for len(ids) > h.limit {
+		if err := h.Remove(ids[0]); err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+
+	return nil
+}
+
+// List returns all entries, oldest first.
+func (h *HistoryStore) List() ([]*domain.HistoryEntry, error) {
+	ids, err := h.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.HistoryEntry, 0, len(ids))
+	for _, id := range ids {
+		data, err := os.ReadFile(h.path(id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history entry %d: %w", id, err)
+		}
+
+		var record historyRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse history entry %d: %w", id, err)
+		}
+
+		entries = append(entries, &domain.HistoryEntry{
+			ID:          record.ID,
+			Time:        record.Time,
+			Description: record.Description,
+			Before:      []byte(record.Before),
+			After:       []byte(record.After),
+		})
+	}
+
+	return entries, nil
+}
+
+// Remove deletes the entry with the given ID.
+func (h *HistoryStore) Remove(id int) error {
+	if err := os.Remove(h.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove history entry %d: %w", id, err)
+	}
+	return nil
+}
+
+// ids returns the IDs of the stored entries in ascending order.
+func (h *HistoryStore) ids() ([]int, error) {
+	files, err := os.ReadDir(h.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var ids []int
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// path returns the file holding the entry with the given ID.
+func (h *HistoryStore) path(id int) string {
+	return filepath.Join(h.dir, fmt.Sprintf("%06d.json", id))
+}
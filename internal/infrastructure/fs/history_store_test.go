@@ -0,0 +1,82 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestHistoryStore_AppendAndList(t *testing.T) {
+	store := NewHistoryStore(t.TempDir())
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first := &domain.HistoryEntry{Time: time.Unix(100, 0).UTC(), Description: "add a:b:1", Before: []byte("v0"), After: []byte("v1")}
+	second := &domain.HistoryEntry{Time: time.Unix(200, 0).UTC(), Description: "remove b", Before: []byte("v1"), After: []byte("v2")}
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+
+	assert.Equal(t, 1, first.ID)
+	assert.Equal(t, 2, second.ID)
+
+	entries, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first, entries[0])
+	assert.Equal(t, second, entries[1])
+}
+
+func TestHistoryStore_PrunesOldestEntries(t *testing.T) {
+	store := NewHistoryStore(t.TempDir())
+	store.limit = 2
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Append(&domain.HistoryEntry{Description: "op"}))
+	}
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].ID)
+	assert.Equal(t, 3, entries[1].ID)
+}
+
+func TestHistoryStore_Remove(t *testing.T) {
+	store := NewHistoryStore(t.TempDir())
+	require.NoError(t, store.Append(&domain.HistoryEntry{Description: "op"}))
+
+	require.NoError(t, store.Remove(1))
+	require.NoError(t, store.Remove(1))
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pom.xml")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+
+	require.NoError(t, WriteFileAtomic(path, []byte("new"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "temporary file must not be left behind")
+}
@@ -0,0 +1,198 @@
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// LicensePolicyFileName is the name of the license policy of a project
+const LicensePolicyFileName = "mvnx-license-policy.yaml"
+
+// licenseExceptionDate is the layout of the date of exceptions
+const licenseExceptionDate = "2006-01-02"
+
+// LicensePolicyFile implements domain.LicensePolicyStore with a YAML file meant
+// to be committed with the project, such as:
+//
+//	allowed: [Apache-2.0, MIT]
+//	denied: [GPL-3.0-only]
+//	unknown: deny
+//	exceptions:
+//	  - artifact: org.example:lib
+//	    justification: Approved by legal
+type LicensePolicyFile struct {
+	// path is empty when the project has no policy
+	path string
+}
+
+// licensePolicyRecord is the on-disk representation of a license policy.
+type licensePolicyRecord struct {
+	Allowed    []string                 `yaml:"allowed"`
+	Denied     []string                 `yaml:"denied"`
+	Unknown    string                   `yaml:"unknown"`
+	Exceptions []licenseExceptionRecord `yaml:"exceptions"`
+}
+
+// licenseExceptionRecord is the on-disk representation of a license exception.
+type licenseExceptionRecord struct {
+	Artifact      string   `yaml:"artifact"`
+	Licenses      []string `yaml:"licenses,omitempty"`
+	Justification string   `yaml:"justification"`
+	Added         string   `yaml:"added,omitempty"`
+}
+
+// NewLicensePolicyFile finds the license policy of the project in projectPath:
+// the nearest mvnx-license-policy.yaml in the directory or its ancestors, so the
+// modules of a multi-module project share the policy of the root.
+func NewLicensePolicyFile(projectPath string) *LicensePolicyFile {
+	dir, err := filepath.Abs(projectPath)
+	if err != nil {
+		return &LicensePolicyFile{}
+	}
+
+	for {
+		path := filepath.Join(dir, LicensePolicyFileName)
+		if _, err := os.Stat(path); err == nil {
+			return &LicensePolicyFile{path: path}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return &LicensePolicyFile{}
+		}
+		dir = parent
+	}
+}
+
+// Path returns the location of the policy, or "" if the project has none.
+func (f *LicensePolicyFile) Path() string {
+	return f.path
+}
+
+// Load reads the policy. Returns nil if the project has none, and a
+// *ValidationError if the file is invalid.
+func (f *LicensePolicyFile) Load() (*domain.LicensePolicy, error) {
+	if f.path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the license policy: %w", err)
+	}
+
+	var record licensePolicyRecord
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&record); err != nil && !errors.Is(err, io.EOF) {
+		return nil, f.invalid(err.Error())
+	}
+
+	policy := &domain.LicensePolicy{Allowed: record.Allowed, Denied: record.Denied}
+	switch record.Unknown {
+	case "", "warn":
+	case "deny":
+		policy.DenyUnknown = true
+	default:
+		return nil, f.invalid(fmt.Sprintf("unknown must be warn or deny, not %q", record.Unknown))
+	}
+
+	for _, e := range record.Exceptions {
+		if strings.Count(e.Artifact, ":") != 1 {
+			return nil, f.invalid(fmt.Sprintf("the artifact of an exception must be groupId:artifactId, not %q", e.Artifact))
+		}
+		exception := &domain.LicenseException{Artifact: e.Artifact, Licenses: e.Licenses, Justification: e.Justification}
+		if e.Added != "" {
+			if exception.Added, err = time.Parse(licenseExceptionDate, e.Added); err != nil {
+				return nil, f.invalid(fmt.Sprintf("the date of the exception for %s must be YYYY-MM-DD, not %q", e.Artifact, e.Added))
+			}
+		}
+		policy.Exceptions = append(policy.Exceptions, exception)
+	}
+
+	return policy, nil
+}
+
+// invalid returns a *ValidationError about the policy file.
+func (f *LicensePolicyFile) invalid(message string) error {
+	return &domain.ValidationError{Field: "license policy", Message: fmt.Sprintf("invalid license policy %s: %s", f.path, message)}
+}
+
+// AddException appends the exception to the exceptions of the policy, keeping
+// the rest of the file, comments included.
+func (f *LicensePolicyFile) AddException(exception *domain.LicenseException) error {
+	if f.path == "" {
+		return &domain.NotFoundError{Kind: "license policy", Name: LicensePolicyFileName}
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read the license policy: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return f.invalid(err.Error())
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return f.invalid("expected a mapping")
+	}
+
+	var exceptions *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "exceptions" {
+			exceptions = root.Content[i+1]
+		}
+	}
+	if exceptions == nil {
+		exceptions = &yaml.Node{}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "exceptions"}, exceptions)
+	}
+	if exceptions.Kind != yaml.SequenceNode {
+		// An empty "exceptions:" is null
+		*exceptions = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", HeadComment: exceptions.HeadComment}
+	}
+	exceptions.Style = 0
+
+	var entry yaml.Node
+	record := licenseExceptionRecord{
+		Artifact:      exception.Artifact,
+		Licenses:      exception.Licenses,
+		Justification: exception.Justification,
+	}
+	if !exception.Added.IsZero() {
+		record.Added = exception.Added.Format(licenseExceptionDate)
+	}
+	if err := entry.Encode(record); err != nil {
+		return fmt.Errorf("failed to encode the license exception: %w", err)
+	}
+	exceptions.Content = append(exceptions.Content, &entry)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode the license policy: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode the license policy: %w", err)
+	}
+
+	if err := WriteFileAtomic(f.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write the license policy: %w", err)
+	}
+	return nil
+}
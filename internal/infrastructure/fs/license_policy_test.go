@@ -0,0 +1,86 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestLicensePolicyFile_LoadAndAddException(t *testing.T) {
+	root := t.TempDir()
+	module := filepath.Join(root, "module")
+	require.NoError(t, os.MkdirAll(module, 0755))
+	path := filepath.Join(root, LicensePolicyFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`# Reviewed by legal
+allowed: [Apache-2.0, MIT]
+denied:
+  - GPL-3.0-only # copyleft
+unknown: deny
+exceptions:
+`), 0644))
+
+	// Modules use the policy of the root
+	store := NewLicensePolicyFile(module)
+	assert.Equal(t, path, store.Path())
+
+	policy, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, &domain.LicensePolicy{
+		Allowed:     []string{"Apache-2.0", "MIT"},
+		Denied:      []string{"GPL-3.0-only"},
+		DenyUnknown: true,
+	}, policy)
+
+	exception := &domain.LicenseException{
+		Artifact:      "org.example:lib",
+		Licenses:      []string{"Custom"},
+		Justification: "Approved by legal",
+		Added:         time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.AddException(exception))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Reviewed by legal")
+	assert.Contains(t, string(content), "# copyleft")
+
+	policy, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.LicenseException{exception}, policy.Exceptions)
+}
+
+func TestLicensePolicyFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown action", "unknown: ignore\n"},
+		{"unknown field", "allow: [MIT]\n"},
+		{"exception artifact", "exceptions:\n  - artifact: lib\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, LicensePolicyFileName), []byte(tt.content), 0644))
+
+			_, err := NewLicensePolicyFile(dir).Load()
+			var validationErr *domain.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func TestLicensePolicyFile_None(t *testing.T) {
+	store := NewLicensePolicyFile(t.TempDir())
+
+	policy, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
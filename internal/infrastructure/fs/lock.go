@@ -0,0 +1,85 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const (
+	// LockFile is the lock file, relative to the project directory
+	LockFile = ".mvnx/lock"
+
+	// DefaultLockTimeout is how long Lock waits for another process to finish
+	DefaultLockTimeout = 30 * time.Second
+
+	// lockRetryInterval is how often a held lock is polled
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// errLocked is returned by tryLock when another process holds the lock
+var errLocked = errors.New("lock is held by another process")
+
+// FileLocker implements domain.Locker with an OS file lock on .mvnx/lock.
+// The lock file is never removed, since deleting it while another process
+// waits on it would let two processes hold "the" lock at the same time.
+type FileLocker struct {
+	timeout time.Duration
+}
+
+// NewFileLocker creates a new FileLocker that waits up to DefaultLockTimeout.
+func NewFileLocker() *FileLocker {
+	return &FileLocker{timeout: DefaultLockTimeout}
+}
+
+// fileLock is a held lock on an open lock file.
+type fileLock struct {
+	file *os.File
+}
+
+// Lock acquires the lock of the project containing pomPath.
+func (l *FileLocker) Lock(pomPath string) (domain.Lock, error) {
+	path := filepath.Join(filepath.Dir(pomPath), filepath.FromSlash(LockFile))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(l.timeout)
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return &fileLock{file: file}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, &domain.ConflictError{
+				Path:   pomPath,
+				Reason: fmt.Sprintf("another mvnx process is editing this project (waited %s for %s)", l.timeout, LockFile),
+			}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Unlock releases the lock and closes the lock file.
+func (l *fileLock) Unlock() error {
+	err := unlock(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
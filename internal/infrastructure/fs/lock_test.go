@@ -0,0 +1,48 @@
+package fs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestFileLocker_ExcludesConcurrentHolders(t *testing.T) {
+	pomPath := filepath.Join(t.TempDir(), "pom.xml")
+	locker := &FileLocker{timeout: 100 * time.Millisecond}
+
+	lock, err := locker.Lock(pomPath)
+	require.NoError(t, err)
+
+	_, err = locker.Lock(pomPath)
+	var conflictErr *domain.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, pomPath, conflictErr.Path)
+
+	require.NoError(t, lock.Unlock())
+
+	lock, err = locker.Lock(pomPath)
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+}
+
+func TestFileLocker_WaitsForRelease(t *testing.T) {
+	pomPath := filepath.Join(t.TempDir(), "pom.xml")
+	locker := &FileLocker{timeout: 5 * time.Second}
+
+	lock, err := locker.Lock(pomPath)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		assert.NoError(t, lock.Unlock())
+	}()
+
+	second, err := locker.Lock(pomPath)
+	require.NoError(t, err)
+	require.NoError(t, second.Unlock())
+}
@@ -0,0 +1,52 @@
+package fs
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// modulePomTemplate is the pom.xml of a module created by mvnx new-module.
+// groupId and version are inherited from the parent, and <relativePath> is only
+// written when the parent is not in the directory above, where Maven looks by default.
+var modulePomTemplate = template.Must(template.New("pom.xml").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0
+         http://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+
+  <parent>
+    <groupId>{{.Parent.GroupID}}</groupId>
+    <artifactId>{{.Parent.ArtifactID}}</artifactId>
+    <version>{{.Parent.Version}}</version>
+{{- if ne .Parent.RelativePath .DefaultRelativePath}}
+    <relativePath>{{.Parent.RelativePath}}</relativePath>
+{{- end}}
+  </parent>
+
+  <artifactId>{{.ArtifactID}}</artifactId>
+  <packaging>{{.Packaging}}</packaging>
+
+  <name>{{.ArtifactID}}</name>
+
+  <dependencies>
+  </dependencies>
+</project>
+`))
+
+// RenderModule returns the files of a new module, relative to the module directory.
+func RenderModule(spec *domain.ModuleSpec) ([]ProjectFile, error) {
+	data := struct {
+		*domain.ModuleSpec
+		DefaultRelativePath string
+	}{spec, domain.DefaultParentRelativePath}
+
+	var buf bytes.Buffer
+	if err := modulePomTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return []ProjectFile{{Path: "pom.xml", Content: buf.Bytes()}}, nil
+}
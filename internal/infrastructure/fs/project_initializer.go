@@ -4,46 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-)
 
-const (
-	// DefaultPomTemplate is the minimal pom.xml template
-	DefaultPomTemplate = `<?xml version="1.0" encoding="UTF-8"?>
-<project xmlns="http://maven.apache.org/POM/4.0.0"
-         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
-         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0
-         http://maven.apache.org/xsd/maven-4.0.0.xsd">
-  <modelVersion>4.0.0</modelVersion>
-
-  <groupId>com.example</groupId>
-  <artifactId>my-app</artifactId>
-  <version>1.0-SNAPSHOT</version>
-  <packaging>jar</packaging>
-
-  <name>my-app</name>
-
-  <properties>
-    <maven.compiler.source>17</maven.compiler.source>
-    <maven.compiler.target>17</maven.compiler.target>
-    <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>
-  </properties>
-
-  <dependencies>
-  </dependencies>
-
-  <build>
-    <plugins>
-      <plugin>
-        <groupId>org.apache.maven.plugins</groupId>
-        <artifactId>maven-compiler-plugin</artifactId>
-        <version>3.11.0</version>
-      </plugin>
-    </plugins>
-  </build>
-</project>
-`
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
 )
 
+// ProjectDirectories are the standard Maven source directories created for every project
+var ProjectDirectories = []string{"src/main/java", "src/main/resources", "src/test/java", "src/test/resources"}
+
 // ProjectInitializer handles project initialization operations.
 type ProjectInitializer struct{}
 
@@ -52,42 +19,125 @@ func NewProjectInitializer() *ProjectInitializer {
 	return &ProjectInitializer{}
 }
 
-// InitProject creates a new Maven project structure in the current directory.
-func (pi *ProjectInitializer) InitProject(path string) error {
-	// Check if pom.xml already exists
-	pomPath := filepath.Join(path, "pom.xml")
-	if _, err := os.Stat(pomPath); err == nil {
-		return fmt.Errorf("pom.xml already exists in %s", path)
+// PreviewProject returns the files InitProject would create from the template, without touching the disk.
+func (pi *ProjectInitializer) PreviewProject(path string, tmpl *ProjectTemplate, spec *domain.ProjectSpec) ([]ProjectFile, error) {
+	files, err := tmpl.Render(spec)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create pom.xml
-	if err := os.WriteFile(pomPath, []byte(DefaultPomTemplate), 0644); err != nil {
-		return fmt.Errorf("failed to create pom.xml: %w", err)
+	if err := pi.CheckProject(path, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// InitProject creates a new Maven project from the template in the specified directory
+// and returns the files it created.
+func (pi *ProjectInitializer) InitProject(path string, tmpl *ProjectTemplate, spec *domain.ProjectSpec) ([]ProjectFile, error) {
+	files, err := pi.PreviewProject(path, tmpl, spec)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create directory structure
-	dirs := []string{
-		filepath.Join(path, "src", "main", "java"),
-		filepath.Join(path, "src", "main", "resources"),
-		filepath.Join(path, "src", "test", "java"),
-		filepath.Join(path, "src", "test", "resources"),
+	if err := pi.createDirectories(path); err != nil {
+		return nil, err
 	}
 
-	for _, dir := range dirs {
+	if err := pi.WriteProject(path, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// PreviewModule returns the files InitModule would create in the module directory dir,
+// without touching the disk.
+func (pi *ProjectInitializer) PreviewModule(dir string, spec *domain.ModuleSpec) ([]ProjectFile, error) {
+	files, err := RenderModule(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render module pom.xml: %w", err)
+	}
+
+	if err := pi.CheckProject(dir, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// InitModule creates a module in the directory dir, with the standard source
+// directories unless it has pom packaging, and returns the files it created.
+func (pi *ProjectInitializer) InitModule(dir string, spec *domain.ModuleSpec) ([]ProjectFile, error) {
+	files, err := pi.PreviewModule(dir, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.HasSources() {
+		if err := pi.createDirectories(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pi.WriteProject(dir, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// createDirectories creates ProjectDirectories under path.
+func (pi *ProjectInitializer) createDirectories(path string) error {
+	for _, dir := range ProjectDirectories {
+		dir = filepath.Join(path, filepath.FromSlash(dir))
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
+	return nil
+}
+
+// CheckProject returns a *domain.ConflictError if path already holds a pom.xml
+// or any of the files, so creating a project never overwrites anything.
+func (pi *ProjectInitializer) CheckProject(path string, files []ProjectFile) error {
+	// Check if pom.xml already exists
+	pomPath := filepath.Join(path, "pom.xml")
+	if _, err := os.Stat(pomPath); err == nil {
+		return &domain.ConflictError{Path: pomPath, Reason: "pom.xml already exists"}
+	}
+
+	for _, file := range files {
+		filePath := filepath.Join(path, filepath.FromSlash(file.Path))
+		if _, err := os.Stat(filePath); err == nil {
+			return &domain.ConflictError{Path: filePath, Reason: "file already exists"}
+		}
+	}
+
+	return nil
+}
+
+// WriteProject creates the files in path, along with their directories, with
+// the mode of each file.
+func (pi *ProjectInitializer) WriteProject(path string, files []ProjectFile) error {
+	for _, file := range files {
+		filePath := filepath.Join(path, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(filePath), err)
+		}
+		if err := os.WriteFile(filePath, file.Content, file.FileMode()); err != nil {
+			return fmt.Errorf("failed to create %s: %w", file.Path, err)
+		}
+	}
 
 	return nil
 }
 
 // FindPomXML searches for pom.xml starting from the given directory and walking up the tree.
 func (pi *ProjectInitializer) FindPomXML(startPath string) (string, error) {
-	currentPath, err := filepath.Abs(startPath)
+	startPath, err := filepath.Abs(startPath)
 	if err != nil {
 		return "", err
 	}
+	currentPath := startPath
 
 	// Walk up the directory tree
 	for {
@@ -108,5 +158,5 @@ func (pi *ProjectInitializer) FindPomXML(startPath string) (string, error) {
 		currentPath = parentPath
 	}
 
-	return "", fmt.Errorf("no pom.xml found in current directory or parent directories")
+	return "", &domain.ProjectNotFoundError{Path: startPath}
 }
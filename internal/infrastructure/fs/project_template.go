@@ -0,0 +1,192 @@
+package fs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const (
+	// DefaultTemplate is the template used by mvnx init when none is given
+	DefaultTemplate = "minimal"
+
+	// packagePlaceholder is a path segment replaced by the package directories,
+	// e.g. "src/main/java/__package__/App.java" becomes "src/main/java/com/example/App.java"
+	packagePlaceholder = "__package__"
+
+	// templateSuffix marks the files rendered with text/template, and is stripped
+	// from their names, so "pom.xml.tmpl" renders "pom.xml"
+	templateSuffix = ".tmpl"
+
+	// defaultFileMode is the mode of files written without one
+	defaultFileMode os.FileMode = 0644
+)
+
+// builtinTemplates holds one directory per built-in template; "all:" keeps the
+// __package__ directories, which embed would otherwise skip
+//
+//go:embed all:templates
+var builtinTemplates embed.FS
+
+// ProjectFile is a file rendered from a project template.
+type ProjectFile struct {
+	// Path is relative to the project directory and slash-separated
+	Path string
+
+	Content []byte
+
+	// Mode holds the permission bits of the file, e.g. 0755 for a script;
+	// zero means 0644
+	Mode os.FileMode
+}
+
+// FileMode returns the permission bits to create the file with.
+func (f ProjectFile) FileMode() os.FileMode {
+	if f.Mode == 0 {
+		return defaultFileMode
+	}
+	return f.Mode
+}
+
+// ProjectTemplate is a directory tree whose *.tmpl files are rendered with
+// text/template and a domain.ProjectSpec, e.g. {{.GroupID}} or {{.Package}}.
+// Other files, such as mvnw or maven-wrapper.jar, are copied as they are.
+type ProjectTemplate struct {
+	// Name is the built-in template name, or the directory of a user-defined template
+	Name string
+
+	files iofs.FS
+}
+
+// TemplateNames returns the names of the built-in templates, sorted.
+func TemplateNames() []string {
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProjectTemplate returns the built-in template with the given name, or
+// the user-defined template in the directory at that path.
+// A user-defined template must contain a pom.xml or pom.xml.tmpl.
+func LoadProjectTemplate(name string) (*ProjectTemplate, error) {
+	for _, builtin := range TemplateNames() {
+		if name == builtin {
+			files, err := iofs.Sub(builtinTemplates, "templates/"+name)
+			if err != nil {
+				return nil, err
+			}
+			return &ProjectTemplate{Name: name, files: files}, nil
+		}
+	}
+
+	info, err := os.Stat(name)
+	if err != nil || !info.IsDir() {
+		return nil, &domain.ValidationError{
+			Field: "template",
+			Message: fmt.Sprintf("unknown template: %s (built-in: %s, or the path of a template directory)",
+				name, strings.Join(TemplateNames(), ", ")),
+		}
+	}
+
+	files := os.DirFS(name)
+	if !hasFile(files, "pom.xml") && !hasFile(files, "pom.xml"+templateSuffix) {
+		return nil, &domain.ValidationError{
+			Field:   "template",
+			Message: fmt.Sprintf("invalid template %s: no pom.xml or pom.xml%s", name, templateSuffix),
+		}
+	}
+
+	return &ProjectTemplate{Name: name, files: files}, nil
+}
+
+// Render renders every file of the template for spec, sorted by path. Files
+// keep the permissions of the template, so scripts stay executable.
+func (t *ProjectTemplate) Render(spec *domain.ProjectSpec) ([]ProjectFile, error) {
+	var files []ProjectFile
+
+	err := iofs.WalkDir(t.files, ".", func(name string, entry iofs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return iofs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		content, err := iofs.ReadFile(t.files, name)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(name, templateSuffix) {
+			if content, err = renderTemplate(name, content, spec); err != nil {
+				return err
+			}
+		}
+
+		// Embedded files are read-only; the project's files must not be
+		files = append(files, ProjectFile{Path: outputPath(name, spec), Content: content, Mode: info.Mode().Perm() | 0200})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// renderTemplate executes the text/template in source for spec.
+func renderTemplate(name string, source []byte, spec *domain.ProjectSpec) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "template", Message: fmt.Sprintf("invalid template %s: %v", name, err)}
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, spec); err != nil {
+		return nil, &domain.ValidationError{Field: "template", Message: fmt.Sprintf("invalid template %s: %v", name, err)}
+	}
+	return content.Bytes(), nil
+}
+
+// outputPath maps a template file name to the path of the rendered file.
+func outputPath(name string, spec *domain.ProjectSpec) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		if segment == packagePlaceholder {
+			segments[i] = spec.PackagePath()
+		}
+	}
+
+	return strings.TrimSuffix(path.Join(segments...), templateSuffix)
+}
+
+// hasFile reports whether a regular file exists in files.
+func hasFile(files iofs.FS, name string) bool {
+	info, err := iofs.Stat(files, name)
+	return err == nil && !info.IsDir()
+}
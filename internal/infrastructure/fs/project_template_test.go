@@ -0,0 +1,135 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func newTestSpec(t *testing.T, pkg string) *domain.ProjectSpec {
+	t.Helper()
+
+	spec, err := domain.NewProjectSpec("com.acme", "order-service", "21", pkg)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestLoadProjectTemplate_BuiltinTemplatesRender(t *testing.T) {
+	spec := newTestSpec(t, "")
+
+	for _, name := range TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := LoadProjectTemplate(name)
+			require.NoError(t, err)
+
+			files, err := tmpl.Render(spec)
+			require.NoError(t, err)
+
+			paths := make([]string, len(files))
+			for i, file := range files {
+				paths[i] = file.Path
+				assert.NotContains(t, string(file.Content), "{{", file.Path)
+			}
+			assert.Contains(t, paths, "pom.xml")
+
+			if name == DefaultTemplate {
+				assert.Equal(t, []string{"pom.xml"}, paths)
+				return
+			}
+			// Every other template ships a starter class and a test in the project package
+			assert.Len(t, filterPrefix(paths, "src/main/java/com/acme/orderservice/"), 1)
+			assert.Len(t, filterPrefix(paths, "src/test/java/com/acme/orderservice/"), 1)
+		})
+	}
+}
+
+func TestLoadProjectTemplate_UserDefined(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "main", "java", "__package__"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".mvn", "wrapper"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml.tmpl"),
+		[]byte("<artifactId>{{.ArtifactID}}</artifactId>\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main", "java", "__package__", "Service.java.tmpl"),
+		[]byte("package {{.Package}};\n"), 0644))
+
+	// Files without the .tmpl suffix are copied as they are, mode included
+	script := []byte("#!/bin/sh\n[ -n \"{{\" ] && exec java \"$@\"\n")
+	jar := []byte{'P', 'K', 3, 4, '{', '{', 0xff, 0}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mvnw"), script, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".mvn", "wrapper", "maven-wrapper.jar"), jar, 0644))
+
+	tmpl, err := LoadProjectTemplate(dir)
+	require.NoError(t, err)
+
+	files, err := tmpl.Render(newTestSpec(t, "com.acme.orders"))
+	require.NoError(t, err)
+	assert.Equal(t, []ProjectFile{
+		{Path: ".mvn/wrapper/maven-wrapper.jar", Content: jar, Mode: 0644},
+		{Path: "mvnw", Content: script, Mode: 0755},
+		{Path: "pom.xml", Content: []byte("<artifactId>order-service</artifactId>\n"), Mode: 0644},
+		{Path: "src/main/java/com/acme/orders/Service.java", Content: []byte("package com.acme.orders;\n"), Mode: 0644},
+	}, files)
+
+	project := t.TempDir()
+	require.NoError(t, NewProjectInitializer().WriteProject(project, files))
+	info, err := os.Stat(filepath.Join(project, "mvnw"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0100, "mvnw must stay executable")
+}
+
+func TestLoadProjectTemplate_Invalid(t *testing.T) {
+	var validationErr *domain.ValidationError
+
+	_, err := LoadProjectTemplate("no-such-template")
+	assert.ErrorAs(t, err, &validationErr)
+
+	// A directory without a pom.xml is not a template
+	_, err = LoadProjectTemplate(t.TempDir())
+	assert.ErrorAs(t, err, &validationErr)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml.tmpl"), []byte("{{.Unknown}}"), 0644))
+	tmpl, err := LoadProjectTemplate(dir)
+	require.NoError(t, err)
+	_, err = tmpl.Render(newTestSpec(t, ""))
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestProjectInitializer_InitProject(t *testing.T) {
+	dir := t.TempDir()
+	tmpl, err := LoadProjectTemplate("app")
+	require.NoError(t, err)
+
+	initializer := NewProjectInitializer()
+	files, err := initializer.InitProject(dir, tmpl, newTestSpec(t, ""))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file.Path)))
+	}
+	for _, sub := range ProjectDirectories {
+		assert.DirExists(t, filepath.Join(dir, filepath.FromSlash(sub)))
+	}
+
+	// Running it again would overwrite the project
+	_, err = initializer.InitProject(dir, tmpl, newTestSpec(t, ""))
+	var conflictErr *domain.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+// filterPrefix returns the paths starting with prefix.
+func filterPrefix(paths []string, prefix string) []string {
+	var matched []string
+	for _, p := range paths {
+		if strings.HasPrefix(p, prefix) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
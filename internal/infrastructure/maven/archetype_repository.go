@@ -0,0 +1,192 @@
+package maven
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const (
+	// MavenCentralRepositoryURL is the base URL of the Maven Central repository
+	MavenCentralRepositoryURL = "https://repo.maven.apache.org/maven2"
+
+	// downloadTimeout applies to catalogs and archetype jars, which can be several megabytes
+	downloadTimeout = 60 * time.Second
+
+	// catalogFile is the name of archetype catalogs, locally and in repositories
+	catalogFile = "archetype-catalog.xml"
+)
+
+// archetypeCatalog is an archetype-catalog.xml document.
+type archetypeCatalog struct {
+	Archetypes []struct {
+		GroupID     string `xml:"groupId"`
+		ArtifactID  string `xml:"artifactId"`
+		Version     string `xml:"version"`
+		Repository  string `xml:"repository"`
+		Description string `xml:"description"`
+	} `xml:"archetypes>archetype"`
+}
+
+// ArchetypeRepository implements domain.ArchetypeRepository with an archetype
+// catalog and the local repository, falling back to a remote repository for
+// jars that are not available locally.
+type ArchetypeRepository struct {
+	// catalog is a file path, an http(s) URL, "local" or "remote"; see NewArchetypeRepository
+	catalog string
+
+	// remoteURL is the base URL of the remote repository, see RemoteRepository
+	remoteURL string
+
+	local      *LocalRepository
+	httpClient *http.Client
+}
+
+// NewArchetypeRepository creates an ArchetypeRepository reading the given catalog:
+// the path or URL of an archetype-catalog.xml, "local" for the catalog of the
+// local repository, or "remote" for the catalog of the remote repository, Maven
+// Central unless the MAVEN_REPO_URL environment variable names another.
+// With an empty catalog, the local catalog is used if it exists.
+func NewArchetypeRepository(catalog string) *ArchetypeRepository {
+	return &ArchetypeRepository{
+		catalog:    catalog,
+		remoteURL:  remoteRepositoryURL(),
+		local:      NewLocalRepository(),
+		httpClient: &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// Find completes the archetype from the catalog.
+// An archetype with a version that is not listed is returned as is, since its
+// jar may still be available in the local or remote repository.
+func (r *ArchetypeRepository) Find(archetype *domain.Archetype) (*domain.Archetype, error) {
+	catalog, err := r.readCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *domain.Archetype
+	for _, entry := range catalog.Archetypes {
+		if entry.GroupID != archetype.GroupID || entry.ArtifactID != archetype.ArtifactID {
+			continue
+		}
+		if archetype.Version != "" && entry.Version != archetype.Version {
+			continue
+		}
+		if found == nil || domain.CompareVersions(entry.Version, found.Version) > 0 {
+			found = &domain.Archetype{
+				GroupID:     entry.GroupID,
+				ArtifactID:  entry.ArtifactID,
+				Version:     entry.Version,
+				Repository:  entry.Repository,
+				Description: strings.TrimSpace(entry.Description),
+			}
+		}
+	}
+
+	switch {
+	case found != nil:
+		return found, nil
+	case archetype.Version != "":
+		return archetype, nil
+	default:
+		return nil, &domain.NotFoundError{Kind: "archetype", Name: archetype.String() + " in the archetype catalog; give a version or a --catalog that lists it"}
+	}
+}
+
+// Fetch returns the archetype jar from the local repository, or downloads it
+// from the archetype's repository. Archetypes listed without one are downloaded
+// from the repository the catalog was read from, or from the remote repository
+// for a local catalog.
+func (r *ArchetypeRepository) Fetch(archetype *domain.Archetype) ([]byte, error) {
+	if r.local.root != "" {
+		path := r.local.artifactPath(archetype.GroupID, archetype.ArtifactID, archetype.Version, "jar")
+		if data, err := os.ReadFile(path); err == nil {
+			return data, nil
+		}
+	}
+
+	base := r.catalogRepositoryURL()
+	if strings.HasPrefix(archetype.Repository, "http://") || strings.HasPrefix(archetype.Repository, "https://") {
+		base = strings.TrimSuffix(archetype.Repository, "/")
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s/%s-%s.jar", base, strings.ReplaceAll(archetype.GroupID, ".", "/"),
+		archetype.ArtifactID, archetype.Version, archetype.ArtifactID, archetype.Version)
+
+	data, err := r.get(url)
+	if err != nil {
+		var netErr *domain.NetworkError
+		if errors.As(err, &netErr) && netErr.StatusCode == http.StatusNotFound {
+			return nil, &domain.NotFoundError{Kind: "archetype", Name: archetype.String()}
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// readCatalog reads the configured catalog. A missing default catalog is empty.
+func (r *ArchetypeRepository) readCatalog() (*archetypeCatalog, error) {
+	location := r.catalog
+	switch location {
+	case "", "local":
+		location = filepath.Join(r.local.root, catalogFile)
+	case "remote":
+		location = r.remoteURL + "/" + catalogFile
+	}
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		data, err = r.get(location)
+	} else {
+		data, err = os.ReadFile(strings.TrimPrefix(location, "file://"))
+		if os.IsNotExist(err) && r.catalog == "" {
+			return &archetypeCatalog{}, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archetype catalog %s: %w", location, err)
+	}
+
+	var catalog archetypeCatalog
+	if err := xml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse archetype catalog %s: %w", location, err)
+	}
+	return &catalog, nil
+}
+
+// catalogRepositoryURL returns the base URL of the repository the catalog was
+// read from: the directory of an http(s) catalog, or else the remote repository.
+func (r *ArchetypeRepository) catalogRepositoryURL() string {
+	if !strings.HasPrefix(r.catalog, "http://") && !strings.HasPrefix(r.catalog, "https://") {
+		return r.remoteURL
+	}
+	return r.catalog[:strings.LastIndex(r.catalog, "/")]
+}
+
+// get downloads url.
+func (r *ArchetypeRepository) get(url string) ([]byte, error) {
+	resp, err := r.httpClient.Get(url)
+	if err != nil {
+		return nil, &domain.NetworkError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &domain.NetworkError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &domain.NetworkError{URL: url, Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+	return data, nil
+}
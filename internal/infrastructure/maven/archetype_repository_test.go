@@ -0,0 +1,152 @@
+package maven
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+const testCatalog = `<?xml version="1.0" encoding="UTF-8"?>
+<archetype-catalog>
+  <archetypes>
+    <archetype>
+      <groupId>com.acme</groupId>
+      <artifactId>service-archetype</artifactId>
+      <version>1.9.0</version>
+    </archetype>
+    <archetype>
+      <groupId>com.acme</groupId>
+      <artifactId>service-archetype</artifactId>
+      <version>1.10.0</version>
+      <description> REST service </description>
+    </archetype>
+    <archetype>
+      <groupId>com.acme</groupId>
+      <artifactId>library-archetype</artifactId>
+      <version>2.0.0</version>
+    </archetype>
+  </archetypes>
+</archetype-catalog>
+`
+
+func TestArchetypeRepositoryFind(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("MAVEN_REPO_LOCAL", root)
+	require.NoError(t, os.WriteFile(filepath.Join(root, catalogFile), []byte(testCatalog), 0644))
+
+	tests := []struct {
+		name        string
+		archetype   string
+		wantVersion string
+		wantErr     bool
+	}{
+		{name: "newest version", archetype: "com.acme:service-archetype", wantVersion: "1.10.0"},
+		{name: "listed version", archetype: "com.acme:service-archetype:1.9.0", wantVersion: "1.9.0"},
+		{name: "unlisted version", archetype: "com.acme:service-archetype:0.1.0", wantVersion: "0.1.0"},
+		{name: "unknown archetype", archetype: "com.acme:unknown-archetype", wantErr: true},
+	}
+
+	repo := NewArchetypeRepository("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested, err := domain.ParseArchetypeCoordinates(tt.archetype)
+			require.NoError(t, err)
+
+			found, err := repo.Find(requested)
+			if tt.wantErr {
+				var notFound *domain.NotFoundError
+				assert.ErrorAs(t, err, &notFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, found.Version)
+		})
+	}
+}
+
+func TestArchetypeRepositoryFetchLocal(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("MAVEN_REPO_LOCAL", root)
+
+	dir := filepath.Join(root, "com", "acme", "service-archetype", "1.10.0")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "service-archetype-1.10.0.jar"), []byte("jar"), 0644))
+
+	data, err := NewArchetypeRepository("").Fetch(&domain.Archetype{GroupID: "com.acme", ArtifactID: "service-archetype", Version: "1.10.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(data))
+}
+
+func TestArchetypeRepositoryFetchRemote(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, ".jar") {
+			_, _ = w.Write([]byte("jar"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	t.Setenv("MAVEN_REPO_LOCAL", t.TempDir())
+	t.Setenv("MAVEN_REPO_URL", server.URL+"/mirror/")
+
+	tests := []struct {
+		name       string
+		catalog    string
+		repository string
+		wantPath   string
+	}{
+		{
+			name:     "catalog repository",
+			catalog:  server.URL + "/releases/archetype-catalog.xml",
+			wantPath: "/releases/com/acme/service-archetype/1.10.0/service-archetype-1.10.0.jar",
+		},
+		{
+			name:       "archetype repository",
+			catalog:    server.URL + "/releases/archetype-catalog.xml",
+			repository: server.URL + "/archetypes/",
+			wantPath:   "/archetypes/com/acme/service-archetype/1.10.0/service-archetype-1.10.0.jar",
+		},
+		{
+			name:     "remote catalog",
+			catalog:  "remote",
+			wantPath: "/mirror/com/acme/service-archetype/1.10.0/service-archetype-1.10.0.jar",
+		},
+		{
+			name:     "local catalog",
+			catalog:  "",
+			wantPath: "/mirror/com/acme/service-archetype/1.10.0/service-archetype-1.10.0.jar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			repo := NewArchetypeRepository(tt.catalog)
+			repo.httpClient = server.Client()
+
+			data, err := repo.Fetch(&domain.Archetype{
+				GroupID: "com.acme", ArtifactID: "service-archetype", Version: "1.10.0", Repository: tt.repository,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "jar", string(data))
+			assert.Equal(t, []string{tt.wantPath}, requested)
+		})
+	}
+
+	// --catalog remote reads the catalog of MAVEN_REPO_URL
+	requested = nil
+	_, err := NewArchetypeRepository("remote").Find(&domain.Archetype{GroupID: "com.acme", ArtifactID: "service-archetype"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"/mirror/archetype-catalog.xml"}, requested)
+}
@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
 )
 
 const (
@@ -61,23 +63,27 @@ func (c *Client) Search(query string, rows int) (*SearchResponse, error) {
 
 	resp, err := c.httpClient.Get(fullURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query Maven Central: %w", err)
+		return nil, &domain.NetworkError{URL: c.baseURL, Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Maven Central API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &domain.NetworkError{
+			URL:        c.baseURL,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("Maven Central API returned status %d: %s", resp.StatusCode, string(body)),
+		}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, &domain.NetworkError{URL: c.baseURL, Err: fmt.Errorf("failed to read response body: %w", err)}
 	}
 
 	var searchResp SearchResponse
 	if err := json.Unmarshal(body, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+		return nil, &domain.NetworkError{URL: c.baseURL, Err: fmt.Errorf("failed to parse JSON response: %w", err)}
 	}
 
 	return &searchResp, nil
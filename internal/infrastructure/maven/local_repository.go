@@ -0,0 +1,118 @@
+package maven
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// LocalRepository implements domain.PomLocator on top of the local Maven
+// repository (~/.m2/repository), where Maven caches every pom.xml it downloads.
+type LocalRepository struct {
+	root string
+}
+
+// NewLocalRepository creates a LocalRepository for the current user.
+// The MAVEN_REPO_LOCAL environment variable overrides the default location.
+func NewLocalRepository() *LocalRepository {
+	root := os.Getenv("MAVEN_REPO_LOCAL")
+	if root == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, ".m2", "repository")
+		}
+	}
+	return &LocalRepository{root: root}
+}
+
+// Locate returns the path of the pom.xml of groupId:artifactId:version in the local repository.
+func (r *LocalRepository) Locate(groupID, artifactID, version string) (string, error) {
+	coordinates := groupID + ":" + artifactID + ":" + version
+
+	if r.root == "" || groupID == "" || artifactID == "" || version == "" {
+		return "", &domain.NotFoundError{Kind: "pom", Name: coordinates}
+	}
+
+	path := r.artifactPath(groupID, artifactID, version, "pom")
+
+	if _, err := os.Stat(path); err != nil {
+		return "", &domain.NotFoundError{Kind: "pom", Name: coordinates}
+	}
+
+	return path, nil
+}
+
+// artifactPath returns where the local repository stores an artifact with the given extension.
+func (r *LocalRepository) artifactPath(groupID, artifactID, version, extension string) string {
+	return filepath.Join(r.root, filepath.FromSlash(strings.ReplaceAll(groupID, ".", "/")),
+		artifactID, version, artifactID+"-"+version+"."+extension)
+}
+
+// JavaRelease returns the highest Java release targeted by the classes in the jar of
+// dep at the given version, read from the class file headers. module-info.class and
+// the versioned entries of multi-release jars are ignored, since they target newer
+// releases on purpose while the rest of the jar still runs on older ones.
+func (r *LocalRepository) JavaRelease(dep *domain.Dependency, version string) (int, error) {
+	name := dep.ArtifactID + "-" + version
+	if dep.Classifier != "" {
+		name += "-" + dep.Classifier
+	}
+	coordinates := dep.GroupID + ":" + dep.ArtifactID + ":" + version
+
+	if r.root == "" {
+		return 0, &domain.NotFoundError{Kind: "jar", Name: coordinates}
+	}
+	jarPath := filepath.Join(filepath.Dir(r.artifactPath(dep.GroupID, dep.ArtifactID, version, "jar")), name+".jar")
+
+	archive, err := zip.OpenReader(jarPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, &domain.NotFoundError{Kind: "jar", Name: coordinates}
+		}
+		return 0, fmt.Errorf("failed to open %s: %w", jarPath, err)
+	}
+	defer archive.Close()
+
+	release := 0
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".class") || strings.HasPrefix(file.Name, "META-INF/") ||
+			path.Base(file.Name) == "module-info.class" {
+			continue
+		}
+
+		major, err := classFileMajor(file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s in %s: %w", file.Name, jarPath, err)
+		}
+		if classRelease := domain.JavaReleaseOfClassFile(major); classRelease > release {
+			release = classRelease
+		}
+	}
+
+	return release, nil
+}
+
+// classFileMajor reads the major version from the header of a class file:
+// a 0xCAFEBABE magic number, then the minor and major versions as big-endian uint16.
+func classFileMajor(file *zip.File) (int, error) {
+	r, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	if binary.BigEndian.Uint32(header[0:4]) != 0xCAFEBABE {
+		return 0, fmt.Errorf("not a class file")
+	}
+	return int(binary.BigEndian.Uint16(header[6:8])), nil
+}
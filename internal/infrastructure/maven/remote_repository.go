@@ -0,0 +1,79 @@
+package maven
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+// RemoteRepository implements domain.PomLocator on top of a LocalRepository,
+// downloading into it the poms it does not have yet, as Maven does.
+type RemoteRepository struct {
+	local      *LocalRepository
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewRemoteRepository creates a RemoteRepository downloading from Maven Central
+// into local. The MAVEN_REPO_URL environment variable overrides the remote.
+func NewRemoteRepository(local *LocalRepository) *RemoteRepository {
+	return &RemoteRepository{
+		local:      local,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		baseURL:    remoteRepositoryURL(),
+	}
+}
+
+// remoteRepositoryURL returns the base URL of the remote repository: the
+// MAVEN_REPO_URL environment variable, or Maven Central.
+func remoteRepositoryURL() string {
+	baseURL := os.Getenv("MAVEN_REPO_URL")
+	if baseURL == "" {
+		baseURL = MavenCentralRepositoryURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// Locate returns the path of the pom.xml of groupId:artifactId:version in the
+// local repository, downloading it first if needed. Returns a *NotFoundError if
+// the remote does not have it either, and a *NetworkError if it cannot be reached.
+func (r *RemoteRepository) Locate(groupID, artifactID, version string) (string, error) {
+	path, err := r.local.Locate(groupID, artifactID, version)
+	if err == nil || r.local.root == "" || groupID == "" || artifactID == "" || version == "" {
+		return path, err
+	}
+
+	url := r.baseURL + "/" + strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID + "/" + version +
+		"/" + artifactID + "-" + version + ".pom"
+	resp, err := r.httpClient.Get(url)
+	if err != nil {
+		return "", &domain.NetworkError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", &domain.NotFoundError{Kind: "pom", Name: groupID + ":" + artifactID + ":" + version}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &domain.NetworkError{URL: url, StatusCode: resp.StatusCode}
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &domain.NetworkError{URL: url, Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+
+	path = r.local.artifactPath(groupID, artifactID, version, "pom")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := fs.WriteFileAtomic(path, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,52 @@
+package maven
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+func TestRemoteRepository_Locate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/org/example/lib/1.0/lib-1.0.pom":
+			_, _ = w.Write([]byte("<project/>"))
+		case "/org/example/broken/1.0/broken-1.0.pom":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	local := &LocalRepository{root: t.TempDir()}
+	remote := &RemoteRepository{local: local, httpClient: server.Client(), baseURL: server.URL}
+
+	path, err := remote.Locate("org.example", "lib", "1.0")
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "<project/>", string(content))
+
+	// The second lookup is served by the local repository
+	_, err = remote.Locate("org.example", "lib", "1.0")
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	var notFoundErr *domain.NotFoundError
+	_, err = remote.Locate("org.example", "missing", "1.0")
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	var networkErr *domain.NetworkError
+	_, err = remote.Locate("org.example", "broken", "1.0")
+	require.ErrorAs(t, err, &networkErr)
+	assert.Equal(t, http.StatusInternalServerError, networkErr.StatusCode)
+}
@@ -1,7 +1,6 @@
 package maven
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
@@ -52,7 +51,7 @@ func (r *Resolver) ResolveExact(groupID, artifactID string) (*domain.ArtifactSea
 	}
 
 	if resp.Response.NumFound == 0 {
-		return nil, fmt.Errorf("artifact not found: %s:%s", groupID, artifactID)
+		return nil, &domain.NotFoundError{Kind: "artifact", Name: groupID + ":" + artifactID}
 	}
 
 	doc := resp.Response.Docs[0]
@@ -60,7 +59,7 @@ func (r *Resolver) ResolveExact(groupID, artifactID string) (*domain.ArtifactSea
 	// Ensure version is stable
 	version := doc.LatestVersion
 	if !IsStableVersion(version) {
-		return nil, fmt.Errorf("no stable version found for %s:%s (latest: %s)", groupID, artifactID, version)
+		return nil, &domain.NoStableVersionError{Coordinates: groupID + ":" + artifactID, Latest: version}
 	}
 
 	return domain.NewArtifactSearchResult(
@@ -80,7 +79,7 @@ func (r *Resolver) fuzzySearch(query string) ([]*domain.ArtifactSearchResult, er
 	}
 
 	if resp.Response.NumFound == 0 {
-		return nil, fmt.Errorf("no artifacts found for query: %s", query)
+		return nil, &domain.NotFoundError{Kind: "artifact", Name: query}
 	}
 
 	results := make([]*domain.ArtifactSearchResult, 0)
@@ -114,7 +113,7 @@ func (r *Resolver) fuzzySearch(query string) ([]*domain.ArtifactSearchResult, er
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("no stable versions found for query: %s", query)
+		return nil, &domain.NoStableVersionError{Coordinates: query}
 	}
 
 	return results, nil
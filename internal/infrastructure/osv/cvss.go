@@ -0,0 +1,92 @@
+package osv
+
+import (
+	"math"
+	"strings"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// cvss3Weights holds the weights of the CVSS v3 base metrics by metric and value.
+// The privileges required weigh more when the scope changes, see cvss3Score.
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3Severity rates a CVSS v3 vector such as "CVSS:3.1/AV:N/AC:L/...", or
+// returns AdvisoryUnknown if it cannot be parsed.
+func cvss3Severity(vector string) domain.AdvisorySeverity {
+	score, ok := cvss3Score(vector)
+	switch {
+	case !ok || score == 0:
+		return domain.AdvisoryUnknown
+	case score < 4:
+		return domain.AdvisoryLow
+	case score < 7:
+		return domain.AdvisoryModerate
+	case score < 9:
+		return domain.AdvisoryHigh
+	default:
+		return domain.AdvisoryCritical
+	}
+}
+
+// cvss3Score computes the base score of a CVSS v3 vector, as specified by FIRST.
+func cvss3Score(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		if name, value, ok := strings.Cut(part, ":"); ok {
+			metrics[name] = value
+		}
+	}
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+
+	weights := make(map[string]float64)
+	for name, values := range cvss3Weights {
+		weight, ok := values[metrics[name]]
+		if !ok {
+			return 0, false
+		}
+		weights[name] = weight
+	}
+	if changed && metrics["PR"] == "L" {
+		weights["PR"] = 0.68
+	} else if changed && metrics["PR"] == "H" {
+		weights["PR"] = 0.5
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * weights["PR"] * weights["UI"]
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	return roundUp(math.Min(score, 10)), true
+}
+
+// roundUp rounds up to one decimal, as CVSS does.
+func roundUp(score float64) float64 {
+	return math.Ceil(score*10-1e-9) / 10
+}
@@ -0,0 +1,290 @@
+// Package osv stores the Maven advisories of an OSV database export, such as
+// the all.zip published for the Maven ecosystem by osv.dev or a GitHub Advisory
+// Database dump, for offline lookups.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
+)
+
+// databaseFile is the name of the imported database within its directory
+const databaseFile = "maven-advisories.json"
+
+// Database implements domain.AdvisoryDatabase with the advisories imported from
+// an OSV zip export, stored as a single JSON file.
+type Database struct {
+	dir string
+
+	// packages is loaded on the first lookup
+	packages map[string][]*domain.Advisory
+}
+
+// NewDatabase creates a Database in the user cache directory.
+// The MVNX_ADVISORY_DB environment variable overrides the location.
+func NewDatabase() *Database {
+	dir := os.Getenv("MVNX_ADVISORY_DB")
+	if dir == "" {
+		if cache, err := os.UserCacheDir(); err == nil {
+			dir = filepath.Join(cache, "mvnx", "osv")
+		}
+	}
+	return &Database{dir: dir}
+}
+
+// Path returns where the imported database is stored.
+func (d *Database) Path() string {
+	return filepath.Join(d.dir, databaseFile)
+}
+
+// ImportSummary describes an imported database.
+type ImportSummary struct {
+	// Source is the zip file the advisories were imported from
+	Source   string    `json:"source"`
+	Imported time.Time `json:"imported"`
+
+	// Advisories counts the advisories kept, and Packages the artifacts they affect
+	Advisories int `json:"advisories"`
+	Packages   int `json:"packages"`
+}
+
+// databaseRecord is the on-disk representation of the database.
+type databaseRecord struct {
+	Summary  ImportSummary               `json:"summary"`
+	Packages map[string][]advisoryRecord `json:"packages"`
+}
+
+// advisoryRecord is the on-disk representation of an advisory.
+type advisoryRecord struct {
+	ID       string          `json:"id"`
+	Aliases  []string        `json:"aliases,omitempty"`
+	Summary  string          `json:"summary,omitempty"`
+	Severity string          `json:"severity"`
+	Ranges   [][]eventRecord `json:"ranges,omitempty"`
+	Versions []string        `json:"versions,omitempty"`
+}
+
+// eventRecord is the on-disk representation of a range event, as in OSV.
+type eventRecord struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// osvEntry is the part of an OSV entry the database keeps.
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string        `json:"type"`
+			Events []eventRecord `json:"events"`
+		} `json:"ranges"`
+		Versions         []string `json:"versions"`
+		DatabaseSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"database_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// Import replaces the database with the Maven advisories of an OSV zip export,
+// one JSON entry per file. Withdrawn advisories and other ecosystems are skipped.
+func (d *Database) Import(zipPath string) (*ImportSummary, error) {
+	if d.dir == "" {
+		return nil, fmt.Errorf("no location for the advisory database; set MVNX_ADVISORY_DB")
+	}
+
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &domain.NotFoundError{Kind: "file", Name: zipPath}
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", zipPath, err)
+	}
+	defer archive.Close()
+
+	record := databaseRecord{Packages: make(map[string][]advisoryRecord)}
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		entry, err := readEntry(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in %s: %w", file.Name, zipPath, err)
+		}
+		if entry.Withdrawn != "" {
+			continue
+		}
+
+		kept := false
+		for _, affected := range entry.Affected {
+			name := affected.Package.Name
+			if affected.Package.Ecosystem != "Maven" || !strings.Contains(name, ":") {
+				continue
+			}
+
+			advisory := advisoryRecord{
+				ID:       entry.ID,
+				Aliases:  entry.Aliases,
+				Summary:  entry.Summary,
+				Severity: string(entrySeverity(entry, affected.DatabaseSpecific.Severity)),
+				Versions: affected.Versions,
+			}
+			for _, r := range affected.Ranges {
+				// Maven ranges are ECOSYSTEM ranges; GIT and SEMVER ranges do not apply
+				if r.Type == "ECOSYSTEM" {
+					advisory.Ranges = append(advisory.Ranges, r.Events)
+				}
+			}
+			record.Packages[name] = append(record.Packages[name], advisory)
+			kept = true
+		}
+		if kept {
+			record.Summary.Advisories++
+		}
+	}
+
+	for _, advisories := range record.Packages {
+		sort.Slice(advisories, func(i, j int) bool { return advisories[i].ID < advisories[j].ID })
+	}
+	record.Summary.Source, _ = filepath.Abs(zipPath)
+	record.Summary.Imported = time.Now().UTC().Truncate(time.Second)
+	record.Summary.Packages = len(record.Packages)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the advisory database: %w", err)
+	}
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", d.dir, err)
+	}
+	if err := fs.WriteFileAtomic(d.Path(), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write the advisory database: %w", err)
+	}
+
+	d.packages = nil
+	return &record.Summary, nil
+}
+
+// readEntry decodes an OSV entry from the zip file.
+func readEntry(file *zip.File) (*osvEntry, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var entry osvEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// entrySeverity returns the severity rating of an OSV entry: the one of GitHub
+// advisories when there is one, else that of the affected package, else the
+// rating of its CVSS v3 score.
+func entrySeverity(entry *osvEntry, affectedSeverity string) domain.AdvisorySeverity {
+	if severity := domain.ParseAdvisorySeverity(entry.DatabaseSpecific.Severity); severity != domain.AdvisoryUnknown {
+		return severity
+	}
+	if severity := domain.ParseAdvisorySeverity(affectedSeverity); severity != domain.AdvisoryUnknown {
+		return severity
+	}
+	for _, s := range entry.Severity {
+		if s.Type == "CVSS_V3" {
+			return cvss3Severity(s.Score)
+		}
+	}
+	return domain.AdvisoryUnknown
+}
+
+// Summary returns the summary of the imported database.
+// Returns a *NotFoundError if no database has been imported.
+func (d *Database) Summary() (*ImportSummary, error) {
+	record, err := d.read()
+	if err != nil {
+		return nil, err
+	}
+	return &record.Summary, nil
+}
+
+// Advisories returns the advisories affecting some version of groupId:artifactId.
+// Returns a *NotFoundError if no database has been imported.
+func (d *Database) Advisories(groupID, artifactID string) ([]*domain.Advisory, error) {
+	if d.packages == nil {
+		record, err := d.read()
+		if err != nil {
+			return nil, err
+		}
+
+		d.packages = make(map[string][]*domain.Advisory, len(record.Packages))
+		for name, advisories := range record.Packages {
+			for _, a := range advisories {
+				advisory := &domain.Advisory{
+					ID:       a.ID,
+					Aliases:  a.Aliases,
+					Summary:  a.Summary,
+					Severity: domain.ParseAdvisorySeverity(a.Severity),
+					Package:  name,
+					Versions: a.Versions,
+				}
+				for _, events := range a.Ranges {
+					converted := make([]domain.AdvisoryEvent, len(events))
+					for i, e := range events {
+						converted[i] = domain.AdvisoryEvent{Introduced: e.Introduced, Fixed: e.Fixed, LastAffected: e.LastAffected}
+					}
+					advisory.Ranges = append(advisory.Ranges, converted)
+				}
+				d.packages[name] = append(d.packages[name], advisory)
+			}
+		}
+	}
+
+	return d.packages[groupID+":"+artifactID], nil
+}
+
+// read loads the database file.
+func (d *Database) read() (*databaseRecord, error) {
+	data, err := os.ReadFile(d.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &domain.NotFoundError{Kind: "advisory database", Name: d.Path() + " (run 'mvnx audit import <zip>' first)"}
+		}
+		return nil, fmt.Errorf("failed to read the advisory database: %w", err)
+	}
+
+	var record databaseRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode the advisory database %s: %w", d.Path(), err)
+	}
+	return &record, nil
+}
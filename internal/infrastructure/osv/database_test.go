@@ -0,0 +1,119 @@
+package osv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// writeZip writes an OSV export with the given entries, by file name.
+func writeZip(t *testing.T, entries map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "all.zip")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	for name, contents := range entries {
+		entry, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return path
+}
+
+func TestDatabase_Import(t *testing.T) {
+	zipPath := writeZip(t, map[string]string{
+		"GHSA-jfh8-c2jp-5v3q.json": `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [
+      {"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.15.0"}]},
+      {"type": "GIT", "events": [{"introduced": "0"}, {"fixed": "abc123"}]}
+    ]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`,
+		"OSV-2024-1.json": `{
+  "id": "OSV-2024-1",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N"}],
+  "affected": [{"package": {"ecosystem": "Maven", "name": "org.example:lib"}, "versions": ["1.0"]}]
+}`,
+		"GHSA-withdrawn.json": `{"id": "GHSA-withdrawn", "withdrawn": "2024-01-01T00:00:00Z",
+  "affected": [{"package": {"ecosystem": "Maven", "name": "org.example:lib"}}]}`,
+		"PYSEC-1.json": `{"id": "PYSEC-1", "affected": [{"package": {"ecosystem": "PyPI", "name": "requests"}}]}`,
+	})
+
+	database := &Database{dir: t.TempDir()}
+	summary, err := database.Import(zipPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Advisories)
+	assert.Equal(t, 2, summary.Packages)
+
+	// A fresh Database reads what was stored
+	database = &Database{dir: database.dir}
+	advisories, err := database.Advisories("org.apache.logging.log4j", "log4j-core")
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Advisory{{
+		ID:       "GHSA-jfh8-c2jp-5v3q",
+		Aliases:  []string{"CVE-2021-44228"},
+		Summary:  "Remote code injection in Log4j",
+		Severity: domain.AdvisoryCritical,
+		Package:  "org.apache.logging.log4j:log4j-core",
+		Ranges:   [][]domain.AdvisoryEvent{{{Introduced: "2.13.0"}, {Fixed: "2.15.0"}}},
+	}}, advisories)
+
+	advisories, err = database.Advisories("org.example", "lib")
+	require.NoError(t, err)
+	require.Len(t, advisories, 1)
+	assert.Equal(t, domain.AdvisoryModerate, advisories[0].Severity)
+
+	advisories, err = database.Advisories("org.example", "other")
+	require.NoError(t, err)
+	assert.Empty(t, advisories)
+}
+
+func TestDatabase_AdvisoriesWithoutImport(t *testing.T) {
+	database := &Database{dir: t.TempDir()}
+
+	_, err := database.Advisories("org.example", "lib")
+	var notFound *domain.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestCVSS3Score(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", want: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", want: 10.0},
+		{vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", want: 5.9},
+		{vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:L/I:N/A:N", want: 3.3},
+		{vector: "CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", want: 6.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			score, ok := cvss3Score(tt.vector)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, score)
+		})
+	}
+
+	_, ok := cvss3Score("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	assert.False(t, ok)
+}
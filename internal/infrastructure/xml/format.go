@@ -0,0 +1,287 @@
+package xml
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// defaultIndent is used when a document has no indented elements to learn from
+const defaultIndent = "  "
+
+// style describes the whitespace conventions of a document.
+// New nodes are formatted with it so they blend in with their siblings.
+type style struct {
+	// newline is "\r\n" for documents with Windows line endings, "\n" otherwise
+	newline string
+
+	// indent is one level of indentation, e.g. two spaces or a tab
+	indent string
+}
+
+// detectStyle infers the newline and indentation conventions of a document.
+func detectStyle(data []byte, doc *etree.Document) style {
+	st := style{newline: "\n", indent: defaultIndent}
+
+	if bytes.Contains(data, []byte("\r\n")) {
+		st.newline = "\r\n"
+	}
+
+	// The root is normally at column 0, so its children are indented by one level
+	if root := doc.Root(); root != nil {
+		for _, child := range root.ChildElements() {
+			if indent, ok := leadingIndent(child); ok && indent != "" {
+				st.indent = indent
+				break
+			}
+		}
+	}
+
+	return st
+}
+
+// leadingIndent returns the indentation of an element, taken from the whitespace
+// between the preceding newline and its start tag.
+// The second result is false when the element does not start on its own line.
+func leadingIndent(elem *etree.Element) (string, bool) {
+	parent := elem.Parent()
+	if parent == nil || elem.Index() == 0 {
+		return "", false
+	}
+
+	cd, ok := parent.Child[elem.Index()-1].(*etree.CharData)
+	if !ok || !isWhitespace(cd) {
+		return "", false
+	}
+
+	i := strings.LastIndex(cd.Data, "\n")
+	if i < 0 {
+		return "", false
+	}
+
+	return cd.Data[i+1:], true
+}
+
+// indentOf returns the indentation of an element, deriving it from its
+// ancestors when the element does not start on its own line.
+func (st style) indentOf(elem *etree.Element) string {
+	if indent, ok := leadingIndent(elem); ok {
+		return indent
+	}
+
+	parent := elem.Parent()
+	if parent == nil || parent.Parent() == nil {
+		// The root element, or an element directly under the document
+		return ""
+	}
+
+	return st.indentOf(parent) + st.indent
+}
+
+// appendChild adds child as the last element of parent, on its own line and
+// indented like its siblings. Trailing whitespace before the parent's end tag
+// and the separator used between siblings (including blank lines) are preserved.
+func (st style) appendChild(parent, child *etree.Element) {
+	siblings := parent.ChildElements()
+
+	separator := ""
+	if len(siblings) > 0 {
+		last := siblings[len(siblings)-1]
+		if cd, ok := precedingWhitespace(last); ok && strings.Contains(cd.Data, "\n") {
+			separator = cd.Data
+		}
+	}
+	if separator == "" {
+		separator = "\n" + st.indentOf(parent) + st.indent
+	}
+
+	// Insert before the whitespace that precedes the parent's end tag, if any
+	index := len(parent.Child)
+	if index > 0 {
+		if cd, ok := parent.Child[index-1].(*etree.CharData); ok && isWhitespace(cd) && strings.Contains(cd.Data, "\n") {
+			index--
+		}
+	}
+
+	if index == len(parent.Child) {
+		// No closing whitespace yet: add it so the end tag lands on its own line
+		parent.AddChild(st.newWhitespace("\n" + st.indentOf(parent)))
+	}
+
+	parent.InsertChildAt(index, st.newWhitespace(separator))
+	parent.InsertChildAt(index+1, child)
+}
+
+// appendTextElement creates <tag>text</tag> as the last element of parent.
+func (st style) appendTextElement(parent *etree.Element, tag, text string) *etree.Element {
+	elem := etree.NewElement(tag)
+	elem.SetText(text)
+	st.appendChild(parent, elem)
+	return elem
+}
+
+// insertTextElementAfter creates <tag>text</tag> right after sibling, on its own
+// line and indented like it.
+func (st style) insertTextElementAfter(sibling *etree.Element, tag, text string) *etree.Element {
+	elem := etree.NewElement(tag)
+	elem.SetText(text)
+
+	index := sibling.Index() + 1
+	parent := sibling.Parent()
+	parent.InsertChildAt(index, st.newWhitespace("\n"+st.indentOf(sibling)))
+	parent.InsertChildAt(index+1, elem)
+
+	return elem
+}
+
+// insertChildBefore adds child right before sibling, separated from it the way
+// sibling is separated from the element before it, so blank lines between
+// sections are kept.
+func (st style) insertChildBefore(sibling, child *etree.Element) {
+	separator := "\n" + st.indentOf(sibling)
+	if cd, ok := precedingWhitespace(sibling); ok && strings.Contains(cd.Data, "\n") {
+		separator = cd.Data
+	}
+
+	index := sibling.Index()
+	parent := sibling.Parent()
+	parent.InsertChildAt(index, child)
+	parent.InsertChildAt(index+1, st.newWhitespace(separator))
+}
+
+// removeChild removes child from its parent together with the whitespace that
+// precedes it, so no empty line is left behind.
+func removeChild(child *etree.Element) {
+	parent := child.Parent()
+	if parent == nil {
+		return
+	}
+
+	if cd, ok := precedingWhitespace(child); ok {
+		parent.RemoveChild(cd)
+	}
+	parent.RemoveChild(child)
+}
+
+// reindent rewrites the whitespace that starts a new line inside elem, whose own
+// indentation is indent, so every child is one level deeper than its parent.
+// Child elements written on the same line as their parent or a sibling, e.g.
+// <build><plugins>, are moved to their own line first. Runs of blank lines
+// become a single one, and none is kept after a start tag. Elements holding
+// text besides whitespace are left as they are.
+func (st style) reindent(elem *etree.Element, indent string) {
+	for _, tok := range elem.Child {
+		if cd, ok := tok.(*etree.CharData); ok && !isWhitespace(cd) {
+			return
+		}
+	}
+	st.breakLines(elem)
+
+	last := len(elem.Child) - 1
+	for i, tok := range elem.Child {
+		switch tok := tok.(type) {
+		case *etree.Element:
+			st.reindent(tok, indent+st.indent)
+		case *etree.CharData:
+			data := strings.ReplaceAll(tok.Data, "\r\n", "\n")
+			if !strings.Contains(data, "\n") {
+				continue
+			}
+
+			want := "\n"
+			if i > 0 && strings.Count(data, "\n") > 1 {
+				want = "\n\n"
+			}
+			if i == last {
+				want += indent
+			} else {
+				want += indent + st.indent
+			}
+
+			if data != want {
+				tok.SetData(strings.ReplaceAll(want, "\n", st.newline))
+			}
+		}
+	}
+}
+
+// breakLines starts a new line before each child element of elem that follows its
+// start tag or another element on the same line, and before its end tag. Comments
+// on the line of an element stay there. reindent then indents the new lines.
+func (st style) breakLines(elem *etree.Element) {
+	if len(elem.ChildElements()) == 0 {
+		return
+	}
+
+	// afterElement reports whether the token at i follows the start tag or an element
+	afterElement := func(i int) bool {
+		if i < 0 {
+			return true
+		}
+		_, ok := elem.Child[i].(*etree.Element)
+		return ok
+	}
+
+	for i := 0; i <= len(elem.Child); i++ {
+		if i < len(elem.Child) {
+			if _, ok := elem.Child[i].(*etree.Element); !ok {
+				continue
+			}
+		} else if i == 0 {
+			break
+		}
+
+		// The token before the element, or before the end tag
+		switch prev := childAt(elem, i-1).(type) {
+		case *etree.CharData:
+			if isWhitespace(prev) && !strings.Contains(prev.Data, "\n") && afterElement(i-2) {
+				prev.SetData("\n")
+			}
+		case *etree.Element, nil:
+			elem.InsertChildAt(i, st.newWhitespace("\n"))
+			i++
+		}
+	}
+}
+
+// childAt returns the child token of elem at index i, or nil if there is none.
+func childAt(elem *etree.Element, i int) etree.Token {
+	if i < 0 || i >= len(elem.Child) {
+		return nil
+	}
+	return elem.Child[i]
+}
+
+// precedingWhitespace returns the whitespace-only text token directly before elem.
+func precedingWhitespace(elem *etree.Element) (*etree.CharData, bool) {
+	parent := elem.Parent()
+	if parent == nil || elem.Index() == 0 {
+		return nil, false
+	}
+
+	cd, ok := parent.Child[elem.Index()-1].(*etree.CharData)
+	if !ok || !isWhitespace(cd) {
+		return nil, false
+	}
+
+	return cd, true
+}
+
+// newWhitespace creates a text token holding formatting whitespace.
+// The XML decoder normalizes line endings to "\n", so whitespace copied from
+// parsed tokens is converted back to the document's newline style. Whitespace
+// copied from tokens created by newWhitespace already uses that style.
+func (st style) newWhitespace(data string) *etree.CharData {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+
+	cd := etree.NewText("")
+	cd.SetData(strings.ReplaceAll(data, "\n", st.newline))
+	return cd
+}
+
+// isWhitespace reports whether a text token holds only formatting whitespace.
+// Unlike CharData.IsWhitespace it also recognizes tokens created after parsing.
+func isWhitespace(cd *etree.CharData) bool {
+	return !cd.IsCData() && strings.TrimSpace(cd.Data) == ""
+}
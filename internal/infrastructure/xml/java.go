@@ -0,0 +1,140 @@
+package xml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// compilerParameters are the maven-compiler-plugin parameters holding a Java release level
+var compilerParameters = []string{"release", "source", "target", "testRelease", "testSource", "testTarget"}
+
+// toolchainVersionPath locates the JDK version in a maven-toolchains-plugin <configuration>
+const toolchainVersionPath = "toolchains/jdk/version"
+
+// javaElement is a JavaDeclaration with the element it was read from.
+type javaElement struct {
+	decl *domain.JavaDeclaration
+	elem *etree.Element
+}
+
+// pluginConfiguration is a <configuration> of a plugin or of one of its executions.
+type pluginConfiguration struct {
+	location string
+	elem     *etree.Element
+}
+
+// GetJavaDeclarations returns the places where the Java release level is set, in
+// document order: properties first, then plugins, then managed plugins.
+func (p *PomRepository) GetJavaDeclarations() ([]*domain.JavaDeclaration, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	elements := p.javaElements()
+	declarations := make([]*domain.JavaDeclaration, len(elements))
+	for i, e := range elements {
+		declarations[i] = e.decl
+	}
+	return declarations, nil
+}
+
+// SetJavaDeclaration sets the value of the declaration at location.
+func (p *PomRepository) SetJavaDeclaration(location, value string) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	for _, e := range p.javaElements() {
+		if e.decl.Location == location {
+			e.elem.SetText(value)
+			return nil
+		}
+	}
+	return &domain.NotFoundError{Kind: "Java declaration", Name: location}
+}
+
+// javaElements finds the elements holding a Java release level.
+func (p *PomRepository) javaElements() []javaElement {
+	var result []javaElement
+	add := func(location, kind string, elem *etree.Element) {
+		result = append(result, javaElement{
+			decl: &domain.JavaDeclaration{Location: location, Kind: kind, Value: strings.TrimSpace(elem.Text())},
+			elem: elem,
+		})
+	}
+
+	if properties := p.doc.Root().SelectElement("properties"); properties != nil {
+		for _, elem := range properties.ChildElements() {
+			for _, name := range domain.JavaProperties {
+				if elem.Tag == name {
+					add("properties/"+name, domain.JavaProperty, elem)
+				}
+			}
+		}
+	}
+
+	for _, managed := range []bool{false, true} {
+		plugins := p.pluginsElement(managed, false)
+		if plugins == nil {
+			continue
+		}
+		prefix := "build/plugins/"
+		if managed {
+			prefix = "build/pluginManagement/plugins/"
+		}
+
+		for _, plugin := range plugins.SelectElements("plugin") {
+			declared := readPlugin(plugin, managed)
+			if declared.GroupID != domain.DefaultPluginGroupID {
+				continue
+			}
+
+			for _, config := range pluginConfigurations(plugin, prefix+declared.ArtifactID) {
+				switch declared.ArtifactID {
+				case "maven-compiler-plugin":
+					for _, name := range compilerParameters {
+						if elem := config.elem.SelectElement(name); elem != nil {
+							add(config.location+"/"+name, domain.JavaCompilerPlugin, elem)
+						}
+					}
+				case "maven-toolchains-plugin":
+					if elem := config.elem.FindElement(toolchainVersionPath); elem != nil {
+						add(config.location+"/"+toolchainVersionPath, domain.JavaToolchain, elem)
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// pluginConfigurations returns the <configuration> of a plugin followed by those of
+// its executions, with their locations, e.g. "…/executions/default-compile/configuration".
+func pluginConfigurations(plugin *etree.Element, location string) []pluginConfiguration {
+	var result []pluginConfiguration
+	if config := plugin.SelectElement("configuration"); config != nil {
+		result = append(result, pluginConfiguration{location: location + "/configuration", elem: config})
+	}
+
+	if executions := plugin.SelectElement("executions"); executions != nil {
+		for _, execution := range executions.SelectElements("execution") {
+			config := execution.SelectElement("configuration")
+			if config == nil {
+				continue
+			}
+			// Maven names executions without an <id> "default"
+			id := childText(execution, "id")
+			if id == "" {
+				id = "default"
+			}
+			result = append(result, pluginConfiguration{location: location + "/executions/" + id + "/configuration", elem: config})
+		}
+	}
+
+	return result
+}
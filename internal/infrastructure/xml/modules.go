@@ -0,0 +1,92 @@
+package xml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// GetModules returns the entries of <modules> as written.
+func (p *PomRepository) GetModules() ([]string, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	modules := []string{}
+	if elem := p.doc.Root().SelectElement("modules"); elem != nil {
+		for _, module := range elem.SelectElements("module") {
+			modules = append(modules, strings.TrimSpace(module.Text()))
+		}
+	}
+	return modules, nil
+}
+
+// AddModule adds a <module> to <modules>. A missing <modules> is created before
+// <properties>, <dependencies> or <build>, where the POM reference places it.
+func (p *PomRepository) AddModule(module string) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	root := p.doc.Root()
+	modules := root.SelectElement("modules")
+	if modules == nil {
+		modules = etree.NewElement("modules")
+		p.insertProjectChild(modules)
+	}
+
+	for _, elem := range modules.SelectElements("module") {
+		if strings.TrimSpace(elem.Text()) == module {
+			return nil
+		}
+	}
+
+	p.style.appendTextElement(modules, "module", module)
+	return nil
+}
+
+// GetPackaging returns the <packaging> of the project, or domain.DefaultPackaging if omitted.
+func (p *PomRepository) GetPackaging() (string, error) {
+	if p.doc == nil {
+		return "", fmt.Errorf("no pom.xml loaded")
+	}
+
+	if packaging := childText(p.doc.Root(), "packaging"); packaging != "" {
+		return packaging, nil
+	}
+	return domain.DefaultPackaging, nil
+}
+
+// SetPackaging sets the <packaging> of the project. A missing element is added
+// after <version>, or <artifactId> when the version is inherited.
+func (p *PomRepository) SetPackaging(packaging string) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	root := p.doc.Root()
+	if elem := root.SelectElement("packaging"); elem != nil {
+		elem.SetText(packaging)
+		return nil
+	}
+
+	if sibling := firstChild(root, []string{"version", "artifactId"}); sibling != nil {
+		p.style.insertTextElementAfter(sibling, "packaging", packaging)
+	} else {
+		p.style.appendTextElement(root, "packaging", packaging)
+	}
+	return nil
+}
+
+// firstChild returns the first child of elem named by one of tags, trying the tags in order.
+func firstChild(elem *etree.Element, tags []string) *etree.Element {
+	for _, tag := range tags {
+		if child := elem.SelectElement(tag); child != nil {
+			return child
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package xml
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// projectElementOrder is the order of the <project> children in the Maven POM reference.
+var projectElementOrder = []string{
+	"modelVersion", "parent", "groupId", "artifactId", "version", "packaging",
+	"name", "description", "url", "inceptionYear", "organization", "licenses",
+	"developers", "contributors", "mailingLists", "prerequisites", "modules",
+	"scm", "issueManagement", "ciManagement", "distributionManagement", "properties",
+	"dependencyManagement", "dependencies", "repositories", "pluginRepositories",
+	"build", "reporting", "profiles",
+}
+
+// insertProjectChild adds a new child to <project> in the position given by
+// projectElementOrder: before the first element that follows it there, or at the end.
+func (p *PomRepository) insertProjectChild(child *etree.Element) {
+	root := p.doc.Root()
+
+	successors := projectElementOrder
+	for i, tag := range projectElementOrder {
+		if tag == child.Tag {
+			successors = projectElementOrder[i+1:]
+			break
+		}
+	}
+
+	for _, elem := range root.ChildElements() {
+		for _, tag := range successors {
+			if elem.Tag == tag {
+				p.style.insertChildBefore(elem, child)
+				return
+			}
+		}
+	}
+
+	p.style.appendChild(root, child)
+}
+
+// Format reorders the <project> children as in projectElementOrder, sorts the
+// dependencies of the project, its <dependencyManagement> and its profiles by
+// keys (none leaves them in place) and normalizes the indentation.
+// Comments move with the element they precede, or follow on the same line.
+func (p *PomRepository) Format(keys []domain.DependencySortKey) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	root := p.doc.Root()
+	reorderChildren(root, projectElementRanks(root))
+
+	if len(keys) > 0 {
+		for _, dependencies := range p.dependencyLists() {
+			sortDependencies(dependencies, keys)
+		}
+	}
+
+	p.style.reindent(root, p.style.indentOf(root))
+	return nil
+}
+
+// dependencyLists returns the <dependencies> elements Format sorts.
+func (p *PomRepository) dependencyLists() []*etree.Element {
+	root := p.doc.Root()
+	parents := []*etree.Element{root}
+	if management := root.SelectElement("dependencyManagement"); management != nil {
+		parents = append(parents, management)
+	}
+	if profiles := root.SelectElement("profiles"); profiles != nil {
+		parents = append(parents, profiles.SelectElements("profile")...)
+	}
+
+	var lists []*etree.Element
+	for _, parent := range parents {
+		if dependencies := parent.SelectElement("dependencies"); dependencies != nil {
+			lists = append(lists, dependencies)
+		}
+	}
+	return lists
+}
+
+// projectElementRanks returns the position of each <project> child in
+// projectElementOrder. Elements missing from it keep their place after the
+// element before them.
+func projectElementRanks(root *etree.Element) map[*etree.Element]int {
+	ranks := make(map[*etree.Element]int)
+	rank := -1
+	for _, elem := range root.ChildElements() {
+		if i := slices.Index(projectElementOrder, elem.Tag); i >= 0 {
+			rank = i
+		}
+		ranks[elem] = rank
+	}
+	return ranks
+}
+
+// sortDependencies sorts the <dependency> entries of a <dependencies> element.
+func sortDependencies(dependencies *etree.Element, keys []domain.DependencySortKey) {
+	ranks := make(map[*etree.Element]int)
+	sorted := dependencies.ChildElements()
+	slices.SortStableFunc(sorted, func(a, b *etree.Element) int {
+		return domain.CompareDependencies(declaredDependency(a), declaredDependency(b), keys)
+	})
+	for i, elem := range sorted {
+		ranks[elem] = i
+	}
+	reorderChildren(dependencies, ranks)
+}
+
+// node is an element together with the tokens that move with it: the whitespace
+// and comments before it, and a comment on the same line after it.
+type node struct {
+	elem   *etree.Element
+	tokens []etree.Token
+}
+
+// reorderChildren stably sorts the child elements of parent by rank.
+// Elements with text content around them are left as they are.
+func reorderChildren(parent *etree.Element, ranks map[*etree.Element]int) {
+	nodes, tail, ok := splitNodes(parent)
+	if !ok {
+		return
+	}
+
+	slices.SortStableFunc(nodes, func(a, b *node) int {
+		return ranks[a.elem] - ranks[b.elem]
+	})
+
+	for len(parent.Child) > 0 {
+		parent.RemoveChildAt(0)
+	}
+	for _, n := range nodes {
+		for _, tok := range n.tokens {
+			parent.AddChild(tok)
+		}
+	}
+	for _, tok := range tail {
+		parent.AddChild(tok)
+	}
+}
+
+// splitNodes groups the children of parent into nodes. The tokens after the
+// last node, such as the whitespace before the end tag, are returned as tail.
+// ok is false when parent holds text besides whitespace.
+func splitNodes(parent *etree.Element) (nodes []*node, tail []etree.Token, ok bool) {
+	var pending []etree.Token
+	for i := 0; i < len(parent.Child); i++ {
+		switch tok := parent.Child[i].(type) {
+		case *etree.Element:
+			n := &node{elem: tok, tokens: append(pending, tok)}
+			pending = nil
+
+			// A comment on the same line describes the element
+			j := i + 1
+			if j < len(parent.Child) {
+				if cd, isText := parent.Child[j].(*etree.CharData); isText && isWhitespace(cd) && !strings.Contains(cd.Data, "\n") {
+					j++
+				}
+			}
+			if j < len(parent.Child) {
+				if _, isComment := parent.Child[j].(*etree.Comment); isComment {
+					n.tokens = append(n.tokens, parent.Child[i+1:j+1]...)
+					i = j
+				}
+			}
+			nodes = append(nodes, n)
+		case *etree.CharData:
+			if !isWhitespace(tok) {
+				return nil, nil, false
+			}
+			pending = append(pending, tok)
+		default:
+			pending = append(pending, tok)
+		}
+	}
+	return nodes, pending, true
+}
@@ -0,0 +1,143 @@
+package xml
+
+import (
+	"fmt"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// GetPlugins returns the plugins of <build><plugins> followed by those of
+// <build><pluginManagement><plugins>. A missing groupId is reported as
+// domain.DefaultPluginGroupID, as Maven does.
+func (p *PomRepository) GetPlugins() ([]*domain.Plugin, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	plugins := p.readPlugins(p.pluginsElement(false, false), false)
+	plugins = append(plugins, p.readPlugins(p.pluginsElement(true, false), true)...)
+
+	return plugins, nil
+}
+
+// AddPlugin adds a plugin or updates its version, in <build><plugins> or, for
+// managed plugins, in <build><pluginManagement><plugins>.
+// Children of an existing plugin, such as <configuration> and <executions>, are kept.
+func (p *PomRepository) AddPlugin(plugin *domain.Plugin) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	plugins := p.pluginsElement(plugin.Managed, true)
+
+	elem := findPlugin(plugins, plugin)
+	if elem == nil {
+		p.createPluginElement(plugins, plugin)
+		return nil
+	}
+
+	if versionElem := elem.SelectElement("version"); versionElem != nil {
+		versionElem.SetText(plugin.Version)
+	} else if artifactElem := elem.SelectElement("artifactId"); artifactElem != nil {
+		// Keep <version> next to the coordinates rather than after <configuration>
+		p.style.insertTextElementAfter(artifactElem, "version", plugin.Version)
+	}
+
+	return nil
+}
+
+// RemovePlugin removes a plugin from <build><plugins> or, for managed plugins,
+// from <build><pluginManagement><plugins>.
+func (p *PomRepository) RemovePlugin(plugin *domain.Plugin) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	var elem *etree.Element
+	if plugins := p.pluginsElement(plugin.Managed, false); plugins != nil {
+		elem = findPlugin(plugins, plugin)
+	}
+	if elem == nil {
+		return &domain.NotFoundError{Kind: "plugin", Name: plugin.Coordinates()}
+	}
+
+	removeChild(elem)
+	return nil
+}
+
+// pluginsElement returns <build><plugins>, or <build><pluginManagement><plugins> if managed.
+// Missing elements are created if create is set; otherwise nil is returned.
+func (p *PomRepository) pluginsElement(managed, create bool) *etree.Element {
+	path := []string{"build", "plugins"}
+	if managed {
+		path = []string{"build", "pluginManagement", "plugins"}
+	}
+
+	elem := p.doc.Root()
+	for _, tag := range path {
+		child := elem.SelectElement(tag)
+		if child == nil {
+			if !create {
+				return nil
+			}
+			child = etree.NewElement(tag)
+			p.style.appendChild(elem, child)
+		}
+		elem = child
+	}
+
+	return elem
+}
+
+// readPlugins returns the <plugin> entries of a <plugins> element as declared.
+func (p *PomRepository) readPlugins(plugins *etree.Element, managed bool) []*domain.Plugin {
+	result := []*domain.Plugin{}
+	if plugins == nil {
+		return result
+	}
+
+	for _, elem := range plugins.SelectElements("plugin") {
+		plugin := readPlugin(elem, managed)
+		plugin.Position = p.position(elem)
+		result = append(result, plugin)
+	}
+	return result
+}
+
+// readPlugin returns the plugin declared by a <plugin> element.
+func readPlugin(elem *etree.Element, managed bool) *domain.Plugin {
+	groupID := childText(elem, "groupId")
+	if groupID == "" {
+		groupID = domain.DefaultPluginGroupID
+	}
+
+	return &domain.Plugin{
+		GroupID:    groupID,
+		ArtifactID: childText(elem, "artifactId"),
+		Version:    childText(elem, "version"),
+		Managed:    managed,
+	}
+}
+
+// findPlugin finds a plugin element by groupId and artifactId.
+func findPlugin(plugins *etree.Element, plugin *domain.Plugin) *etree.Element {
+	for _, elem := range plugins.SelectElements("plugin") {
+		declared := readPlugin(elem, plugin.Managed)
+		if declared.GroupID == plugin.GroupID && declared.ArtifactID == plugin.ArtifactID {
+			return elem
+		}
+	}
+	return nil
+}
+
+// createPluginElement creates a new plugin element.
+func (p *PomRepository) createPluginElement(plugins *etree.Element, plugin *domain.Plugin) {
+	elem := etree.NewElement("plugin")
+	p.style.appendChild(plugins, elem)
+
+	p.style.appendTextElement(elem, "groupId", plugin.GroupID)
+	p.style.appendTextElement(elem, "artifactId", plugin.ArtifactID)
+	p.style.appendTextElement(elem, "version", plugin.Version)
+}
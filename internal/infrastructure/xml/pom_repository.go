@@ -1,17 +1,29 @@
 package xml
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/beevik/etree"
 
 	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+	"github.com/elitonkfogaca/mvnx-cli/internal/infrastructure/fs"
 )
 
 // PomRepository implements the domain.PomRepository interface using etree for XML manipulation.
+// Only the nodes touched by an edit are re-serialized; everything else is written
+// back exactly as it was read.
 type PomRepository struct {
 	doc      *etree.Document
 	filePath string
+	source   *source
+	style    style
+
+	// loaded is the file content as of the last Load or Save, used to detect
+	// edits made by other programs in the meantime
+	loaded []byte
 }
 
 // NewPomRepository creates a new PomRepository instance.
@@ -21,14 +33,28 @@ func NewPomRepository() *PomRepository {
 
 // Load reads and parses the pom.xml file.
 func (p *PomRepository) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &domain.PomParseError{Path: path, Err: err}
+	}
+
+	return p.load(path, data)
+}
+
+// load parses data as the contents of the pom.xml at path.
+func (p *PomRepository) load(path string, data []byte) error {
 	doc := etree.NewDocument()
+	doc.ReadSettings.PreserveCData = true
 
-	if err := doc.ReadFromFile(path); err != nil {
-		return fmt.Errorf("failed to read pom.xml: %w", err)
+	if err := doc.ReadFromBytes(data); err != nil {
+		return &domain.PomParseError{Path: path, Err: err}
 	}
 
 	p.doc = doc
 	p.filePath = path
+	p.source = newSource(data, doc)
+	p.style = detectStyle(data, doc)
+	p.loaded = data
 
 	return nil
 }
@@ -44,14 +70,20 @@ func (p *PomRepository) AddDependency(dep *domain.Dependency) error {
 		return fmt.Errorf("invalid pom.xml: no root element")
 	}
 
+	parent, err := p.dependenciesParent(dep, true)
+	if err != nil {
+		return err
+	}
+
 	// Find or create <dependencies> element
-	dependencies := root.SelectElement("dependencies")
+	dependencies := parent.SelectElement("dependencies")
 	if dependencies == nil {
-		dependencies = root.CreateElement("dependencies")
+		dependencies = etree.NewElement("dependencies")
+		p.style.appendChild(parent, dependencies)
 	}
 
 	// Check if dependency already exists
-	existingDep := p.findDependency(dependencies, dep.GroupID, dep.ArtifactID)
+	existingDep := p.findDependency(dependencies, dep)
 
 	if existingDep != nil {
 		// Update existing dependency
@@ -64,43 +96,117 @@ func (p *PomRepository) AddDependency(dep *domain.Dependency) error {
 	return nil
 }
 
-// RemoveDependency removes a dependency by artifactId.
-func (p *PomRepository) RemoveDependency(artifactID string) error {
-	if p.doc == nil {
-		return fmt.Errorf("no pom.xml loaded")
+// RemoveDependency removes the dependency with the same groupId, artifactId, type and classifier as dep.
+func (p *PomRepository) RemoveDependency(dep *domain.Dependency) error {
+	elem, err := p.dependencyElement(dep)
+	if err != nil {
+		return err
 	}
 
-	root := p.doc.Root()
-	dependencies := root.SelectElement("dependencies")
-	if dependencies == nil {
-		return fmt.Errorf("dependency not found: %s", artifactID)
+	removeChild(elem)
+	return nil
+}
+
+// MergeDependencies rewrites the first declaration of the artifact of merged with its
+// version, scope, optional flag, systemPath and exclusions, and removes the later ones.
+func (p *PomRepository) MergeDependencies(merged *domain.Dependency) (int, error) {
+	first, err := p.dependencyElement(merged)
+	if err != nil {
+		return 0, err
 	}
 
-	// Find all dependency elements
-	for _, dep := range dependencies.SelectElements("dependency") {
-		artifactElem := dep.SelectElement("artifactId")
-		if artifactElem != nil && artifactElem.Text() == artifactID {
-			dependencies.RemoveChild(dep)
-			return nil
+	p.updateDependencyElement(first, merged)
+	version := first.SelectElement("version")
+	switch {
+	case merged.Version != "" && version == nil:
+		if artifactID := first.SelectElement("artifactId"); artifactID != nil {
+			p.style.insertTextElementAfter(artifactID, "version", merged.Version)
+		} else {
+			p.style.appendTextElement(first, "version", merged.Version)
 		}
+	case merged.Version == "" && version != nil:
+		removeChild(version)
 	}
 
-	return fmt.Errorf("dependency not found: %s", artifactID)
+	for _, exclusion := range merged.Exclusions {
+		if err := p.AddExclusion(merged, exclusion); err != nil {
+			return 0, err
+		}
+	}
+
+	removed := 0
+	for _, elem := range first.Parent().SelectElements("dependency") {
+		if elem != first && declaredDependency(elem).SameArtifact(merged) {
+			removeChild(elem)
+			removed++
+		}
+	}
+	return removed, nil
 }
 
-// HasDependency checks if a dependency exists by groupId and artifactId.
-func (p *PomRepository) HasDependency(groupID, artifactID string) bool {
+// AddExclusion adds an exclusion to the dependency with the same groupId, artifactId, type and classifier as dep.
+// The <exclusions> element is created if needed; an existing identical exclusion is kept as is.
+func (p *PomRepository) AddExclusion(target *domain.Dependency, exclusion domain.Exclusion) error {
+	dep, err := p.dependencyElement(target)
+	if err != nil {
+		return err
+	}
+
+	exclusions := dep.SelectElement("exclusions")
+	if exclusions == nil {
+		exclusions = etree.NewElement("exclusions")
+		p.style.appendChild(dep, exclusions)
+	} else if findExclusion(exclusions, exclusion) != nil {
+		return nil
+	}
+
+	p.createExclusionElement(exclusions, exclusion)
+	return nil
+}
+
+// RemoveExclusion removes an exclusion from the dependency with the same groupId, artifactId, type and classifier as dep.
+// The <exclusions> element is removed once it is empty.
+func (p *PomRepository) RemoveExclusion(target *domain.Dependency, exclusion domain.Exclusion) error {
+	dep, err := p.dependencyElement(target)
+	if err != nil {
+		return err
+	}
+
+	exclusions := dep.SelectElement("exclusions")
+	if exclusions == nil {
+		return &domain.NotFoundError{Kind: "exclusion", Name: exclusion.String()}
+	}
+
+	elem := findExclusion(exclusions, exclusion)
+	if elem == nil {
+		return &domain.NotFoundError{Kind: "exclusion", Name: exclusion.String()}
+	}
+
+	removeChild(elem)
+	if len(exclusions.ChildElements()) == 0 {
+		removeChild(exclusions)
+	}
+
+	return nil
+}
+
+// HasDependency checks if a dependency with the same groupId, artifactId, type and classifier as dep exists.
+func (p *PomRepository) HasDependency(dep *domain.Dependency) bool {
 	if p.doc == nil {
 		return false
 	}
 
-	root := p.doc.Root()
-	dependencies := root.SelectElement("dependencies")
+	parent, err := p.dependenciesParent(dep, false)
+	if err != nil || parent == nil {
+		return false
+	}
+
+	dependencies := parent.SelectElement("dependencies")
 	if dependencies == nil {
 		return false
 	}
 
-	return p.findDependency(dependencies, groupID, artifactID) != nil
+	return p.findDependency(dependencies, dep) != nil
 }
 
 // GetDependencies returns all dependencies in the pom.xml.
@@ -109,75 +215,305 @@ func (p *PomRepository) GetDependencies() ([]*domain.Dependency, error) {
 		return nil, fmt.Errorf("no pom.xml loaded")
 	}
 
-	root := p.doc.Root()
-	dependencies := root.SelectElement("dependencies")
-	if dependencies == nil {
+	return p.readDependencies(p.doc.Root().SelectElement("dependencies")), nil
+}
+
+// GetManagedDependencies returns the entries of <dependencyManagement>,
+// including imported BOMs.
+func (p *PomRepository) GetManagedDependencies() ([]*domain.Dependency, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	management := p.doc.Root().SelectElement("dependencyManagement")
+	if management == nil {
 		return []*domain.Dependency{}, nil
 	}
 
-	var result []*domain.Dependency
+	dependencies := p.readDependencies(management.SelectElement("dependencies"))
+	for _, dep := range dependencies {
+		dep.Managed = true
+	}
+	return dependencies, nil
+}
 
-	for _, dep := range dependencies.SelectElements("dependency") {
-		groupElem := dep.SelectElement("groupId")
-		artifactElem := dep.SelectElement("artifactId")
-		versionElem := dep.SelectElement("version")
-		scopeElem := dep.SelectElement("scope")
+// GetParent returns the <parent> of the pom.xml, or nil if it has none.
+func (p *PomRepository) GetParent() (*domain.Parent, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	parent := p.doc.Root().SelectElement("parent")
+	if parent == nil {
+		return nil, nil
+	}
+
+	// Maven looks for the parent in ../pom.xml unless <relativePath> says otherwise
+	relativePath := domain.DefaultParentRelativePath
+	if elem := parent.SelectElement("relativePath"); elem != nil {
+		relativePath = strings.TrimSpace(elem.Text())
+	}
 
-		if groupElem == nil || artifactElem == nil || versionElem == nil {
+	return &domain.Parent{
+		GroupID:      childText(parent, "groupId"),
+		ArtifactID:   childText(parent, "artifactId"),
+		Version:      childText(parent, "version"),
+		RelativePath: relativePath,
+	}, nil
+}
+
+// GetLicenses returns the <licenses> of the pom.xml, with their SPDX identifiers
+// as far as domain.NormalizeLicense recognizes them.
+func (p *PomRepository) GetLicenses() ([]*domain.License, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	licenses := []*domain.License{}
+	elem := p.doc.Root().SelectElement("licenses")
+	if elem == nil {
+		return licenses, nil
+	}
+	for _, license := range elem.SelectElements("license") {
+		name, url := childText(license, "name"), childText(license, "url")
+		if name == "" && url == "" {
 			continue
 		}
+		licenses = append(licenses, &domain.License{Name: name, URL: url, SPDX: domain.NormalizeLicense(name, url)})
+	}
+	return licenses, nil
+}
+
+// GetProperties returns the <properties> of the pom.xml together with the
+// project.* properties Maven derives from the project coordinates.
+func (p *PomRepository) GetProperties() (map[string]string, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	root := p.doc.Root()
+	properties := make(map[string]string)
+
+	// groupId and version are inherited from the parent when omitted
+	parent := root.SelectElement("parent")
+	for _, name := range []string{"groupId", "artifactId", "version"} {
+		value := childText(root, name)
+		if parent != nil {
+			if parentValue := childText(parent, name); parentValue != "" {
+				properties["project.parent."+name] = parentValue
+				if value == "" && name != "artifactId" {
+					value = parentValue
+				}
+			}
+		}
+		if value != "" {
+			properties["project."+name] = value
+		}
+	}
 
-		scope := "compile"
-		if scopeElem != nil && scopeElem.Text() != "" {
-			scope = scopeElem.Text()
+	if elem := root.SelectElement("properties"); elem != nil {
+		for _, property := range elem.ChildElements() {
+			properties[property.Tag] = strings.TrimSpace(property.Text())
 		}
+	}
 
-		dependency, err := domain.NewDependency(
-			groupElem.Text(),
-			artifactElem.Text(),
-			versionElem.Text(),
-			scope,
-		)
-		if err != nil {
-			continue
+	return properties, nil
+}
+
+// readDependencies returns the <dependency> entries of a <dependencies> element as declared.
+// Versions are returned verbatim, e.g. "${jackson.version}", and are empty when omitted.
+// Every entry is returned, including invalid ones such as a system dependency without
+// <systemPath>; callers check them with domain.Dependency.Validate.
+func (p *PomRepository) readDependencies(dependencies *etree.Element) []*domain.Dependency {
+	result := []*domain.Dependency{}
+	if dependencies == nil {
+		return result
+	}
+
+	for _, dep := range dependencies.SelectElements("dependency") {
+		scope := childText(dep, "scope")
+		if scope == "" {
+			scope = "compile"
 		}
 
-		result = append(result, dependency)
+		result = append(result, &domain.Dependency{
+			GroupID:    childText(dep, "groupId"),
+			ArtifactID: childText(dep, "artifactId"),
+			Version:    childText(dep, "version"),
+			Scope:      scope,
+			Type:       childText(dep, "type"),
+			Classifier: childText(dep, "classifier"),
+			Optional:   childText(dep, "optional") == "true",
+			SystemPath: childText(dep, "systemPath"),
+			Exclusions: readExclusions(dep.SelectElement("exclusions")),
+			Position:   p.position(dep),
+		})
 	}
 
-	return result, nil
+	return result
+}
+
+// readExclusions returns the <exclusion> entries of an <exclusions> element.
+func readExclusions(exclusions *etree.Element) []domain.Exclusion {
+	if exclusions == nil {
+		return nil
+	}
+
+	var result []domain.Exclusion
+	for _, elem := range exclusions.SelectElements("exclusion") {
+		result = append(result, domain.Exclusion{
+			GroupID:    childText(elem, "groupId"),
+			ArtifactID: childText(elem, "artifactId"),
+		})
+	}
+	return result
+}
+
+// childText returns the trimmed text of the named child element, or "" if it does not exist.
+func childText(elem *etree.Element, tag string) string {
+	child := elem.SelectElement(tag)
+	if child == nil {
+		return ""
+	}
+	return strings.TrimSpace(child.Text())
 }
 
 // Save writes the pom.xml back to disk, preserving formatting.
+// Returns a *domain.ConflictError if the file was modified since it was loaded.
 func (p *PomRepository) Save() error {
-	if p.doc == nil {
-		return fmt.Errorf("no pom.xml loaded")
+	content, err := p.Render()
+	if err != nil {
+		return err
 	}
 
-	p.doc.Indent(2)
+	// Refuse to overwrite changes made on disk since Load
+	current, err := os.ReadFile(p.filePath)
+	if err != nil {
+		return &domain.PomParseError{Path: p.filePath, Err: err}
+	}
+	if !bytes.Equal(current, p.loaded) {
+		return &domain.ConflictError{
+			Path:   p.filePath,
+			Reason: "pom.xml changed on disk since it was loaded; rerun the command",
+		}
+	}
 
-	if err := p.doc.WriteToFile(p.filePath); err != nil {
+	if err := fs.WriteFileAtomic(p.filePath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write pom.xml: %w", err)
 	}
+	p.loaded = content
 
 	return nil
 }
 
-// findDependency finds a dependency element by groupId and artifactId.
-func (p *PomRepository) findDependency(dependencies *etree.Element, groupID, artifactID string) *etree.Element {
-	for _, dep := range dependencies.SelectElements("dependency") {
-		groupElem := dep.SelectElement("groupId")
-		artifactElem := dep.SelectElement("artifactId")
+// Render returns the pom.xml contents exactly as Save would write them.
+func (p *PomRepository) Render() ([]byte, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	if p.source != nil {
+		return p.source.render(p.doc), nil
+	}
+
+	content, err := p.doc.WriteToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pom.xml: %w", err)
+	}
+
+	return content, nil
+}
 
-		if groupElem != nil && artifactElem != nil &&
-			groupElem.Text() == groupID && artifactElem.Text() == artifactID {
-			return dep
+// findDependency finds the dependency element with the same groupId, artifactId,
+// type and classifier as dep. A missing <type> is the same as jar.
+func (p *PomRepository) findDependency(dependencies *etree.Element, dep *domain.Dependency) *etree.Element {
+	for _, elem := range dependencies.SelectElements("dependency") {
+		if declaredDependency(elem).SameArtifact(dep) {
+			return elem
+		}
+	}
+	return nil
+}
+
+// declaredDependency returns the identity and scope of a <dependency> element,
+// enough to find and sort it.
+func declaredDependency(elem *etree.Element) *domain.Dependency {
+	return &domain.Dependency{
+		GroupID:    childText(elem, "groupId"),
+		ArtifactID: childText(elem, "artifactId"),
+		Type:       childText(elem, "type"),
+		Classifier: childText(elem, "classifier"),
+		Scope:      childText(elem, "scope"),
+	}
+}
+
+// minSortedDependencies is the number of dependencies, in order, from which a
+// list counts as sorted.
+const minSortedDependencies = 3
+
+// sortedSuccessor returns the <dependency> a new dependency goes before when the
+// list is already sorted by domain.DefaultDependencySortKeys, the keys mvnx fmt
+// sorts by, or nil to append it. Two dependencies are in order by chance too often,
+// so at least minSortedDependencies are needed to tell that the list is kept sorted.
+func sortedSuccessor(dependencies *etree.Element, dep *domain.Dependency) *etree.Element {
+	elems := dependencies.SelectElements("dependency")
+	if len(elems) < minSortedDependencies {
+		return nil
+	}
+
+	declared := make([]*domain.Dependency, len(elems))
+	for i, elem := range elems {
+		declared[i] = declaredDependency(elem)
+		if i > 0 && domain.CompareDependencies(declared[i-1], declared[i], domain.DefaultDependencySortKeys) > 0 {
+			return nil
+		}
+	}
+
+	for i, d := range declared {
+		if domain.CompareDependencies(dep, d, domain.DefaultDependencySortKeys) < 0 {
+			return elems[i]
+		}
+	}
+	return nil
+}
+
+// dependencyElement returns the <dependency> element with the same groupId, artifactId,
+// type and classifier as dep.
+func (p *PomRepository) dependencyElement(dep *domain.Dependency) (*etree.Element, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	parent, err := p.dependenciesParent(dep, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var elem *etree.Element
+	if parent != nil {
+		if dependencies := parent.SelectElement("dependencies"); dependencies != nil {
+			elem = p.findDependency(dependencies, dep)
+		}
+	}
+	if elem == nil {
+		return nil, &domain.NotFoundError{Kind: "dependency", Name: dep.ID()}
+	}
+
+	return elem, nil
+}
+
+// findExclusion finds an exclusion element by groupId and artifactId.
+func findExclusion(exclusions *etree.Element, exclusion domain.Exclusion) *etree.Element {
+	for _, elem := range exclusions.SelectElements("exclusion") {
+		if childText(elem, "groupId") == exclusion.GroupID && childText(elem, "artifactId") == exclusion.ArtifactID {
+			return elem
 		}
 	}
 	return nil
 }
 
 // updateDependencyElement updates an existing dependency element.
+// Only the version, scope, systemPath and optional flag change; other children such as <exclusions> are kept.
 func (p *PomRepository) updateDependencyElement(elem *etree.Element, dep *domain.Dependency) {
 	versionElem := elem.SelectElement("version")
 	if versionElem != nil {
@@ -188,31 +524,83 @@ func (p *PomRepository) updateDependencyElement(elem *etree.Element, dep *domain
 	scopeElem := elem.SelectElement("scope")
 	if dep.Scope != "compile" {
 		if scopeElem == nil {
-			scopeElem = elem.CreateElement("scope")
+			scopeElem = p.style.appendTextElement(elem, "scope", dep.Scope)
 		}
 		scopeElem.SetText(dep.Scope)
 	} else if scopeElem != nil {
 		// Remove scope element if it's compile (default)
-		elem.RemoveChild(scopeElem)
+		removeChild(scopeElem)
+	}
+
+	systemPathElem := elem.SelectElement("systemPath")
+	if dep.SystemPath != "" {
+		if systemPathElem == nil {
+			systemPathElem = p.style.appendTextElement(elem, "systemPath", dep.SystemPath)
+		}
+		systemPathElem.SetText(dep.SystemPath)
+	} else if systemPathElem != nil {
+		removeChild(systemPathElem)
+	}
+
+	optionalElem := elem.SelectElement("optional")
+	if dep.Optional {
+		if optionalElem == nil {
+			optionalElem = p.style.appendTextElement(elem, "optional", "true")
+		}
+		optionalElem.SetText("true")
+	} else if optionalElem != nil {
+		removeChild(optionalElem)
 	}
 }
 
-// createDependencyElement creates a new dependency element.
+// createDependencyElement creates a new dependency element, in sorted position
+// when the existing dependencies are sorted.
 func (p *PomRepository) createDependencyElement(dependencies *etree.Element, dep *domain.Dependency) {
-	depElem := dependencies.CreateElement("dependency")
-
-	groupElem := depElem.CreateElement("groupId")
-	groupElem.SetText(dep.GroupID)
+	depElem := etree.NewElement("dependency")
+	if next := sortedSuccessor(dependencies, dep); next != nil {
+		p.style.insertChildBefore(next, depElem)
+	} else {
+		p.style.appendChild(dependencies, depElem)
+	}
 
-	artifactElem := depElem.CreateElement("artifactId")
-	artifactElem.SetText(dep.ArtifactID)
+	p.style.appendTextElement(depElem, "groupId", dep.GroupID)
+	p.style.appendTextElement(depElem, "artifactId", dep.ArtifactID)
+	p.style.appendTextElement(depElem, "version", dep.Version)
 
-	versionElem := depElem.CreateElement("version")
-	versionElem.SetText(dep.Version)
+	// Only add type and classifier when they differ from the defaults
+	if dep.Type != "" && dep.Type != domain.DefaultDependencyType {
+		p.style.appendTextElement(depElem, "type", dep.Type)
+	}
+	if dep.Classifier != "" {
+		p.style.appendTextElement(depElem, "classifier", dep.Classifier)
+	}
 
 	// Only add scope if not compile (default)
 	if dep.Scope != "compile" {
-		scopeElem := depElem.CreateElement("scope")
-		scopeElem.SetText(dep.Scope)
+		p.style.appendTextElement(depElem, "scope", dep.Scope)
+	}
+	if dep.SystemPath != "" {
+		p.style.appendTextElement(depElem, "systemPath", dep.SystemPath)
+	}
+
+	if dep.Optional {
+		p.style.appendTextElement(depElem, "optional", "true")
+	}
+
+	if len(dep.Exclusions) > 0 {
+		exclusions := etree.NewElement("exclusions")
+		p.style.appendChild(depElem, exclusions)
+		for _, exclusion := range dep.Exclusions {
+			p.createExclusionElement(exclusions, exclusion)
+		}
 	}
 }
+
+// createExclusionElement creates a new exclusion element.
+func (p *PomRepository) createExclusionElement(exclusions *etree.Element, exclusion domain.Exclusion) {
+	elem := etree.NewElement("exclusion")
+	p.style.appendChild(exclusions, elem)
+
+	p.style.appendTextElement(elem, "groupId", exclusion.GroupID)
+	p.style.appendTextElement(elem, "artifactId", exclusion.ArtifactID)
+}
@@ -0,0 +1,737 @@
+package xml
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// corpus lists the sample POMs under testdata
+var corpus = []string{
+	"empty-dependencies.xml",
+	"library.xml",
+	"mvnx-init.xml",
+	"no-dependencies.xml",
+	"spring-boot-app.xml",
+	"unformatted.xml",
+	"windows-crlf.xml",
+}
+
+func loadTestPom(t *testing.T, name string) (*PomRepository, []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	repo := NewPomRepository()
+	require.NoError(t, repo.Load(path))
+
+	return repo, data
+}
+
+func mustDependency(t *testing.T, groupID, artifactID, version, scope string) *domain.Dependency {
+	t.Helper()
+
+	dep, err := domain.NewDependency(groupID, artifactID, version, scope)
+	require.NoError(t, err)
+	return dep
+}
+
+// artifact returns a dependency identifying groupId:artifactId as a plain jar.
+func artifact(groupID, artifactID string) *domain.Dependency {
+	return &domain.Dependency{GroupID: groupID, ArtifactID: artifactID}
+}
+
+// assertGolden compares got with testdata/golden/<name>, rewriting it with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestPomRepository_RoundTrip(t *testing.T) {
+	for _, name := range corpus {
+		t.Run(name, func(t *testing.T) {
+			repo, data := loadTestPom(t, name)
+
+			got, err := repo.Render()
+			require.NoError(t, err)
+			assert.Equal(t, string(data), string(got))
+		})
+	}
+}
+
+func TestPomRepository_Golden(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		edit func(t *testing.T, repo *PomRepository)
+	}{
+		{
+			name: "add",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.slf4j", "slf4j-api", "2.0.13", "compile")))
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.mockito", "mockito-core", "5.11.0", "test")))
+			},
+		},
+		{
+			name: "update",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.apache.commons", "commons-lang3", "3.15.0", "provided")))
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.assertj", "assertj-core", "3.26.0", "compile")))
+			},
+		},
+		{
+			name: "classifier",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				epoll := mustDependency(t, "io.netty", "netty-transport-native-epoll", "4.1.110.Final", "runtime")
+				epoll.Classifier = "linux-x86_64"
+				epoll.Optional = true
+				require.NoError(t, repo.AddDependency(epoll))
+
+				// Same groupId:artifactId as an existing dependency, but a different type
+				testJar := mustDependency(t, "org.apache.commons", "commons-lang3", "3.14.0", "test")
+				testJar.Type = "test-jar"
+				require.NoError(t, repo.AddDependency(testJar))
+			},
+		},
+		{
+			name: "system",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				ojdbc := mustDependency(t, "com.oracle", "ojdbc", "11", "system")
+				ojdbc.SystemPath = "${project.basedir}/lib/ojdbc11.jar"
+				require.NoError(t, repo.AddDependency(ojdbc))
+
+				// Switching to system scope adds <systemPath> to the existing entry
+				lang := mustDependency(t, "org.apache.commons", "commons-lang3", "3.14.0", "system")
+				lang.SystemPath = "${project.basedir}/lib/commons-lang3.jar"
+				require.NoError(t, repo.AddDependency(lang))
+			},
+		},
+		{
+			name: "remove",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.RemoveDependency(artifact("org.assertj", "assertj-core")))
+			},
+		},
+		{
+			name: "add",
+			file: "spring-boot-app.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.postgresql", "postgresql", "42.7.3", "runtime")))
+			},
+		},
+		{
+			name: "plugins",
+			file: "spring-boot-app.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				// The existing plugin has no <version> and a <configuration> block
+				require.NoError(t, repo.AddPlugin(&domain.Plugin{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin", Version: "3.3.0"}))
+				require.NoError(t, repo.AddPlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-surefire-plugin", Version: "3.2.5"}))
+				require.NoError(t, repo.AddPlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-enforcer-plugin", Version: "3.5.0", Managed: true}))
+			},
+		},
+		{
+			name: "plugins",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddPlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-surefire-plugin", Version: "3.3.0"}))
+			},
+		},
+		{
+			name: "remove",
+			file: "spring-boot-app.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.RemoveDependency(artifact("org.projectlombok", "lombok")))
+			},
+		},
+		{
+			name: "add",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.slf4j", "slf4j-api", "2.0.13", "compile")))
+			},
+		},
+		{
+			name: "update",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "junit", "junit", "4.13.2", "compile")))
+			},
+		},
+		{
+			name: "add",
+			file: "no-dependencies.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.slf4j", "slf4j-api", "2.0.13", "compile")))
+			},
+		},
+		{
+			name: "add",
+			file: "empty-dependencies.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.slf4j", "slf4j-api", "2.0.13", "compile")))
+			},
+		},
+		{
+			name: "add",
+			file: "mvnx-init.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.projectlombok", "lombok", "1.18.32", "provided")))
+			},
+		},
+		{
+			name: "exclude",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddExclusion(artifact("org.junit.jupiter", "junit-jupiter"), domain.Exclusion{GroupID: "org.opentest4j", ArtifactID: "opentest4j"}))
+				require.NoError(t, repo.AddExclusion(artifact("org.junit.jupiter", "junit-jupiter"), domain.Exclusion{GroupID: "org.apiguardian", ArtifactID: "*"}))
+				require.NoError(t, repo.AddExclusion(artifact("org.assertj", "assertj-core"), domain.Exclusion{GroupID: "net.bytebuddy", ArtifactID: "byte-buddy"}))
+
+				// Bumping the version keeps the exclusions
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.assertj", "assertj-core", "3.26.0", "test")))
+			},
+		},
+		{
+			name: "exclude",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddExclusion(artifact("com.google.guava", "guava"), domain.Exclusion{GroupID: "com.google.code.findbugs", ArtifactID: "jsr305"}))
+			},
+		},
+		{
+			name: "modules",
+			file: "mvnx-init.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.SetPackaging("pom"))
+				require.NoError(t, repo.AddModule("core"))
+				require.NoError(t, repo.AddModule("services/orders"))
+				require.NoError(t, repo.AddModule("core"))
+			},
+		},
+		{
+			name: "modules",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				// No <packaging> yet: it goes after <version>
+				require.NoError(t, repo.SetPackaging("pom"))
+				require.NoError(t, repo.AddModule("core"))
+			},
+		},
+		{
+			name: "java",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				// No <properties> yet: it goes before <dependencies>
+				require.NoError(t, repo.SetProperty("maven.compiler.release", "21"))
+			},
+		},
+		{
+			name: "profiles",
+			file: "library.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddProfile(&domain.Profile{
+					ID: "it",
+					Activation: []domain.ProfileActivation{
+						{Kind: domain.ActivationProperty, Name: "env", Value: "it"},
+						{Kind: domain.ActivationJDK, Value: "[17,)"},
+					},
+				}))
+				require.NoError(t, repo.AddProfile(&domain.Profile{ID: "release"}))
+
+				testcontainers := mustDependency(t, "org.testcontainers", "postgresql", "1.19.8", "test")
+				testcontainers.Profile = "it"
+				require.NoError(t, repo.AddDependency(testcontainers))
+			},
+		},
+		{
+			name: "profiles",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddProfile(&domain.Profile{
+					ID:         "windows",
+					Activation: []domain.ProfileActivation{{Kind: domain.ActivationOS, Name: "windows"}},
+				}))
+
+				jna := mustDependency(t, "net.java.dev.jna", "jna-platform", "5.14.0", "compile")
+				jna.Profile = "windows"
+				require.NoError(t, repo.AddDependency(jna))
+			},
+		},
+		{
+			name: "managed",
+			file: "mvnx-init.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				// No <dependencyManagement> yet: it goes before <dependencies>
+				jackson := mustDependency(t, "com.fasterxml.jackson", "jackson-bom", "2.17.1", "import")
+				jackson.Type = "pom"
+				jackson.Managed = true
+				require.NoError(t, repo.AddDependency(jackson))
+
+				guava := mustDependency(t, "com.google.guava", "guava", "33.2.1-jre", "compile")
+				guava.Managed = true
+				require.NoError(t, repo.AddDependency(guava))
+				assert.True(t, repo.HasDependency(guava))
+				assert.False(t, repo.HasDependency(artifact("com.google.guava", "guava")))
+			},
+		},
+		{
+			name: "fmt",
+			file: "unformatted.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.Format(domain.DefaultDependencySortKeys))
+			},
+		},
+		{
+			name: "fmt-groupid",
+			file: "unformatted.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.Format([]domain.DependencySortKey{domain.SortByGroupID}))
+			},
+		},
+		{
+			name: "fmt-add",
+			file: "unformatted.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.Format(domain.DefaultDependencySortKeys))
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.flywaydb", "flyway-core", "10.15.0", "compile")))
+			},
+		},
+		{
+			name: "fmt",
+			file: "spring-boot-app.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.Format(domain.DefaultDependencySortKeys))
+			},
+		},
+		{
+			name: "fmt",
+			file: "windows-crlf.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.Format(domain.DefaultDependencySortKeys))
+			},
+		},
+		{
+			name: "add-remove",
+			file: "mvnx-init.xml",
+			edit: func(t *testing.T, repo *PomRepository) {
+				require.NoError(t, repo.AddDependency(mustDependency(t, "org.projectlombok", "lombok", "1.18.32", "provided")))
+				require.NoError(t, repo.RemoveDependency(artifact("org.projectlombok", "lombok")))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		golden := tt.file[:len(tt.file)-len(".xml")] + "." + tt.name + ".xml"
+
+		t.Run(golden, func(t *testing.T) {
+			repo, _ := loadTestPom(t, tt.file)
+			tt.edit(t, repo)
+
+			got, err := repo.Render()
+			require.NoError(t, err)
+
+			assertGolden(t, golden, got)
+			assert.NotContains(t, string(got), "\r\r", "line endings must not be doubled")
+
+			// The result must itself be a well-formed POM that round-trips
+			reloaded := NewPomRepository()
+			require.NoError(t, reloaded.load("pom.xml", got))
+			again, err := reloaded.Render()
+			require.NoError(t, err)
+			assert.Equal(t, string(got), string(again))
+		})
+	}
+}
+
+func TestPomRepository_FormatIsIdempotent(t *testing.T) {
+	for _, name := range corpus {
+		t.Run(name, func(t *testing.T) {
+			repo, _ := loadTestPom(t, name)
+			require.NoError(t, repo.Format(domain.DefaultDependencySortKeys))
+			formatted, err := repo.Render()
+			require.NoError(t, err)
+
+			reloaded := NewPomRepository()
+			require.NoError(t, reloaded.load("pom.xml", formatted))
+			require.NoError(t, reloaded.Format(domain.DefaultDependencySortKeys))
+			again, err := reloaded.Render()
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(again))
+		})
+	}
+}
+
+func TestPomRepository_SaveLeavesUntouchedFileIdentical(t *testing.T) {
+	for _, name := range corpus {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", name))
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "pom.xml")
+			require.NoError(t, os.WriteFile(path, data, 0644))
+
+			repo := NewPomRepository()
+			require.NoError(t, repo.Load(path))
+			require.NoError(t, repo.Save())
+
+			saved, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, data, saved)
+		})
+	}
+}
+
+func TestPomRepository_SaveRefusesConcurrentModification(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "library.xml"))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "pom.xml")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	repo := NewPomRepository()
+	require.NoError(t, repo.Load(path))
+	require.NoError(t, repo.AddDependency(mustDependency(t, "org.slf4j", "slf4j-api", "2.0.13", "compile")))
+	require.NoError(t, repo.Save())
+
+	// A second save after our own write is fine
+	require.NoError(t, repo.AddDependency(mustDependency(t, "org.mockito", "mockito-core", "5.11.0", "test")))
+	require.NoError(t, repo.Save())
+
+	edited := []byte("<!-- edited elsewhere -->\n")
+	require.NoError(t, os.WriteFile(path, edited, 0644))
+
+	require.NoError(t, repo.RemoveDependency(artifact("org.mockito", "mockito-core")))
+	err = repo.Save()
+	var conflictErr *domain.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, edited, saved)
+}
+
+func TestPomRepository_Exclusions(t *testing.T) {
+	repo, data := loadTestPom(t, "library.xml")
+
+	opentest4j := domain.Exclusion{GroupID: "org.opentest4j", ArtifactID: "opentest4j"}
+	apiguardian := domain.Exclusion{GroupID: "org.apiguardian", ArtifactID: "*"}
+
+	require.NoError(t, repo.AddExclusion(artifact("org.junit.jupiter", "junit-jupiter"), opentest4j))
+	require.NoError(t, repo.AddExclusion(artifact("org.junit.jupiter", "junit-jupiter"), apiguardian))
+	require.NoError(t, repo.AddExclusion(artifact("org.junit.jupiter", "junit-jupiter"), opentest4j))
+
+	dependencies, err := repo.GetDependencies()
+	require.NoError(t, err)
+	for _, dep := range dependencies {
+		if dep.ArtifactID == "junit-jupiter" {
+			assert.Equal(t, []domain.Exclusion{opentest4j, apiguardian}, dep.Exclusions)
+		}
+	}
+
+	var notFoundErr *domain.NotFoundError
+	assert.ErrorAs(t, repo.AddExclusion(artifact("org.example", "missing"), opentest4j), &notFoundErr)
+	assert.ErrorAs(t, repo.RemoveExclusion(artifact("org.assertj", "assertj-core"), opentest4j), &notFoundErr)
+
+	// Removing the last exclusion removes <exclusions> and restores the original file
+	require.NoError(t, repo.RemoveExclusion(artifact("org.junit.jupiter", "junit-jupiter"), opentest4j))
+	require.NoError(t, repo.RemoveExclusion(artifact("org.junit.jupiter", "junit-jupiter"), apiguardian))
+
+	got, err := repo.Render()
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(got))
+}
+
+func TestPomRepository_TypeAndClassifierIdentity(t *testing.T) {
+	repo, _ := loadTestPom(t, "library.xml")
+
+	testJar := mustDependency(t, "org.apache.commons", "commons-lang3", "3.14.0", "test")
+	testJar.Type = "test-jar"
+	testJar.Optional = true
+	require.NoError(t, repo.AddDependency(testJar))
+
+	deps, err := repo.GetDependencies()
+	require.NoError(t, err)
+	require.Len(t, deps, 4)
+	assert.Equal(t, "test-jar", deps[3].Type)
+	assert.True(t, deps[3].Optional)
+
+	// An explicit <type>jar</type> is the same as no type
+	jar := artifact("org.apache.commons", "commons-lang3")
+	jar.Type = "jar"
+	assert.True(t, repo.HasDependency(jar))
+
+	require.NoError(t, repo.RemoveDependency(testJar))
+	assert.True(t, repo.HasDependency(jar))
+	assert.False(t, repo.HasDependency(testJar))
+
+	var notFoundErr *domain.NotFoundError
+	assert.ErrorAs(t, repo.RemoveDependency(testJar), &notFoundErr)
+}
+
+func TestPomRepository_Plugins(t *testing.T) {
+	repo, _ := loadTestPom(t, "spring-boot-app.xml")
+
+	require.NoError(t, repo.AddPlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-enforcer-plugin", Version: "3.5.0", Managed: true}))
+
+	plugins, err := repo.GetPlugins()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Plugin{
+		{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin", Position: domain.Position{Line: 39, Column: 4}},
+		// Added since Load, so not in the file yet
+		{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-enforcer-plugin", Version: "3.5.0", Managed: true},
+	}, plugins)
+
+	// Plugins are only removed from the section they are declared in
+	var notFoundErr *domain.NotFoundError
+	assert.ErrorAs(t, repo.RemovePlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-enforcer-plugin"}), &notFoundErr)
+	require.NoError(t, repo.RemovePlugin(&domain.Plugin{GroupID: domain.DefaultPluginGroupID, ArtifactID: "maven-enforcer-plugin", Managed: true}))
+
+	plugins, err = repo.GetPlugins()
+	require.NoError(t, err)
+	assert.Len(t, plugins, 1)
+}
+
+func TestPomRepository_Modules(t *testing.T) {
+	repo, _ := loadTestPom(t, "windows-crlf.xml")
+
+	packaging, err := repo.GetPackaging()
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultPackaging, packaging)
+
+	modules, err := repo.GetModules()
+	require.NoError(t, err)
+	assert.Empty(t, modules)
+
+	require.NoError(t, repo.SetPackaging(domain.AggregatorPackaging))
+	require.NoError(t, repo.AddModule("core"))
+	require.NoError(t, repo.AddModule("core"))
+
+	packaging, err = repo.GetPackaging()
+	require.NoError(t, err)
+	assert.Equal(t, domain.AggregatorPackaging, packaging)
+
+	modules, err = repo.GetModules()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"core"}, modules)
+}
+
+func TestPomRepository_Profiles(t *testing.T) {
+	repo := NewPomRepository()
+	require.NoError(t, repo.load("pom.xml", []byte(`<project>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>2.0.13</version>
+    </dependency>
+  </dependencies>
+  <profiles>
+    <profile>
+      <id>ci</id>
+      <activation>
+        <property>
+          <name>!skipCi</name>
+        </property>
+        <file>
+          <missing>local.properties</missing>
+        </file>
+      </activation>
+      <dependencies>
+        <dependency>
+          <groupId>org.slf4j</groupId>
+          <artifactId>slf4j-api</artifactId>
+          <version>2.0.9</version>
+        </dependency>
+      </dependencies>
+    </profile>
+  </profiles>
+</project>
+`)))
+
+	profiles, err := repo.GetProfiles()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "ci", profiles[0].ID)
+	assert.Equal(t, []domain.ProfileActivation{
+		{Kind: domain.ActivationProperty, Name: "!skipCi"},
+		{Kind: domain.ActivationFile, Name: "!local.properties"},
+	}, profiles[0].Activation)
+
+	var conflict *domain.ConflictError
+	assert.ErrorAs(t, repo.AddProfile(&domain.Profile{ID: "ci"}), &conflict)
+
+	// The project and the profile declare the same artifact independently
+	dependencies, err := repo.GetProfileDependencies("ci")
+	require.NoError(t, err)
+	require.Len(t, dependencies, 1)
+	assert.Equal(t, "2.0.9", dependencies[0].Version)
+	assert.Equal(t, "ci", dependencies[0].Profile)
+
+	require.NoError(t, repo.RemoveDependency(dependencies[0]))
+	assert.False(t, repo.HasDependency(dependencies[0]))
+	assert.True(t, repo.HasDependency(artifact("org.slf4j", "slf4j-api")))
+
+	var notFound *domain.NotFoundError
+	_, err = repo.GetProfileDependencies("release")
+	assert.ErrorAs(t, err, &notFound)
+
+	missing := artifact("org.slf4j", "slf4j-api")
+	missing.Profile = "release"
+	assert.ErrorAs(t, repo.AddDependency(missing), &notFound)
+}
+
+func TestPomRepository_JavaDeclarations(t *testing.T) {
+	repo := NewPomRepository()
+	require.NoError(t, repo.load("pom.xml", []byte(`<project>
+  <properties>
+    <java.version>17</java.version>
+    <maven.compiler.release>${java.version}</maven.compiler.release>
+    <other>17</other>
+  </properties>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-toolchains-plugin</artifactId>
+        <configuration><toolchains><jdk><version>[17,)</version></jdk></toolchains></configuration>
+      </plugin>
+    </plugins>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-compiler-plugin</artifactId>
+          <configuration><source>1.8</source><target>1.8</target></configuration>
+          <executions>
+            <execution><configuration><testRelease>21</testRelease></configuration></execution>
+          </executions>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+</project>`)))
+
+	declarations, err := repo.GetJavaDeclarations()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.JavaDeclaration{
+		{Location: "properties/java.version", Kind: domain.JavaProperty, Value: "17"},
+		{Location: "properties/maven.compiler.release", Kind: domain.JavaProperty, Value: "${java.version}"},
+		{Location: "build/plugins/maven-toolchains-plugin/configuration/toolchains/jdk/version", Kind: domain.JavaToolchain, Value: "[17,)"},
+		{Location: "build/pluginManagement/plugins/maven-compiler-plugin/configuration/source", Kind: domain.JavaCompilerPlugin, Value: "1.8"},
+		{Location: "build/pluginManagement/plugins/maven-compiler-plugin/configuration/target", Kind: domain.JavaCompilerPlugin, Value: "1.8"},
+		{Location: "build/pluginManagement/plugins/maven-compiler-plugin/executions/default/configuration/testRelease", Kind: domain.JavaCompilerPlugin, Value: "21"},
+	}, declarations)
+
+	require.NoError(t, repo.SetJavaDeclaration("build/pluginManagement/plugins/maven-compiler-plugin/configuration/source", "11"))
+	var notFoundErr *domain.NotFoundError
+	assert.ErrorAs(t, repo.SetJavaDeclaration("properties/other", "11"), &notFoundErr)
+
+	declarations, err = repo.GetJavaDeclarations()
+	require.NoError(t, err)
+	assert.Equal(t, "11", declarations[3].Value)
+}
+
+func TestPomRepository_Properties(t *testing.T) {
+	repo, _ := loadTestPom(t, "no-dependencies.xml")
+
+	properties, err := repo.GetPropertyDeclarations()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Property{
+		{Name: "maven.compiler.source", Value: "21", Position: domain.Position{Line: 10, Column: 9}},
+		{Name: "maven.compiler.target", Value: "21", Position: domain.Position{Line: 11, Column: 9}},
+	}, properties)
+
+	var notFoundErr *domain.NotFoundError
+	assert.ErrorAs(t, repo.RemoveProperty("java.version"), &notFoundErr)
+
+	// Removing the last property removes the section
+	require.NoError(t, repo.RemoveProperty("maven.compiler.source"))
+	require.NoError(t, repo.RemoveProperty("maven.compiler.target"))
+
+	properties, err = repo.GetPropertyDeclarations()
+	require.NoError(t, err)
+	assert.Empty(t, properties)
+
+	rendered, err := repo.Render()
+	require.NoError(t, err)
+	assert.NotContains(t, string(rendered), "<properties>")
+}
+
+func TestPomRepository_Licenses(t *testing.T) {
+	repo := NewPomRepository()
+	require.NoError(t, repo.load("pom.xml", []byte(`<project>
+  <licenses>
+    <license>
+      <name>The Apache Software License, Version 2.0</name>
+      <url>http://www.apache.org/licenses/LICENSE-2.0.txt</url>
+    </license>
+    <license>
+      <url>https://www.eclipse.org/legal/epl-2.0/</url>
+    </license>
+    <license>
+      <name>Proprietary</name>
+    </license>
+    <license/>
+  </licenses>
+</project>`)))
+
+	licenses, err := repo.GetLicenses()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.License{
+		{Name: "The Apache Software License, Version 2.0", URL: "http://www.apache.org/licenses/LICENSE-2.0.txt", SPDX: "Apache-2.0"},
+		{URL: "https://www.eclipse.org/legal/epl-2.0/", SPDX: "EPL-2.0"},
+		{Name: "Proprietary"},
+	}, licenses)
+
+	repo, _ = loadTestPom(t, "no-dependencies.xml")
+	licenses, err = repo.GetLicenses()
+	require.NoError(t, err)
+	assert.Empty(t, licenses)
+}
+
+func FuzzPomRepository_RoundTrip(f *testing.F) {
+	for _, name := range corpus {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte("<project><a x='1'  y=\"2\"/><!-- c --><b>&#169;&lt;</b></project>"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		repo := NewPomRepository()
+		if err := repo.load("pom.xml", data); err != nil {
+			return
+		}
+
+		got, err := repo.Render()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(data) {
+			t.Fatalf("round trip changed the document:\nin:  %q\nout: %q", data, got)
+		}
+	})
+}
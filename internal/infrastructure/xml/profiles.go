@@ -0,0 +1,199 @@
+package xml
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// activationOrder is the order of the <activation> children in the Maven POM reference.
+var activationOrder = []string{
+	domain.ActivationDefault, domain.ActivationJDK, domain.ActivationOS,
+	domain.ActivationProperty, domain.ActivationFile,
+}
+
+// GetProfiles returns the <profiles> of the pom.xml with their activation.
+func (p *PomRepository) GetProfiles() ([]*domain.Profile, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	profiles := []*domain.Profile{}
+	if elem := p.doc.Root().SelectElement("profiles"); elem != nil {
+		for _, profile := range elem.SelectElements("profile") {
+			profiles = append(profiles, &domain.Profile{
+				ID:         childText(profile, "id"),
+				Activation: readActivation(profile.SelectElement("activation")),
+			})
+		}
+	}
+	return profiles, nil
+}
+
+// AddProfile adds a profile to <profiles>. A missing <profiles> is created at the
+// end of the project, where the POM reference places it.
+func (p *PomRepository) AddProfile(profile *domain.Profile) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	if p.profileElement(profile.ID) != nil {
+		return &domain.ConflictError{Path: p.filePath, Reason: fmt.Sprintf("profile %s already exists", profile.ID)}
+	}
+
+	root := p.doc.Root()
+	profiles := root.SelectElement("profiles")
+	if profiles == nil {
+		profiles = etree.NewElement("profiles")
+		p.insertProjectChild(profiles)
+	}
+
+	elem := etree.NewElement("profile")
+	p.style.appendChild(profiles, elem)
+	p.style.appendTextElement(elem, "id", profile.ID)
+
+	if len(profile.Activation) == 0 {
+		return nil
+	}
+
+	activation := etree.NewElement("activation")
+	p.style.appendChild(elem, activation)
+	for _, kind := range activationOrder {
+		for _, condition := range profile.Activation {
+			if condition.Kind == kind {
+				p.createActivationElement(activation, condition)
+			}
+		}
+	}
+	return nil
+}
+
+// GetProfileDependencies returns the <dependencies> of the profile with the given id.
+func (p *PomRepository) GetProfileDependencies(id string) ([]*domain.Dependency, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	profile := p.profileElement(id)
+	if profile == nil {
+		return nil, &domain.NotFoundError{Kind: "profile", Name: id}
+	}
+
+	dependencies := p.readDependencies(profile.SelectElement("dependencies"))
+	for _, dep := range dependencies {
+		dep.Profile = id
+	}
+	return dependencies, nil
+}
+
+// profileElement returns the <profile> with the given id, or nil if there is none.
+func (p *PomRepository) profileElement(id string) *etree.Element {
+	profiles := p.doc.Root().SelectElement("profiles")
+	if profiles == nil {
+		return nil
+	}
+
+	for _, profile := range profiles.SelectElements("profile") {
+		if childText(profile, "id") == id {
+			return profile
+		}
+	}
+	return nil
+}
+
+// dependenciesParent returns the element holding the <dependencies> of the section
+// selected by dep: the <profile> with dep.Profile as id, or <project> when it is
+// empty, and within it <dependencyManagement> when dep.Managed is set.
+// A missing <dependencyManagement> is created if create is set; otherwise nil is returned.
+func (p *PomRepository) dependenciesParent(dep *domain.Dependency, create bool) (*etree.Element, error) {
+	parent := p.doc.Root()
+	if dep.Profile != "" {
+		parent = p.profileElement(dep.Profile)
+		if parent == nil {
+			return nil, &domain.NotFoundError{Kind: "profile", Name: dep.Profile}
+		}
+	}
+	if !dep.Managed {
+		return parent, nil
+	}
+
+	management := parent.SelectElement("dependencyManagement")
+	if management == nil {
+		if !create {
+			return nil, nil
+		}
+		management = etree.NewElement("dependencyManagement")
+		if dep.Profile == "" {
+			p.insertProjectChild(management)
+		} else {
+			p.style.appendChild(parent, management)
+		}
+	}
+	return management, nil
+}
+
+// readActivation returns the conditions of an <activation> element.
+// Conditions mvnx cannot express, such as an OS version or arch, are skipped.
+func readActivation(activation *etree.Element) []domain.ProfileActivation {
+	if activation == nil {
+		return nil
+	}
+
+	var result []domain.ProfileActivation
+	if childText(activation, "activeByDefault") == "true" {
+		result = append(result, domain.ProfileActivation{Kind: domain.ActivationDefault, Value: "true"})
+	}
+	if jdk := childText(activation, "jdk"); jdk != "" {
+		result = append(result, domain.ProfileActivation{Kind: domain.ActivationJDK, Value: jdk})
+	}
+	if os := activation.SelectElement("os"); os != nil && childText(os, "family") != "" {
+		result = append(result, domain.ProfileActivation{Kind: domain.ActivationOS, Name: childText(os, "family")})
+	}
+	if property := activation.SelectElement("property"); property != nil {
+		result = append(result, domain.ProfileActivation{
+			Kind:  domain.ActivationProperty,
+			Name:  childText(property, "name"),
+			Value: childText(property, "value"),
+		})
+	}
+	if file := activation.SelectElement("file"); file != nil {
+		if exists := childText(file, "exists"); exists != "" {
+			result = append(result, domain.ProfileActivation{Kind: domain.ActivationFile, Name: exists})
+		} else if missing := childText(file, "missing"); missing != "" {
+			result = append(result, domain.ProfileActivation{Kind: domain.ActivationFile, Name: "!" + missing})
+		}
+	}
+	return result
+}
+
+// createActivationElement adds the element of an activation condition to <activation>.
+func (p *PomRepository) createActivationElement(activation *etree.Element, condition domain.ProfileActivation) {
+	switch condition.Kind {
+	case domain.ActivationDefault:
+		p.style.appendTextElement(activation, "activeByDefault", "true")
+	case domain.ActivationJDK:
+		p.style.appendTextElement(activation, "jdk", condition.Value)
+	case domain.ActivationOS:
+		os := etree.NewElement("os")
+		p.style.appendChild(activation, os)
+		p.style.appendTextElement(os, "family", condition.Name)
+	case domain.ActivationProperty:
+		property := etree.NewElement("property")
+		p.style.appendChild(activation, property)
+		p.style.appendTextElement(property, "name", condition.Name)
+		if condition.Value != "" {
+			p.style.appendTextElement(property, "value", condition.Value)
+		}
+	case domain.ActivationFile:
+		file := etree.NewElement("file")
+		p.style.appendChild(activation, file)
+		if missing, ok := strings.CutPrefix(condition.Name, "!"); ok {
+			p.style.appendTextElement(file, "missing", missing)
+		} else {
+			p.style.appendTextElement(file, "exists", condition.Name)
+		}
+	}
+}
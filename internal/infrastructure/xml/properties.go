@@ -0,0 +1,94 @@
+package xml
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/beevik/etree"
+
+	"github.com/elitonkfogaca/mvnx-cli/internal/domain"
+)
+
+// SetProperty sets a property, adding it at the end of <properties>. A missing
+// <properties> is created where the POM reference places it.
+func (p *PomRepository) SetProperty(name, value string) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	properties := p.doc.Root().SelectElement("properties")
+	if properties == nil {
+		properties = etree.NewElement("properties")
+		p.insertProjectChild(properties)
+	}
+
+	if elem := properties.SelectElement(name); elem != nil {
+		elem.SetText(value)
+		return nil
+	}
+
+	p.style.appendTextElement(properties, name, value)
+	return nil
+}
+
+// RemoveProperty removes a property from <properties>, and <properties> once it is empty.
+func (p *PomRepository) RemoveProperty(name string) error {
+	if p.doc == nil {
+		return fmt.Errorf("no pom.xml loaded")
+	}
+
+	var elem *etree.Element
+	properties := p.doc.Root().SelectElement("properties")
+	if properties != nil {
+		elem = properties.SelectElement(name)
+	}
+	if elem == nil {
+		return &domain.NotFoundError{Kind: "property", Name: name}
+	}
+
+	removeChild(elem)
+	if len(properties.ChildElements()) == 0 {
+		removeChild(properties)
+	}
+	return nil
+}
+
+// GetPropertyDeclarations returns the entries of <properties> in document order.
+func (p *PomRepository) GetPropertyDeclarations() ([]*domain.Property, error) {
+	if p.doc == nil {
+		return nil, fmt.Errorf("no pom.xml loaded")
+	}
+
+	result := []*domain.Property{}
+	if properties := p.doc.Root().SelectElement("properties"); properties != nil {
+		for _, elem := range properties.ChildElements() {
+			result = append(result, &domain.Property{
+				Name:     elem.Tag,
+				Value:    strings.TrimSpace(elem.Text()),
+				Position: p.position(elem),
+			})
+		}
+	}
+	return result, nil
+}
+
+// position returns where elem starts in the file as last loaded or saved. Elements
+// added since then, and documents that could not be indexed, have no position.
+func (p *PomRepository) position(elem *etree.Element) domain.Position {
+	if p.source == nil {
+		return domain.Position{}
+	}
+	sp, ok := p.source.spans[elem]
+	if !ok {
+		return domain.Position{}
+	}
+
+	before := p.source.data[:sp.start]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return domain.Position{
+		Line:   bytes.Count(before, []byte("\n")) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
@@ -0,0 +1,190 @@
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// span is the byte range a token occupied in the original document.
+// For elements, the start and end tags are tracked separately so that an element
+// whose children changed can still be written with its original tags.
+type span struct {
+	start, end int
+
+	// startTagEnd and endTagStart delimit the content of an element
+	startTagEnd, endTagStart int
+}
+
+// selfClosing reports whether the element was written as <tag/>.
+func (s span) selfClosing() bool {
+	return s.endTagStart == s.end
+}
+
+// source is the original text of a loaded document, indexed by token.
+//
+// Tokens are written back byte-for-byte unless they changed since Load, so
+// saving an unmodified document reproduces the input exactly and an edit only
+// touches the bytes of the nodes it affects.
+type source struct {
+	data  []byte
+	spans map[etree.Token]span
+
+	// pristine holds the serialization of each token at load time; for elements
+	// startTags holds the serialization of the start tag alone
+	pristine  map[etree.Token]string
+	startTags map[*etree.Element]string
+}
+
+// newSource indexes the tokens of doc against data, the bytes doc was parsed from.
+// Returns nil if the two cannot be matched, in which case callers fall back to a
+// plain serialization.
+func newSource(data []byte, doc *etree.Document) *source {
+	s := &source{
+		data:      data,
+		spans:     make(map[etree.Token]span),
+		pristine:  make(map[etree.Token]string),
+		startTags: make(map[*etree.Element]string),
+	}
+
+	// Flatten the tree into the order the decoder reports tokens.
+	// A nil token marks the end tag of the element on top of the stack.
+	var events []etree.Token
+	var flatten func(e *etree.Element)
+	flatten = func(e *etree.Element) {
+		for _, child := range e.Child {
+			events = append(events, child)
+			if elem, ok := child.(*etree.Element); ok {
+				flatten(elem)
+				events = append(events, nil)
+			}
+		}
+	}
+	flatten(&doc.Element)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var open []*etree.Element
+	for i := 0; ; i++ {
+		start := int(decoder.InputOffset())
+		tok, err := decoder.RawToken()
+		if err == io.EOF {
+			if i != len(events) {
+				return nil
+			}
+			break
+		}
+		if err != nil || i >= len(events) {
+			return nil
+		}
+		end := int(decoder.InputOffset())
+
+		switch tok.(type) {
+		case xml.StartElement:
+			elem, ok := events[i].(*etree.Element)
+			if !ok {
+				return nil
+			}
+			s.spans[elem] = span{start: start, startTagEnd: end}
+			open = append(open, elem)
+		case xml.EndElement:
+			if events[i] != nil || len(open) == 0 {
+				return nil
+			}
+			elem := open[len(open)-1]
+			open = open[:len(open)-1]
+			sp := s.spans[elem]
+			sp.endTagStart, sp.end = start, end
+			s.spans[elem] = sp
+		default:
+			if events[i] == nil {
+				return nil
+			}
+			if _, ok := events[i].(*etree.Element); ok {
+				return nil
+			}
+			s.spans[events[i]] = span{start: start, end: end}
+		}
+	}
+
+	for tok := range s.spans {
+		s.pristine[tok] = serialize(tok)
+		if elem, ok := tok.(*etree.Element); ok {
+			s.startTags[elem] = serializeStartTag(elem)
+		}
+	}
+
+	return s
+}
+
+// render writes the document, reusing the original bytes of every unchanged token.
+func (s *source) render(doc *etree.Document) []byte {
+	var buf bytes.Buffer
+	for _, child := range doc.Child {
+		s.writeToken(&buf, child)
+	}
+	return buf.Bytes()
+}
+
+// writeToken writes a single token and, for elements, its subtree.
+func (s *source) writeToken(buf *bytes.Buffer, tok etree.Token) {
+	sp, known := s.spans[tok]
+
+	if known && serialize(tok) == s.pristine[tok] {
+		buf.Write(s.data[sp.start:sp.end])
+		return
+	}
+
+	elem, ok := tok.(*etree.Element)
+	if !ok {
+		tok.WriteTo(buf, &etree.WriteSettings{})
+		return
+	}
+
+	// The element changed: keep its original tags if possible and recurse into the children
+	if known && !sp.selfClosing() && serializeStartTag(elem) == s.startTags[elem] && len(elem.Child) > 0 {
+		buf.Write(s.data[sp.start:sp.startTagEnd])
+		for _, child := range elem.Child {
+			s.writeToken(buf, child)
+		}
+		buf.Write(s.data[sp.endTagStart:sp.end])
+		return
+	}
+
+	if len(elem.Child) == 0 {
+		tok.WriteTo(buf, &etree.WriteSettings{})
+		return
+	}
+
+	buf.WriteString(serializeStartTag(elem))
+	for _, child := range elem.Child {
+		s.writeToken(buf, child)
+	}
+	buf.WriteString("</" + elem.FullTag() + ">")
+}
+
+// serialize returns the etree serialization of a token.
+func serialize(tok etree.Token) string {
+	var b strings.Builder
+	tok.WriteTo(&b, &etree.WriteSettings{})
+	return b.String()
+}
+
+// serializeStartTag returns the start tag of an element, including its attributes.
+func serializeStartTag(elem *etree.Element) string {
+	var b strings.Builder
+	b.WriteString("<" + elem.FullTag())
+	for _, attr := range elem.Attr {
+		b.WriteString(" ")
+		attr.WriteTo(&b, &etree.WriteSettings{})
+	}
+	b.WriteString(">")
+	return b.String()
+}